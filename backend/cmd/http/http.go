@@ -6,7 +6,10 @@ import (
 
 	"gitlab.com/project-quiz/config"
 	"gitlab.com/project-quiz/database"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/rbac"
 	h "gitlab.com/project-quiz/internal/server/http"
+	"gitlab.com/project-quiz/internal/worker"
 	mail "gitlab.com/project-quiz/utils/mailer"
 	"gitlab.com/project-quiz/utils/minio"
 
@@ -44,6 +47,19 @@ func StartServer(ctx context.Context, port int) {
 	defer sentry.Flush(2 * time.Second)
 	sentry.CaptureMessage("It works!")
 
+	// Background workers, stopped together with the server
+	workerCfg := config.NewWorkerConfig().Load()
+	go worker.NewAttemptExpiryWorker(db, minio, workerCfg.AttemptExpiryInterval).Run(ctx)
+	go worker.NewLeaderboardWorker(db, workerCfg.LeaderboardInterval).Run(ctx)
+
+	streakCfg := config.NewStreakConfig().Load()
+
+	enforcer, err := rbac.NewEnforcer(db)
+	if err != nil {
+		logrus.Fatalf("rbac.NewEnforcer: %s", err)
+	}
+	go worker.NewPolicyReloadWorker(enforcer, workerCfg.PolicyReloadInterval).Run(ctx)
+
 	ht := h.NewServer(&h.HttpServerCfg{
 		DB:             db,
 		SMTP:           *smtp,
@@ -51,6 +67,11 @@ func StartServer(ctx context.Context, port int) {
 		Secret:         secretKey.Key,
 		AesSecret:      secretKey.AesKey,
 		GoogleClientID: oauth.GoogleClientID,
+		StreakMilestones: entities.StreakMilestones{
+			Answer: streakCfg.AnswerMilestones,
+			Daily:  streakCfg.DailyMilestones,
+		},
+		Enforcer: enforcer,
 	})
 	defer ht.Done()
 	ht.Run(ctx, port)
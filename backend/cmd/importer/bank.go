@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/project-quiz/config"
+	"gitlab.com/project-quiz/database"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/params/generics"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/interchange"
+	"gitlab.com/project-quiz/utils/minio"
+
+	"github.com/spf13/cobra"
+)
+
+var ExportCmd = &cobra.Command{
+	Use:   "export [COMMANDS]",
+	Short: "Export data to files",
+}
+
+var importBankCmd = &cobra.Command{
+	Use:   "bank [FILE]",
+	Short: "Import questions from QTI 2.1 package, Moodle XML or GIFT",
+	Long:  "Import questions from QTI 2.1 package, Moodle XML or GIFT. Images are uploaded to the storage and nothing is imported when a question is invalid.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		materialID, _ := cmd.Flags().GetInt("material")
+		contributorID, _ := cmd.Flags().GetInt("contributor")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		active, _ := cmd.Flags().GetBool("active")
+
+		content, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp := bankUsecase().ImportBank(params.QuestionBankImportParam{
+			Format:        format,
+			FileName:      filepath.Base(args[0]),
+			MaterialID:    materialID,
+			ContributorID: contributorID,
+			IsAdmin:       active,
+			DryRun:        dryRun,
+		}, content)
+
+		report, _ := json.MarshalIndent(resp, "", "  ")
+		fmt.Println(string(report))
+
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("import failed")
+		}
+
+		return nil
+	},
+}
+
+var exportQuestionsCmd = &cobra.Command{
+	Use:   "questions",
+	Short: "Export questions as QTI 2.1 package, Moodle XML or GIFT",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		materialID, _ := cmd.Flags().GetInt("material")
+		show, _ := cmd.Flags().GetString("show")
+		output, _ := cmd.Flags().GetString("output")
+
+		resp := bankUsecase().Export(params.QuestionExportParam{
+			Format:     format,
+			MaterialID: materialID,
+			Show:       show,
+		})
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("export failed: %v", resp.Errors)
+		}
+
+		file := resp.Data.(generics.FileResponse)
+		if output == "" {
+			output = file.Name
+		}
+		if err := os.WriteFile(output, file.Content, 0644); err != nil {
+			return err
+		}
+
+		fmt.Printf("questions exported to %s\n", output)
+		if skipped := file.Headers["X-Skipped-Questions"]; skipped != "" {
+			fmt.Printf("skipped questions not supported by %s: %s\n", format, skipped)
+		}
+
+		return nil
+	},
+}
+
+// bankUsecase builds question usecase with database and storage, the same way as the http server
+func bankUsecase() usecase.QuestionUsecase {
+	dbConfig := config.NewDbConfig().Load().Get()
+	db := database.NewSqlDB(dbConfig.Driver, dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Database).ORM()
+
+	minioConfig := config.NewMinioCfg().Load()
+	storage := minio.NewMinioStorage(minioConfig.Endpoint, minioConfig.AccessKeyID, minioConfig.SecretAccessKey, minioConfig.BucketName, minioConfig.UseSSL)
+
+	return usecase.NewQuestionUsecase(db, storage)
+}
+
+func init() {
+	formats := strings.Join(interchange.Formats, ", ")
+
+	importBankCmd.Flags().String("format", "", "format of the file: "+formats)
+	importBankCmd.Flags().Int("material", 0, "material ID of questions whose category is not a known material")
+	importBankCmd.Flags().Int("contributor", 0, "user ID recorded as contributor of the questions")
+	importBankCmd.Flags().Bool("dry-run", false, "validate the file without importing")
+	importBankCmd.Flags().Bool("active", false, "activate the imported questions right away")
+	importBankCmd.MarkFlagRequired("format")
+
+	exportQuestionsCmd.Flags().String("format", "", "format of the file: "+formats)
+	exportQuestionsCmd.Flags().Int("material", 0, "only export questions of the material")
+	exportQuestionsCmd.Flags().String("show", "", "only export active or inactive questions")
+	exportQuestionsCmd.Flags().StringP("output", "o", "", "output file, named after the format when empty")
+	exportQuestionsCmd.MarkFlagRequired("format")
+
+	ImportCmd.AddCommand(importBankCmd)
+	ExportCmd.AddCommand(exportQuestionsCmd)
+}
@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/project-quiz/config"
+	"gitlab.com/project-quiz/database"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+var ImportCmd = &cobra.Command{
+	Use:   "import [COMMANDS]",
+	Short: "Import data from files",
+}
+
+var importQuestionsCmd = &cobra.Command{
+	Use:   "questions [FILE]",
+	Short: "Import questions from CSV or XLSX spreadsheet",
+	Long:  "Import questions from CSV or XLSX spreadsheet. Every row is validated first and nothing is imported when a row is invalid.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contributorID, _ := cmd.Flags().GetInt("contributor")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		active, _ := cmd.Flags().GetBool("active")
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		dbConfig := config.NewDbConfig().Load().Get()
+		db := database.NewSqlDB(dbConfig.Driver, dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Database).ORM()
+
+		resp := usecase.NewQuestionUsecase(db, nil).Import(params.QuestionImportParam{
+			FileName:      filepath.Base(args[0]),
+			ContributorID: contributorID,
+			IsAdmin:       active,
+			DryRun:        dryRun,
+		}, file)
+
+		report, _ := json.MarshalIndent(resp, "", "  ")
+		fmt.Println(string(report))
+
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("import failed")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	importQuestionsCmd.Flags().Int("contributor", 0, "user ID recorded as contributor of the questions")
+	importQuestionsCmd.Flags().Bool("dry-run", false, "validate the spreadsheet without importing")
+	importQuestionsCmd.Flags().Bool("active", false, "activate the imported questions right away")
+
+	ImportCmd.AddCommand(importQuestionsCmd)
+}
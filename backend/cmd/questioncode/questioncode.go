@@ -0,0 +1,77 @@
+package questioncode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gitlab.com/project-quiz/config"
+	"gitlab.com/project-quiz/database"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+var QuestionCodeCmd = &cobra.Command{
+	Use:   "question-code [COMMANDS]",
+	Short: "Maintain question codes",
+}
+
+var resequenceCmd = &cobra.Command{
+	Use:   "resequence",
+	Short: "Renumber question codes from 1 per prefix of their material",
+	Long:  "Renumber question codes from 1 per prefix of their material, ordered by question ID. Creating questions waits while the codes are renumbered, reading them does not.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		resp := codeUsecase().Resequence(params.QuestionCodeResequenceParam{DryRun: dryRun})
+		return printReport(resp)
+	},
+}
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Give codes to questions without one",
+	Long:  "Move code counters after the highest code in use and give codes to questions without one, batch by batch without blocking the server.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		batchSize, _ := cmd.Flags().GetInt("batch")
+		uniqueIndex, _ := cmd.Flags().GetBool("unique-index")
+
+		resp := codeUsecase().Backfill(params.QuestionCodeBackfillParam{
+			DryRun:      dryRun,
+			BatchSize:   batchSize,
+			UniqueIndex: uniqueIndex,
+		})
+		return printReport(resp)
+	},
+}
+
+func codeUsecase() usecase.QuestionCodeUsecase {
+	dbConfig := config.NewDbConfig().Load().Get()
+	db := database.NewSqlDB(dbConfig.Driver, dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Database).ORM()
+
+	return usecase.NewQuestionCodeUsecase(db)
+}
+
+func printReport(resp appctx.Response) error {
+	report, _ := json.MarshalIndent(resp, "", "  ")
+	fmt.Println(string(report))
+
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("question code maintenance failed")
+	}
+
+	return nil
+}
+
+func init() {
+	resequenceCmd.Flags().Bool("dry-run", false, "only report codes which would change")
+
+	backfillCmd.Flags().Bool("dry-run", false, "only count questions without code")
+	backfillCmd.Flags().Int("batch", 500, "number of questions given a code in one transaction")
+	backfillCmd.Flags().Bool("unique-index", false, "create unique index on question code when there is no duplicate left")
+
+	QuestionCodeCmd.AddCommand(resequenceCmd, backfillCmd)
+}
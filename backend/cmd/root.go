@@ -7,7 +7,9 @@ import (
 	"os/signal"
 	"syscall"
 	"gitlab.com/project-quiz/cmd/http"
+	"gitlab.com/project-quiz/cmd/importer"
 	"gitlab.com/project-quiz/cmd/migration"
+	"gitlab.com/project-quiz/cmd/questioncode"
 	"gitlab.com/project-quiz/cmd/stub"
 
 	"github.com/spf13/cobra"
@@ -35,7 +37,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 	ctx, cancel := context.WithCancel(context.Background())
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-quit
@@ -84,6 +86,9 @@ func init() {
 	rootCmd.AddCommand(migration.MigrationCmd)
 	rootCmd.AddCommand(migration.SeederCmd)
 	rootCmd.AddCommand(stub.TemplateCmd)
+	rootCmd.AddCommand(importer.ImportCmd)
+	rootCmd.AddCommand(importer.ExportCmd)
+	rootCmd.AddCommand(questioncode.QuestionCodeCmd)
 }
 
 func initConfig() {
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Streak struct {
+	// Bonus points by the length of consecutive correct answers
+	AnswerMilestones map[int]int
+	// Bonus points by the length of consecutive active days
+	DailyMilestones map[int]int
+}
+
+type StreakConfig interface {
+	Load() *Streak
+}
+
+func NewStreakConfig() StreakConfig {
+	return &Streak{}
+}
+
+func (s *Streak) Load() *Streak {
+	s.AnswerMilestones = milestonesFromEnv("STREAK_ANSWER_MILESTONES", "5:5,10:15,25:50")
+	s.DailyMilestones = milestonesFromEnv("STREAK_DAILY_MILESTONES", "3:5,7:20,30:100")
+	return s
+}
+
+// milestonesFromEnv read comma separated length:point pairs, fallback when empty. Invalid pairs are skipped.
+func milestonesFromEnv(key, fallback string) map[int]int {
+	value := os.Getenv(key)
+	if value == "" {
+		value = fallback
+	}
+
+	milestones := map[int]int{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 {
+			logrus.Warnf("[Streak Config] %s: invalid milestone %q", key, pair)
+			continue
+		}
+
+		length, errLength := strconv.Atoi(parts[0])
+		point, errPoint := strconv.Atoi(parts[1])
+		if errLength != nil || errPoint != nil || length <= 0 || point <= 0 {
+			logrus.Warnf("[Streak Config] %s: invalid milestone %q", key, pair)
+			continue
+		}
+
+		milestones[length] = point
+	}
+
+	return milestones
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type Worker struct {
+	AttemptExpiryInterval time.Duration
+	LeaderboardInterval   time.Duration
+	PolicyReloadInterval  time.Duration
+}
+
+type WorkerConfig interface {
+	Load() *Worker
+}
+
+func NewWorkerConfig() WorkerConfig {
+	return &Worker{}
+}
+
+func (w *Worker) Load() *Worker {
+	w.AttemptExpiryInterval = durationFromEnv("WORKER_ATTEMPT_EXPIRY_INTERVAL", time.Minute)
+	w.LeaderboardInterval = durationFromEnv("WORKER_LEADERBOARD_INTERVAL", 5*time.Minute)
+	w.PolicyReloadInterval = durationFromEnv("WORKER_POLICY_RELOAD_INTERVAL", 30*time.Second)
+	return w
+}
+
+// durationFromEnv read duration in seconds from env, fallback when empty or invalid
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS question_packs
+    ADD COLUMN IF NOT EXISTS correct_point REAL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS wrong_point REAL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS blank_point REAL DEFAULT 0;
+
+ALTER TABLE IF EXISTS question_pack_attempts
+    ADD COLUMN IF NOT EXISTS max_score REAL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS question_pack_attempt_scores (
+    id SERIAL PRIMARY KEY,
+    question_pack_attempt_id INT NOT NULL,
+    question_id INT NOT NULL,
+    question_option_id INT,
+    status VARCHAR(16) NOT NULL,
+    point REAL NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITHOUT TIME ZONE,
+    updated_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_pack_attempt_scores_attempt_id ON question_pack_attempt_scores (question_pack_attempt_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS question_pack_attempt_scores;
+
+ALTER TABLE IF EXISTS question_pack_attempts
+    DROP COLUMN IF EXISTS max_score;
+
+ALTER TABLE IF EXISTS question_packs
+    DROP COLUMN IF EXISTS correct_point,
+    DROP COLUMN IF EXISTS wrong_point,
+    DROP COLUMN IF EXISTS blank_point;
+-- +goose StatementEnd
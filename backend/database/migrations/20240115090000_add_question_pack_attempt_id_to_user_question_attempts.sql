@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS user_question_attempts
+    ADD COLUMN IF NOT EXISTS question_pack_attempt_id INT;
+
+CREATE INDEX IF NOT EXISTS idx_user_question_attempts_question_pack_attempt_id ON user_question_attempts (question_pack_attempt_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_question_attempts_question_pack_attempt_id;
+
+ALTER TABLE IF EXISTS user_question_attempts
+    DROP COLUMN IF EXISTS question_pack_attempt_id;
+-- +goose StatementEnd
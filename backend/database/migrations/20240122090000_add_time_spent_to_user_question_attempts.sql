@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS user_question_attempts
+    ADD COLUMN IF NOT EXISTS time_spent INT DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS user_question_attempts
+    DROP COLUMN IF EXISTS time_spent;
+-- +goose StatementEnd
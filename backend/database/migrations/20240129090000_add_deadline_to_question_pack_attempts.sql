@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS question_pack_attempts
+    ADD COLUMN IF NOT EXISTS deadline TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_question_pack_attempts_open_deadline ON question_pack_attempts (deadline) WHERE is_finish = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_question_pack_attempts_open_deadline;
+
+ALTER TABLE IF EXISTS question_pack_attempts
+    DROP COLUMN IF EXISTS deadline;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS question_packs
+    ADD COLUMN IF NOT EXISTS max_attempts INT DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cooldown INT DEFAULT 0;
+
+-- Close duplicated open attempts, keeping the latest one open
+UPDATE question_pack_attempts qpa
+SET is_finish = true, finished_at = NOW()
+WHERE qpa.is_finish = false
+AND EXISTS (
+    SELECT 1 FROM question_pack_attempts o
+    WHERE o.user_id = qpa.user_id
+    AND o.question_pack_id = qpa.question_pack_id
+    AND o.is_finish = false
+    AND o.id > qpa.id
+);
+
+-- Only one open attempt per user and question pack
+CREATE UNIQUE INDEX IF NOT EXISTS idx_question_pack_attempts_open_unique ON question_pack_attempts (user_id, question_pack_id) WHERE is_finish = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_question_pack_attempts_open_unique;
+
+ALTER TABLE IF EXISTS question_packs
+    DROP COLUMN IF EXISTS max_attempts,
+    DROP COLUMN IF EXISTS cooldown;
+-- +goose StatementEnd
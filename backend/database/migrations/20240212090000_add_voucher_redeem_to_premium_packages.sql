@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Package without user is an unredeemed voucher
+ALTER TABLE IF EXISTS premium_packages
+    ALTER COLUMN user_id DROP NOT NULL,
+    ALTER COLUMN active_until DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS redeemed_at TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_premium_packages_token_unique ON premium_packages (token);
+CREATE INDEX IF NOT EXISTS idx_premium_packages_user_id ON premium_packages (user_id);
+
+ALTER TABLE IF EXISTS question_solutions
+    ADD COLUMN IF NOT EXISTS is_premium BOOLEAN DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS question_solutions
+    DROP COLUMN IF EXISTS is_premium;
+
+DROP INDEX IF EXISTS idx_premium_packages_user_id;
+DROP INDEX IF EXISTS idx_premium_packages_token_unique;
+
+ALTER TABLE IF EXISTS premium_packages
+    DROP COLUMN IF EXISTS redeemed_at;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS voucher_batches (
+    id SERIAL PRIMARY KEY,
+    campaign VARCHAR(100) NOT NULL,
+    long_period INT NOT NULL,
+    quantity INT NOT NULL,
+    code_length INT NOT NULL,
+    alphabet VARCHAR(64) NOT NULL,
+    is_revoked BOOLEAN NOT NULL DEFAULT false,
+    revoked_at TIMESTAMP WITHOUT TIME ZONE,
+    created_at TIMESTAMP WITHOUT TIME ZONE,
+    updated_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+ALTER TABLE IF EXISTS premium_packages
+    ADD COLUMN IF NOT EXISTS voucher_batch_id INT REFERENCES voucher_batches (id),
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_premium_packages_voucher_batch_id ON premium_packages (voucher_batch_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_premium_packages_voucher_batch_id;
+
+ALTER TABLE IF EXISTS premium_packages
+    DROP COLUMN IF EXISTS voucher_batch_id,
+    DROP COLUMN IF EXISTS revoked_at;
+
+DROP TABLE IF EXISTS voucher_batches;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS questions
+    ADD COLUMN IF NOT EXISTS type VARCHAR(32) NOT NULL DEFAULT 'single_choice',
+    ADD COLUMN IF NOT EXISTS scoring_mode VARCHAR(32) NOT NULL DEFAULT 'all_or_nothing',
+    ADD COLUMN IF NOT EXISTS numeric_answer DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS numeric_tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS accepted_answers JSONB,
+    ADD COLUMN IF NOT EXISTS case_sensitive BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE IF EXISTS question_options
+    ADD COLUMN IF NOT EXISTS position INT,
+    ADD COLUMN IF NOT EXISTS match_body TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE IF EXISTS user_question_attempts
+    ADD COLUMN IF NOT EXISTS question_option_ids JSONB,
+    ADD COLUMN IF NOT EXISTS answer_text TEXT,
+    ADD COLUMN IF NOT EXISTS answer_pairs JSONB,
+    ADD COLUMN IF NOT EXISTS credit REAL NOT NULL DEFAULT 0;
+
+-- Submitted correct answers of single choice questions have full credit
+UPDATE user_question_attempts SET credit = 1 WHERE attempt_value = true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS user_question_attempts
+    DROP COLUMN IF EXISTS question_option_ids,
+    DROP COLUMN IF EXISTS answer_text,
+    DROP COLUMN IF EXISTS answer_pairs,
+    DROP COLUMN IF EXISTS credit;
+
+ALTER TABLE IF EXISTS question_options
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS match_body;
+
+ALTER TABLE IF EXISTS questions
+    DROP COLUMN IF EXISTS type,
+    DROP COLUMN IF EXISTS scoring_mode,
+    DROP COLUMN IF EXISTS numeric_answer,
+    DROP COLUMN IF EXISTS numeric_tolerance,
+    DROP COLUMN IF EXISTS accepted_answers,
+    DROP COLUMN IF EXISTS case_sensitive;
+-- +goose StatementEnd
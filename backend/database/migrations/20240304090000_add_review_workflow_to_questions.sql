@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS questions
+    ADD COLUMN IF NOT EXISTS review_status VARCHAR(32) NOT NULL DEFAULT 'approved',
+    ADD COLUMN IF NOT EXISTS reviewer_id INT,
+    ADD COLUMN IF NOT EXISTS rejection_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS questions_review_status_idx ON questions (review_status);
+
+CREATE TABLE IF NOT EXISTS question_review_comments (
+    id SERIAL PRIMARY KEY,
+    question_id INT NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    field VARCHAR(64) NOT NULL DEFAULT '',
+    question_option_id INT,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS question_review_comments_question_id_idx ON question_review_comments (question_id);
+
+CREATE TABLE IF NOT EXISTS question_review_logs (
+    id SERIAL PRIMARY KEY,
+    question_id INT NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    actor_id INT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS question_review_logs_question_id_idx ON question_review_logs (question_id);
+
+-- Inactive contributor questions were waiting for an admin to activate them
+UPDATE questions SET review_status = 'submitted', submitted_at = updated_at
+WHERE COALESCE(contributor_id, 0) <> 0 AND is_active = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS question_review_logs;
+DROP TABLE IF EXISTS question_review_comments;
+
+DROP INDEX IF EXISTS questions_review_status_idx;
+
+ALTER TABLE IF EXISTS questions
+    DROP COLUMN IF EXISTS review_status,
+    DROP COLUMN IF EXISTS reviewer_id,
+    DROP COLUMN IF EXISTS rejection_reason,
+    DROP COLUMN IF EXISTS submitted_at,
+    DROP COLUMN IF EXISTS reviewed_at;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS question_revisions (
+    id SERIAL PRIMARY KEY,
+    question_id INT NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    actor_id INT NOT NULL DEFAULT 0,
+    action VARCHAR(32) NOT NULL,
+    rollback_of INT REFERENCES question_revisions (id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT question_revisions_question_id_revision_key UNIQUE (question_id, revision)
+);
+
+ALTER TABLE IF EXISTS questions
+    ADD COLUMN IF NOT EXISTS revision_id INT;
+
+ALTER TABLE IF EXISTS user_question_attempts
+    ADD COLUMN IF NOT EXISTS question_revision_id INT;
+
+CREATE INDEX IF NOT EXISTS user_question_attempts_question_revision_id_idx ON user_question_attempts (question_revision_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS user_question_attempts_question_revision_id_idx;
+
+ALTER TABLE IF EXISTS user_question_attempts
+    DROP COLUMN IF EXISTS question_revision_id;
+
+ALTER TABLE IF EXISTS questions
+    DROP COLUMN IF EXISTS revision_id;
+
+DROP TABLE IF EXISTS question_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS question_code_counters (
+    prefix VARCHAR(16) PRIMARY KEY,
+    last_value BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE IF EXISTS materials
+    ADD COLUMN IF NOT EXISTS code_prefix VARCHAR(16) NOT NULL DEFAULT '';
+
+-- Counters continue after the highest code in use so existing codes are never given again
+INSERT INTO question_code_counters (prefix, last_value)
+SELECT substring(code FROM '^([a-z]+)[0-9]+$'), MAX(substring(code FROM '^[a-z]+([0-9]+)$')::BIGINT)
+FROM questions
+WHERE code ~ '^[a-z]+[0-9]+$'
+GROUP BY 1
+ON CONFLICT (prefix) DO UPDATE SET last_value = GREATEST(question_code_counters.last_value, EXCLUDED.last_value);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS materials
+    DROP COLUMN IF EXISTS code_prefix;
+
+DROP TABLE IF EXISTS question_code_counters;
+-- +goose StatementEnd
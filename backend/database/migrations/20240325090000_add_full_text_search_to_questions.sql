@@ -0,0 +1,108 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS questions
+    ADD COLUMN IF NOT EXISTS search_document TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+CREATE INDEX IF NOT EXISTS questions_search_vector_idx ON questions USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Body weighs the most, then tags, options and solution. Every text is stemmed in Indonesian and English.
+CREATE OR REPLACE FUNCTION question_search_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    v_options TEXT;
+    v_tags TEXT;
+    v_solution TEXT;
+BEGIN
+    SELECT COALESCE(string_agg(concat_ws(' ', o.body, NULLIF(o.match_body, '')), ' ' ORDER BY o.id), '') INTO v_options
+    FROM question_options AS o WHERE o.question_id = NEW.id;
+
+    SELECT COALESCE(string_agg(t.name, ' ' ORDER BY t.id), '') INTO v_tags
+    FROM question_tags AS qt INNER JOIN tags AS t ON t.id = qt.tag_id WHERE qt.question_id = NEW.id;
+
+    SELECT COALESCE(string_agg(s.solution_text, ' ' ORDER BY s.id), '') INTO v_solution
+    FROM question_solutions AS s WHERE s.question_id = NEW.id;
+
+    NEW.search_document := concat_ws(' … ', NULLIF(NEW.body, ''), NULLIF(v_options, ''), NULLIF(v_tags, ''), NULLIF(v_solution, ''));
+    NEW.search_vector :=
+        setweight(to_tsvector('indonesian', COALESCE(NEW.body, '')), 'A') || setweight(to_tsvector('english', COALESCE(NEW.body, '')), 'A') ||
+        setweight(to_tsvector('indonesian', v_tags), 'B') || setweight(to_tsvector('english', v_tags), 'B') ||
+        setweight(to_tsvector('indonesian', v_options), 'C') || setweight(to_tsvector('english', v_options), 'C') ||
+        setweight(to_tsvector('indonesian', v_solution), 'D') || setweight(to_tsvector('english', v_solution), 'D');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Changes of options, tags and solutions refresh the search vector of their question
+CREATE OR REPLACE FUNCTION question_search_touch() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE questions SET body = body WHERE id = OLD.question_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE questions SET body = body WHERE id = NEW.question_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION question_search_touch_tag() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE questions SET body = body WHERE id IN (SELECT question_id FROM question_tags WHERE tag_id = NEW.id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS questions_search_refresh ON questions;
+CREATE TRIGGER questions_search_refresh BEFORE INSERT OR UPDATE OF body ON questions
+    FOR EACH ROW EXECUTE FUNCTION question_search_refresh();
+
+DROP TRIGGER IF EXISTS question_options_search_touch ON question_options;
+CREATE TRIGGER question_options_search_touch AFTER INSERT OR UPDATE OR DELETE ON question_options
+    FOR EACH ROW EXECUTE FUNCTION question_search_touch();
+
+DROP TRIGGER IF EXISTS question_tags_search_touch ON question_tags;
+CREATE TRIGGER question_tags_search_touch AFTER INSERT OR UPDATE OR DELETE ON question_tags
+    FOR EACH ROW EXECUTE FUNCTION question_search_touch();
+
+DROP TRIGGER IF EXISTS question_solutions_search_touch ON question_solutions;
+CREATE TRIGGER question_solutions_search_touch AFTER INSERT OR UPDATE OR DELETE ON question_solutions
+    FOR EACH ROW EXECUTE FUNCTION question_search_touch();
+
+DROP TRIGGER IF EXISTS tags_search_touch ON tags;
+CREATE TRIGGER tags_search_touch AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION question_search_touch_tag();
+
+-- Index questions which already exist
+UPDATE questions SET body = body;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS tags_search_touch ON tags;
+DROP TRIGGER IF EXISTS question_solutions_search_touch ON question_solutions;
+DROP TRIGGER IF EXISTS question_tags_search_touch ON question_tags;
+DROP TRIGGER IF EXISTS question_options_search_touch ON question_options;
+DROP TRIGGER IF EXISTS questions_search_refresh ON questions;
+
+DROP FUNCTION IF EXISTS question_search_touch_tag();
+DROP FUNCTION IF EXISTS question_search_touch();
+DROP FUNCTION IF EXISTS question_search_refresh();
+
+DROP INDEX IF EXISTS questions_search_vector_idx;
+
+ALTER TABLE IF EXISTS questions
+    DROP COLUMN IF EXISTS search_document,
+    DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
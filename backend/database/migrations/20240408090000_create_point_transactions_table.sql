@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Merge balances split over duplicate rows before user_id becomes unique
+UPDATE user_points AS up
+SET point = d.total
+FROM (
+    SELECT MIN(id) AS id, SUM(point) AS total FROM user_points GROUP BY user_id HAVING COUNT(*) > 1
+) AS d
+WHERE up.id = d.id;
+
+DELETE FROM user_points AS up
+USING user_points AS keep
+WHERE up.user_id = keep.user_id AND up.id > keep.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_points_user_id_key ON user_points (user_id);
+
+CREATE TABLE IF NOT EXISTS point_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    amount INT NOT NULL,
+    balance INT NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    source_type VARCHAR(64) NOT NULL DEFAULT '',
+    source_id INT,
+    note TEXT NOT NULL DEFAULT '',
+    actor_id INT,
+    idempotency_key VARCHAR(128),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS point_transactions_idempotency_key_key ON point_transactions (idempotency_key);
+CREATE INDEX IF NOT EXISTS point_transactions_user_id_created_at_idx ON point_transactions (user_id, created_at DESC);
+
+-- Points earned before the ledger existed become the opening balance
+INSERT INTO point_transactions (user_id, amount, balance, reason, idempotency_key)
+SELECT user_id, point, point, 'opening_balance', 'opening_balance:' || user_id FROM user_points
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS point_transactions;
+
+DROP INDEX IF EXISTS user_points_user_id_key;
+-- +goose StatementEnd
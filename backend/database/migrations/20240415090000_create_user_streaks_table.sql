@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_streaks (
+    user_id INT PRIMARY KEY,
+    current_answer_streak INT NOT NULL DEFAULT 0,
+    best_answer_streak INT NOT NULL DEFAULT 0,
+    current_daily_streak INT NOT NULL DEFAULT 0,
+    best_daily_streak INT NOT NULL DEFAULT 0,
+    last_active_date DATE,
+    last_attempt_id INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_streaks;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS leaderboard_entries (
+    scope VARCHAR(32) NOT NULL,
+    scope_id INT NOT NULL DEFAULT 0,
+    period VARCHAR(16) NOT NULL,
+    user_id INT NOT NULL,
+    score NUMERIC NOT NULL DEFAULT 0,
+    rank INT NOT NULL,
+    refreshed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, scope_id, period, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS leaderboard_entries_rank_idx ON leaderboard_entries (scope, scope_id, period, rank, user_id);
+
+-- Material boards follow the attempt which earned the points
+CREATE INDEX IF NOT EXISTS point_transactions_source_idx ON point_transactions (source_type, source_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS point_transactions_source_idx;
+
+DROP TABLE IF EXISTS leaderboard_entries;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS casbin_rules (
+    id SERIAL PRIMARY KEY,
+    ptype VARCHAR(100) NOT NULL,
+    v0 VARCHAR(255) NOT NULL DEFAULT '',
+    v1 VARCHAR(255) NOT NULL DEFAULT '',
+    v2 VARCHAR(255) NOT NULL DEFAULT '',
+    v3 VARCHAR(255) NOT NULL DEFAULT '',
+    v4 VARCHAR(255) NOT NULL DEFAULT '',
+    v5 VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS casbin_rules_unique_idx ON casbin_rules (ptype, v0, v1, v2, v3, v4, v5);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS casbin_rules;
+-- +goose StatementEnd
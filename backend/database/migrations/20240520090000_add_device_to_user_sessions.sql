@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_sessions
+    ADD COLUMN IF NOT EXISTS login_method VARCHAR(20) NOT NULL DEFAULT 'password',
+    ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- One active basic auth session per device of the user
+CREATE UNIQUE INDEX IF NOT EXISTS user_sessions_basic_device_idx ON user_sessions (user_id, user_agent, ip_address)
+    WHERE login_method = 'basic' AND revoked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS user_sessions_basic_device_idx;
+
+ALTER TABLE user_sessions
+    DROP COLUMN IF EXISTS login_method,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS last_seen_at;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Correct answer points are keyed by user and question instead of attempt.
+-- The first reward of each question keeps the new key, so it is not given again.
+UPDATE point_transactions AS pt
+SET idempotency_key = 'correct_answer:question:' || r.question_id || ':user:' || r.user_id
+FROM (
+    SELECT pt.id, a.question_id, pt.user_id,
+        row_number() OVER (PARTITION BY pt.user_id, a.question_id ORDER BY pt.created_at, pt.id) AS rn
+    FROM point_transactions AS pt
+    JOIN user_question_attempts AS a ON pt.idempotency_key = 'correct_answer:user_question_attempt:' || a.id
+    WHERE pt.reason = 'correct_answer'
+) AS r
+WHERE pt.id = r.id AND r.rn = 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE point_transactions
+SET idempotency_key = 'correct_answer:user_question_attempt:' || source_id
+WHERE reason = 'correct_answer' AND idempotency_key LIKE 'correct_answer:question:%' AND source_id IS NOT NULL;
+-- +goose StatementEnd
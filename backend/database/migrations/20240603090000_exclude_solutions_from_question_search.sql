@@ -0,0 +1,69 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Search is open to every student, so solutions, premium ones included, are not indexed.
+-- Match bodies are left out too, the document would show them next to the option they pair with.
+CREATE OR REPLACE FUNCTION question_search_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    v_options TEXT;
+    v_tags TEXT;
+BEGIN
+    SELECT COALESCE(string_agg(o.body, ' ' ORDER BY o.id), '') INTO v_options
+    FROM question_options AS o WHERE o.question_id = NEW.id;
+
+    SELECT COALESCE(string_agg(t.name, ' ' ORDER BY t.id), '') INTO v_tags
+    FROM question_tags AS qt INNER JOIN tags AS t ON t.id = qt.tag_id WHERE qt.question_id = NEW.id;
+
+    NEW.search_document := concat_ws(' … ', NULLIF(NEW.body, ''), NULLIF(v_options, ''), NULLIF(v_tags, ''));
+    NEW.search_vector :=
+        setweight(to_tsvector('indonesian', COALESCE(NEW.body, '')), 'A') || setweight(to_tsvector('english', COALESCE(NEW.body, '')), 'A') ||
+        setweight(to_tsvector('indonesian', v_tags), 'B') || setweight(to_tsvector('english', v_tags), 'B') ||
+        setweight(to_tsvector('indonesian', v_options), 'C') || setweight(to_tsvector('english', v_options), 'C');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS question_solutions_search_touch ON question_solutions;
+
+-- Drop solutions from the index of questions which already exist
+UPDATE questions SET body = body;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION question_search_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    v_options TEXT;
+    v_tags TEXT;
+    v_solution TEXT;
+BEGIN
+    SELECT COALESCE(string_agg(concat_ws(' ', o.body, NULLIF(o.match_body, '')), ' ' ORDER BY o.id), '') INTO v_options
+    FROM question_options AS o WHERE o.question_id = NEW.id;
+
+    SELECT COALESCE(string_agg(t.name, ' ' ORDER BY t.id), '') INTO v_tags
+    FROM question_tags AS qt INNER JOIN tags AS t ON t.id = qt.tag_id WHERE qt.question_id = NEW.id;
+
+    SELECT COALESCE(string_agg(s.solution_text, ' ' ORDER BY s.id), '') INTO v_solution
+    FROM question_solutions AS s WHERE s.question_id = NEW.id;
+
+    NEW.search_document := concat_ws(' … ', NULLIF(NEW.body, ''), NULLIF(v_options, ''), NULLIF(v_tags, ''), NULLIF(v_solution, ''));
+    NEW.search_vector :=
+        setweight(to_tsvector('indonesian', COALESCE(NEW.body, '')), 'A') || setweight(to_tsvector('english', COALESCE(NEW.body, '')), 'A') ||
+        setweight(to_tsvector('indonesian', v_tags), 'B') || setweight(to_tsvector('english', v_tags), 'B') ||
+        setweight(to_tsvector('indonesian', v_options), 'C') || setweight(to_tsvector('english', v_options), 'C') ||
+        setweight(to_tsvector('indonesian', v_solution), 'D') || setweight(to_tsvector('english', v_solution), 'D');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS question_solutions_search_touch ON question_solutions;
+CREATE TRIGGER question_solutions_search_touch AFTER INSERT OR UPDATE OR DELETE ON question_solutions
+    FOR EACH ROW EXECUTE FUNCTION question_search_touch();
+
+UPDATE questions SET body = body;
+-- +goose StatementEnd
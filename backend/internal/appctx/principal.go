@@ -0,0 +1,57 @@
+package appctx
+
+import "context"
+
+const EntitlementPremium = "premium"
+
+type principalKey struct{}
+
+// Principal is the authenticated user of the request, set by the authorization middleware
+type Principal struct {
+	UserID int
+	// Name of the roles of the user
+	Roles []string
+	// Access the user is entitled to besides the roles, such as premium.
+	// They are loaded by the routes which check them.
+	Entitlements []string
+	// Session and ID of the access token, empty when authenticated by basic auth
+	SessionID int
+	TokenID   string
+}
+
+// HasRole check whether the user has the role
+func (p Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+// HasEntitlement check whether the user is entitled to the access
+func (p Principal) HasEntitlement(entitlement string) bool {
+	return contains(p.Entitlements, entitlement)
+}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of ctx, false when the request is not authenticated
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// UserID returns ID of the authenticated user, 0 when the request is not authenticated
+func UserID(ctx context.Context) int {
+	principal, _ := PrincipalFrom(ctx)
+	return principal.UserID
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
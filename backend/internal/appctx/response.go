@@ -25,6 +25,9 @@ type MetaData struct {
 	Limit      int64 `json:"limit"`
 	TotalPage  int64 `json:"total_page"`
 	TotalCount int64 `json:"total_count"`
+	// Keyset pagination, next cursor is empty on the last page
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 func NewResponse() *Response {
@@ -91,6 +94,20 @@ func (r *Response) WithMeta(page int64, limit int64, totalCount int64) *Response
 	return r
 }
 
+// WithCursorMeta sets meta of keyset pagination, total is nil when it is not counted
+func (r *Response) WithCursorMeta(limit int64, cursor, nextCursor string, total *int64) *Response {
+	r.Meta = &MetaData{
+		Limit:      limit,
+		Cursor:     cursor,
+		NextCursor: nextCursor,
+	}
+	if total != nil {
+		r.Meta.TotalCount = *total
+		r.Meta.TotalPage = int64(math.Ceil(float64(*total) / float64(limit)))
+	}
+	return r
+}
+
 func (r *Response) WithMetaObj(meta MetaData) *Response {
 	r.Meta = &meta
 	return r
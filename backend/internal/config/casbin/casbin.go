@@ -0,0 +1,21 @@
+// Package casbin embeds the casbin models and the default policies into the binary
+package casbin
+
+import _ "embed"
+
+// RouteModel and RoutePolicy decide which routes need authentication
+var (
+	//go:embed route_model.conf
+	RouteModel string
+	//go:embed route_policy.csv
+	RoutePolicy string
+)
+
+// AuthModel decides which role may access a route, its policies are stored in the database.
+// AuthPolicy is the default policy the database is seeded with.
+var (
+	//go:embed auth_model.conf
+	AuthModel string
+	//go:embed policy.csv
+	AuthPolicy string
+)
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities/base"
+)
+
+const (
+	// Submitted answers, in a material when the badge has one
+	BadgeRuleAnswerCount = "answer_count"
+	// Correct answers, in a material when the badge has one
+	BadgeRuleCorrectAnswerCount = "correct_answer_count"
+	// Finished question packs scoring at least MinScore percent
+	BadgeRulePackFinishCount = "pack_finish_count"
+	// Best run of correct answers
+	BadgeRuleAnswerStreak = "answer_streak"
+	// Best run of active days
+	BadgeRuleDailyStreak = "daily_streak"
+
+	BadgeEventAnswerSubmitted = "answer_submitted"
+	BadgeEventPackFinished    = "pack_finished"
+)
+
+// BadgeEventRules maps an event to the rules it may complete
+var BadgeEventRules = map[string][]string{
+	BadgeEventAnswerSubmitted: {BadgeRuleAnswerCount, BadgeRuleCorrectAnswerCount, BadgeRuleAnswerStreak, BadgeRuleDailyStreak},
+	BadgeEventPackFinished:    {BadgeRulePackFinishCount},
+}
+
+// Badge is awarded once the progress of a user on its rule reaches the threshold
+type Badge struct {
+	ID          int    `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Path of the icon in storage, replaced by a temporary url when read
+	IconUrl    string  `json:"icon_url"`
+	RuleType   string  `json:"rule_type"`
+	Threshold  int     `json:"threshold"`
+	MaterialID *int    `json:"material_id"`
+	MinScore   float32 `json:"min_score"`
+	IsActive   bool    `json:"is_active"`
+	base.Timestamp
+}
+
+// UserBadge is a badge awarded to a user, a badge is awarded to the same user once
+type UserBadge struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id"`
+	BadgeID   int       `json:"badge_id"`
+	AwardedAt time.Time `json:"awarded_at"`
+	Badge     *Badge    `json:"badge,omitempty"`
+}
@@ -0,0 +1,36 @@
+package entities
+
+// CasbinRule is a casbin policy line, unused values are empty
+type CasbinRule struct {
+	ID    int    `json:"id" gorm:"primaryKey"`
+	Ptype string `json:"ptype"`
+	V0    string `json:"v0"`
+	V1    string `json:"v1"`
+	V2    string `json:"v2"`
+	V3    string `json:"v3"`
+	V4    string `json:"v4"`
+	V5    string `json:"v5"`
+}
+
+// Values of the rule without the trailing empty values
+func (c CasbinRule) Values() []string {
+	values := []string{c.V0, c.V1, c.V2, c.V3, c.V4, c.V5}
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+
+	return values
+}
+
+// NewCasbinRule creates a rule of the policy type from its values
+func NewCasbinRule(ptype string, values []string) CasbinRule {
+	rule := CasbinRule{Ptype: ptype}
+	fields := []*string{&rule.V0, &rule.V1, &rule.V2, &rule.V3, &rule.V4, &rule.V5}
+	for i, value := range values {
+		if i < len(fields) {
+			*fields[i] = value
+		}
+	}
+
+	return rule
+}
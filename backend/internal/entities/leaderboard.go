@@ -0,0 +1,56 @@
+package entities
+
+import "time"
+
+const (
+	LeaderboardScopeGlobal       = "global"
+	LeaderboardScopeMaterial     = "material"
+	LeaderboardScopeQuestionPack = "question_pack"
+
+	LeaderboardPeriodWeekly  = "weekly"
+	LeaderboardPeriodMonthly = "monthly"
+	LeaderboardPeriodAllTime = "all_time"
+)
+
+var LeaderboardPeriods = []string{LeaderboardPeriodWeekly, LeaderboardPeriodMonthly, LeaderboardPeriodAllTime}
+
+// LeaderboardEntry is the rank of a user in a snapshot of a leaderboard.
+// Global and material boards score earned points, question pack boards score the best finished attempt.
+type LeaderboardEntry struct {
+	Scope string `json:"scope" gorm:"primaryKey"`
+	// Material or question pack, 0 on global board
+	ScopeID     int              `json:"scope_id" gorm:"primaryKey;autoIncrement:false"`
+	Period      string           `json:"period" gorm:"primaryKey"`
+	UserID      int              `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Score       float64          `json:"score"`
+	Rank        int              `json:"rank"`
+	RefreshedAt time.Time        `json:"refreshed_at"`
+	User        *LeaderboardUser `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// LeaderboardUser is what others can see of a user on a leaderboard
+type LeaderboardUser struct {
+	ID   int    `json:"id" gorm:"primaryKey"`
+	Name string `json:"name"`
+}
+
+func (LeaderboardUser) TableName() string {
+	return "users"
+}
+
+// LeaderboardPeriodStart is when the period containing now starts, weeks start on Monday.
+// All time starts at the zero time.
+func LeaderboardPeriodStart(period string, now time.Time) time.Time {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case LeaderboardPeriodWeekly:
+		// Sunday is the last day of the week
+		return today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case LeaderboardPeriodMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	}
+
+	return time.Time{}
+}
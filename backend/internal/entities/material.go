@@ -6,5 +6,7 @@ type Material struct {
 	ID    int    `json:"id" gorm:"primaryKey"`
 	Name  string `json:"name"`
 	Level string `json:"level"`
+	// Prefix of codes of questions in the material, kq when empty
+	CodePrefix string `json:"code_prefix"`
 	base.Timestamp
 }
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// Points earned before the ledger existed
+	PointReasonOpeningBalance = "opening_balance"
+	PointReasonCorrectAnswer  = "correct_answer"
+	PointReasonAdminGrant     = "admin_grant"
+	PointReasonAdminRevoke    = "admin_revoke"
+)
+
+const PointSourceAttempt = "user_question_attempt"
+
+// Points given for a correct answer
+const CorrectAnswerPoint = 3
+
+// PointTransaction is an append-only entry of the point ledger. The balance of UserPoint
+// is the sum of the amount of every transaction of the user.
+type PointTransaction struct {
+	ID     int `json:"id" gorm:"primaryKey"`
+	UserID int `json:"user_id"`
+	// Negative amount takes points away
+	Amount int `json:"amount"`
+	// Balance of the user after the transaction
+	Balance int    `json:"balance"`
+	Reason  string `json:"reason"`
+	// Entity which caused the transaction
+	SourceType string `json:"source_type"`
+	SourceID   *int   `json:"source_id"`
+	Note       string `json:"note"`
+	// Admin who granted or revoked the points
+	ActorID *int `json:"actor_id"`
+	// Transaction with a key which was applied before is not applied again
+	IdempotencyKey *string   `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PointIdempotencyKey identifies the transaction of a reason caused by a source entity
+func PointIdempotencyKey(reason, sourceType string, sourceID int) *string {
+	key := fmt.Sprintf("%s:%s:%d", reason, sourceType, sourceID)
+	return &key
+}
+
+// CorrectAnswerIdempotencyKey identifies the reward of the first correct answer of a question,
+// answering the question correctly again does not give points again
+func CorrectAnswerIdempotencyKey(questionID, userID int) *string {
+	key := fmt.Sprintf("%s:question:%d:user:%d", PointReasonCorrectAnswer, questionID, userID)
+	return &key
+}
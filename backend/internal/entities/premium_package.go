@@ -6,13 +6,23 @@ import (
 	"gitlab.com/project-quiz/internal/entities/base"
 )
 
+// PremiumPackage is a premium subscription of a user. Package without user is
+// an unredeemed voucher which can be bound to a user through its token.
 type PremiumPackage struct {
-	ID          int       `json:"id" gorm:"primaryKey"`
-	Token       string    `json:"token"`
-	IsActive    bool      `json:"is_active"`
-	ActiveUntil time.Time `json:"active_until"`
-	UserID      int       `json:"user_id"`
-	User        User      `json:"user"`
-	LongPeriod  int       `json:"long_period"`
+	ID             int        `json:"id" gorm:"primaryKey"`
+	Token          string     `json:"token"`
+	IsActive       bool       `json:"is_active"`
+	ActiveUntil    *time.Time `json:"active_until"`
+	UserID         *int       `json:"user_id"`
+	User           *User      `json:"user,omitempty"`
+	LongPeriod     int        `json:"long_period"` // in days
+	RedeemedAt     *time.Time `json:"redeemed_at"`
+	VoucherBatchID *int       `json:"voucher_batch_id"`
+	RevokedAt      *time.Time `json:"revoked_at"`
 	base.Timestamp
 }
+
+// IsEffective check whether the package gives premium access at the given time
+func (p PremiumPackage) IsEffective(now time.Time) bool {
+	return p.IsActive && p.UserID != nil && p.ActiveUntil != nil && p.ActiveUntil.After(now)
+}
@@ -1,11 +1,12 @@
 package entities
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/entities/base"
 	"gitlab.com/project-quiz/utils/minio"
+	"gitlab.com/project-quiz/utils/questioncode"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +23,24 @@ type Question struct {
 	QuestionTags    []QuestionTag    `json:"tags" gorm:"many2many:question_tags;foreginKey:ID;joinForeignKey:QuestionID;references:ID;joinReferences:TagID"`
 	ImgPlacementUrl string           `json:"img_placement_url"`
 	ContributorID   int              `json:"contributor_id"`
+	Type            string           `json:"type" gorm:"default:single_choice"`
+	ScoringMode     string           `json:"scoring_mode" gorm:"default:all_or_nothing"`
+	// Answer key of numeric question
+	NumericAnswer    *float64 `json:"numeric_answer,omitempty"`
+	NumericTolerance float64  `json:"numeric_tolerance"`
+	// Answer key of short text question
+	AcceptedAnswers []string `json:"accepted_answers,omitempty" gorm:"serializer:json"`
+	CaseSensitive   bool     `json:"case_sensitive"`
+	// Review workflow of contributor question
+	ReviewStatus    string     `json:"review_status" gorm:"default:approved"`
+	ReviewerID      *int       `json:"reviewer_id"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	// Current revision of the question content
+	RevisionID *int `json:"revision_id"`
+	// Highlighted fragments matching the search keyword
+	Snippet string `json:"snippet,omitempty" gorm:"->;-:migration"`
 	base.Timestamp
 }
 
@@ -34,6 +53,8 @@ type QuestionAdminList struct {
 	IsActive      *bool  `json:"is_active" gorm:"default:true"`
 	Code          string `json:"code"`
 	ContributorID int    `json:"contributor_id"`
+	ReviewStatus  string `json:"review_status"`
+	Snippet       string `json:"snippet,omitempty"`
 	base.Timestamp
 }
 
@@ -50,9 +71,21 @@ func (q *Question) GenerateTempStorageUrl(m minio.MinioStorageContract) {
 }
 
 func (q *Question) BeforeCreate(tx *gorm.DB) (err error) {
-	var countQuestion int64
-	tx.Debug().Model(q).Select("id").Count(&countQuestion)
-	questionCode := fmt.Sprintf("kq%v", countQuestion+1)
+	db := tx.Session(&gorm.Session{NewDB: true})
+
+	// Code prefix is configured per material
+	var prefix string
+	if q.MaterialID != 0 {
+		if err := db.Model(&Material{}).Select("code_prefix").Where("id = ?", q.MaterialID).Scan(&prefix).Error; err != nil {
+			return err
+		}
+	}
+
+	questionCode, err := questioncode.Next(db, prefix)
+	if err != nil {
+		return err
+	}
+
 	q.Code = questionCode
 	tx.Statement.SetColumn("code", questionCode)
 	return nil
@@ -13,6 +13,10 @@ type QuestionOption struct {
 	IsImage     bool   `json:"is_image"`
 	ImgPath     string `json:"img_path"`
 	QuestionID  int    `json:"question_id"`
+	// Correct position of the option on ordering question
+	Position *int `json:"position,omitempty"`
+	// Pair of the option on matching question
+	MatchBody string `json:"match_body,omitempty"`
 	base.Timestamp
 }
 
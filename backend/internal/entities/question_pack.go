@@ -1,13 +1,40 @@
 package entities
 
-import "gitlab.com/project-quiz/internal/entities/base"
+import (
+	"gitlab.com/project-quiz/internal/entities/base"
+	"gitlab.com/project-quiz/utils/scoring"
+)
 
 type QuestionPack struct {
-	ID        int        `json:"id" gorm:"primaryKey"`
-	Name      string     `json:"name"`
-	Questions []Question `json:"questions" gorm:"many2many:question_pack_items;foreginKey:ID;joinForeignKey:QuestionPackID;references:ID;joinReferences:QuestionID"`
-	IsFree    *bool      `json:"is_free" gorm:"default:false"`
-	IsActive  *bool      `json:"is_active" gorm:"default:true"`
-	TimeLimit int        `json:"time_limit"`
+	ID           int        `json:"id" gorm:"primaryKey"`
+	Name         string     `json:"name"`
+	Questions    []Question `json:"questions" gorm:"many2many:question_pack_items;foreginKey:ID;joinForeignKey:QuestionPackID;references:ID;joinReferences:QuestionID"`
+	IsFree       *bool      `json:"is_free" gorm:"default:false"`
+	IsActive     *bool      `json:"is_active" gorm:"default:true"`
+	TimeLimit    int        `json:"time_limit"` // in minutes, 0 means unlimited
+	CorrectPoint *float32   `json:"correct_point" gorm:"default:1"`
+	WrongPoint   *float32   `json:"wrong_point" gorm:"default:0"`
+	BlankPoint   *float32   `json:"blank_point" gorm:"default:0"`
+	MaxAttempts  *int       `json:"max_attempts" gorm:"default:0"` // 0 means unlimited
+	Cooldown     *int       `json:"cooldown" gorm:"default:0"`     // in minutes between finished attempt and the next one
 	base.Timestamp
 }
+
+// ScoringRule returns the scoring rule used to score attempts of the pack
+func (q QuestionPack) ScoringRule() scoring.Rule {
+	rule := scoring.Rule{Correct: 1}
+
+	if q.CorrectPoint != nil {
+		rule.Correct = *q.CorrectPoint
+	}
+
+	if q.WrongPoint != nil {
+		rule.Wrong = *q.WrongPoint
+	}
+
+	if q.BlankPoint != nil {
+		rule.Blank = *q.BlankPoint
+	}
+
+	return rule
+}
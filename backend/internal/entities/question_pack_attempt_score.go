@@ -0,0 +1,14 @@
+package entities
+
+import "gitlab.com/project-quiz/internal/entities/base"
+
+// Score breakdown of a single question inside a question pack attempt
+type QuestionPackAttemptScore struct {
+	ID                    int     `json:"id" gorm:"primaryKey"`
+	QuestionPackAttemptID int     `json:"question_pack_attempt_id"`
+	QuestionID            int     `json:"question_id"`
+	QuestionOptionID      *int    `json:"option_id"`
+	Status                string  `json:"status"`
+	Point                 float32 `json:"point"`
+	base.Timestamp
+}
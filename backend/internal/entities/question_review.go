@@ -0,0 +1,104 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities/base"
+)
+
+const (
+	ReviewStatusDraft     = "draft"
+	ReviewStatusSubmitted = "submitted"
+	ReviewStatusInReview  = "in_review"
+	ReviewStatusApproved  = "approved"
+	ReviewStatusRejected  = "rejected"
+
+	ReviewActionSubmit   = "submit"
+	ReviewActionWithdraw = "withdraw"
+	ReviewActionAssign   = "assign"
+	ReviewActionApprove  = "approve"
+	ReviewActionReject   = "reject"
+)
+
+var ErrInvalidReviewTransition = errors.New("invalid review transition")
+
+// reviewTransitions holds the statuses an action can start from and the status it leads to
+var reviewTransitions = map[string]struct {
+	from []string
+	to   string
+}{
+	ReviewActionSubmit:   {from: []string{ReviewStatusDraft, ReviewStatusRejected}, to: ReviewStatusSubmitted},
+	ReviewActionWithdraw: {from: []string{ReviewStatusSubmitted}, to: ReviewStatusDraft},
+	ReviewActionAssign:   {from: []string{ReviewStatusSubmitted, ReviewStatusInReview}, to: ReviewStatusInReview},
+	ReviewActionApprove:  {from: []string{ReviewStatusInReview}, to: ReviewStatusApproved},
+	ReviewActionReject:   {from: []string{ReviewStatusInReview}, to: ReviewStatusRejected},
+}
+
+// QuestionReviewComment is a reviewer note on the question, optionally pinned to a field or an option
+type QuestionReviewComment struct {
+	ID               int    `json:"id" gorm:"primaryKey"`
+	QuestionID       int    `json:"question_id"`
+	UserID           int    `json:"user_id"`
+	Field            string `json:"field,omitempty"`
+	QuestionOptionID *int   `json:"question_option_id,omitempty"`
+	Body             string `json:"body"`
+	base.Timestamp
+}
+
+// QuestionReviewLog records every review transition of a question
+type QuestionReviewLog struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	QuestionID int    `json:"question_id"`
+	ActorID    int    `json:"actor_id"`
+	Action     string `json:"action"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Note       string `json:"note,omitempty"`
+	base.Timestamp
+}
+
+type QuestionReviewQueueItem struct {
+	ID            int        `json:"id"`
+	Code          string     `json:"code"`
+	Body          string     `json:"body"`
+	Material      string     `json:"material"`
+	ContributorID int        `json:"contributor_id"`
+	ReviewStatus  string     `json:"review_status"`
+	ReviewerID    *int       `json:"reviewer_id"`
+	SubmittedAt   *time.Time `json:"submitted_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ReviewStatusOf returns the review status, questions created before the review workflow are approved
+func (q Question) ReviewStatusOf() string {
+	if q.ReviewStatus == "" {
+		return ReviewStatusApproved
+	}
+
+	return q.ReviewStatus
+}
+
+// NextReviewStatus returns the status the question moves to by the action
+func (q Question) NextReviewStatus(action string) (string, error) {
+	transition, ok := reviewTransitions[action]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown action %s", ErrInvalidReviewTransition, action)
+	}
+
+	current := q.ReviewStatusOf()
+	for _, from := range transition.from {
+		if current == from {
+			return transition.to, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: can not %s a question which is %s", ErrInvalidReviewTransition, action, current)
+}
+
+// IsEditableByContributor tells whether the contributor may still change the question
+func (q Question) IsEditableByContributor() bool {
+	status := q.ReviewStatusOf()
+	return status == ReviewStatusDraft || status == ReviewStatusRejected
+}
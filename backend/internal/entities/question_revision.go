@@ -0,0 +1,194 @@
+package entities
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gitlab.com/project-quiz/internal/entities/base"
+)
+
+const (
+	// Question as it was before the first recorded change
+	RevisionActionBaseline = "baseline"
+	RevisionActionCreate   = "create"
+	RevisionActionImport   = "import"
+	RevisionActionUpdate   = "update"
+	RevisionActionOption   = "option"
+	RevisionActionSolution = "solution"
+	RevisionActionRollback = "rollback"
+)
+
+// QuestionRevision is an immutable snapshot of a question with its options and solution
+type QuestionRevision struct {
+	ID         int `json:"id" gorm:"primaryKey"`
+	QuestionID int `json:"question_id"`
+	// Revision number, counted per question from 1
+	Revision int    `json:"revision"`
+	ActorID  int    `json:"actor_id"`
+	Action   string `json:"action"`
+	// Revision the question was rolled back to
+	RollbackOf *int             `json:"rollback_of,omitempty"`
+	Snapshot   QuestionSnapshot `json:"snapshot" gorm:"serializer:json"`
+	base.Timestamp
+}
+
+type QuestionSnapshot struct {
+	Body             string                    `json:"body"`
+	MaterialID       int                       `json:"material_id"`
+	IsImage          bool                      `json:"is_image"`
+	ImgPath          string                    `json:"img_path"`
+	ImgPlacementUrl  string                    `json:"img_placement_url"`
+	IsPackOnly       bool                      `json:"is_pack_only"`
+	Type             string                    `json:"type"`
+	ScoringMode      string                    `json:"scoring_mode"`
+	NumericAnswer    *float64                  `json:"numeric_answer"`
+	NumericTolerance float64                   `json:"numeric_tolerance"`
+	AcceptedAnswers  []string                  `json:"accepted_answers"`
+	CaseSensitive    bool                      `json:"case_sensitive"`
+	Options          []QuestionOptionSnapshot  `json:"options"`
+	Solution         *QuestionSolutionSnapshot `json:"solution"`
+}
+
+type QuestionOptionSnapshot struct {
+	ID          int    `json:"id"`
+	Body        string `json:"body"`
+	OptionValue bool   `json:"option_value"`
+	IsImage     bool   `json:"is_image"`
+	ImgPath     string `json:"img_path"`
+	Position    *int   `json:"position"`
+	MatchBody   string `json:"match_body"`
+}
+
+type QuestionSolutionSnapshot struct {
+	SolutionType   string `json:"solution_type"`
+	SolutionText   string `json:"solution_text"`
+	SolutionImgUrl string `json:"solution_img_url"`
+	PdfFileUrl     string `json:"pdf_file_url"`
+	Link           string `json:"link"`
+	IsPremium      bool   `json:"is_premium"`
+}
+
+// QuestionRevisionChange is a field which differs between two revisions.
+// From is nil when the field was added and To is nil when it was removed.
+type QuestionRevisionChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// NewQuestionSnapshot takes the content of question, its options and its solution which may be nil
+func NewQuestionSnapshot(question Question, options []QuestionOption, solution *QuestionSolution) QuestionSnapshot {
+	snapshot := QuestionSnapshot{
+		Body:             question.Body,
+		MaterialID:       question.MaterialID,
+		IsImage:          question.IsImage,
+		ImgPath:          question.ImgPath,
+		ImgPlacementUrl:  question.ImgPlacementUrl,
+		IsPackOnly:       question.IsPackOnly != nil && *question.IsPackOnly,
+		Type:             question.QuestionType(),
+		ScoringMode:      question.ScoringMode,
+		NumericAnswer:    question.NumericAnswer,
+		NumericTolerance: question.NumericTolerance,
+		AcceptedAnswers:  question.AcceptedAnswers,
+		CaseSensitive:    question.CaseSensitive,
+		Options:          []QuestionOptionSnapshot{},
+	}
+	if len(snapshot.AcceptedAnswers) == 0 {
+		snapshot.AcceptedAnswers = nil
+	}
+
+	for _, option := range options {
+		snapshot.Options = append(snapshot.Options, QuestionOptionSnapshot{
+			ID:          option.ID,
+			Body:        option.Body,
+			OptionValue: option.OptionValue != nil && *option.OptionValue,
+			IsImage:     option.IsImage,
+			ImgPath:     option.ImgPath,
+			Position:    option.Position,
+			MatchBody:   option.MatchBody,
+		})
+	}
+	sort.Slice(snapshot.Options, func(i, j int) bool {
+		return snapshot.Options[i].ID < snapshot.Options[j].ID
+	})
+
+	if solution != nil {
+		snapshot.Solution = &QuestionSolutionSnapshot{
+			SolutionType:   solution.SolutionType,
+			SolutionText:   solution.SolutionText,
+			SolutionImgUrl: solution.SolutionImgUrl,
+			PdfFileUrl:     solution.PdfFileUrl,
+			Link:           solution.Link,
+			IsPremium:      solution.IsPremium != nil && *solution.IsPremium,
+		}
+	}
+
+	return snapshot
+}
+
+// Equal tells whether both snapshots hold the same content
+func (s QuestionSnapshot) Equal(other QuestionSnapshot) bool {
+	return len(s.Diff(other)) == 0
+}
+
+// Diff lists the changes from the snapshot to the other one.
+// Options are matched by their ID and reported as options.<id>.<field>.
+func (s QuestionSnapshot) Diff(to QuestionSnapshot) []QuestionRevisionChange {
+	changes := diffFields("", s, to)
+
+	from := map[int]QuestionOptionSnapshot{}
+	for _, option := range s.Options {
+		from[option.ID] = option
+	}
+	for _, option := range to.Options {
+		field := fmt.Sprintf("options.%d", option.ID)
+		previous, ok := from[option.ID]
+		if !ok {
+			changes = append(changes, QuestionRevisionChange{Field: field, To: option})
+			continue
+		}
+		delete(from, option.ID)
+		changes = append(changes, diffFields(field+".", previous, option)...)
+	}
+	for _, option := range s.Options {
+		if _, ok := from[option.ID]; ok {
+			changes = append(changes, QuestionRevisionChange{Field: fmt.Sprintf("options.%d", option.ID), From: option})
+		}
+	}
+
+	switch {
+	case s.Solution == nil && to.Solution != nil:
+		changes = append(changes, QuestionRevisionChange{Field: "solution", To: *to.Solution})
+	case s.Solution != nil && to.Solution == nil:
+		changes = append(changes, QuestionRevisionChange{Field: "solution", From: *s.Solution})
+	case s.Solution != nil && to.Solution != nil:
+		changes = append(changes, diffFields("solution.", *s.Solution, *to.Solution)...)
+	}
+
+	return changes
+}
+
+// diffFields compares plain fields of two structs of the same type, named by their json tag
+func diffFields(prefix string, from, to interface{}) []QuestionRevisionChange {
+	var changes []QuestionRevisionChange
+
+	fromValue := reflect.ValueOf(from)
+	toValue := reflect.ValueOf(to)
+	for i := 0; i < fromValue.NumField(); i++ {
+		field := fromValue.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "options" || name == "solution" {
+			continue
+		}
+
+		a := fromValue.Field(i).Interface()
+		b := toValue.Field(i).Interface()
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, QuestionRevisionChange{Field: prefix + name, From: a, To: b})
+		}
+	}
+
+	return changes
+}
@@ -1,6 +1,12 @@
 package entities
 
-import "gitlab.com/project-quiz/internal/entities/base"
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities/base"
+	"gitlab.com/project-quiz/utils/minio"
+)
 
 type QuestionSolution struct {
 	ID             int    `json:"id" gorm:"primaryKey"`
@@ -10,5 +16,28 @@ type QuestionSolution struct {
 	SolutionType   string `json:"solution_type"`
 	PdfFileUrl     string `json:"pdf_file_url"`
 	Link           string `json:"link"`
+	IsPremium      *bool  `json:"is_premium" gorm:"default:false"`
 	base.Timestamp
 }
+
+func (q *QuestionSolution) GenerateTempStorageUrl(m minio.MinioStorageContract) {
+	if q.SolutionType == "image" && q.SolutionImgUrl != "" && !strings.HasPrefix(q.SolutionImgUrl, "http") {
+		url, err := m.GetTemporaryPublicUrl(q.SolutionImgUrl)
+		if err != nil {
+			logrus.Error("[Question Solution Entity]", err.Error())
+			return
+		}
+
+		q.SolutionImgUrl = url.String()
+	}
+
+	if q.SolutionType == "pdf" && q.PdfFileUrl != "" && !strings.HasPrefix(q.PdfFileUrl, "http") {
+		url, err := m.GetTemporaryPublicUrl(q.PdfFileUrl)
+		if err != nil {
+			logrus.Error("[Question Solution Entity]", err.Error())
+			return
+		}
+
+		q.PdfFileUrl = url.String()
+	}
+}
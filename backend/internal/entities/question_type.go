@@ -0,0 +1,294 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/project-quiz/utils/scoring"
+)
+
+const (
+	QuestionTypeSingleChoice   = "single_choice"
+	QuestionTypeMultipleAnswer = "multiple_answer"
+	QuestionTypeTrueFalse      = "true_false"
+	QuestionTypeNumeric        = "numeric"
+	QuestionTypeShortText      = "short_text"
+	QuestionTypeOrdering       = "ordering"
+	QuestionTypeMatching       = "matching"
+
+	ScoringModeAllOrNothing = "all_or_nothing"
+	ScoringModePartial      = "partial"
+)
+
+// QuestionType returns the type of question, questions created before types existed are single choice
+func (q Question) QuestionType() string {
+	if q.Type == "" {
+		return QuestionTypeSingleChoice
+	}
+
+	return q.Type
+}
+
+// IsPartial check whether the question gives partial credit
+func (q Question) IsPartial() bool {
+	return q.ScoringMode == ScoringModePartial
+}
+
+// ValidateAnswerKey check the answer key of the question. Options are only
+// checked when they are given.
+func (q Question) ValidateAnswerKey(options []QuestionOption) error {
+	switch q.QuestionType() {
+	case QuestionTypeNumeric:
+		if q.NumericAnswer == nil {
+			return errors.New("numeric_answer is required for numeric question")
+		}
+		if q.NumericTolerance < 0 {
+			return errors.New("numeric_tolerance must not be negative")
+		}
+		return nil
+	case QuestionTypeShortText:
+		for _, answer := range q.AcceptedAnswers {
+			if strings.TrimSpace(answer) != "" {
+				return nil
+			}
+		}
+		return errors.New("accepted_answers is required for short text question")
+	}
+
+	if options == nil {
+		return nil
+	}
+
+	trueCount := 0
+	for _, option := range options {
+		if option.OptionValue != nil && *option.OptionValue {
+			trueCount++
+		}
+	}
+
+	switch q.QuestionType() {
+	case QuestionTypeSingleChoice:
+		if trueCount != 1 {
+			return errors.New("single choice question must have exactly one true option")
+		}
+	case QuestionTypeTrueFalse:
+		if len(options) != 2 || trueCount != 1 {
+			return errors.New("true/false question must have two options with exactly one true option")
+		}
+	case QuestionTypeMultipleAnswer:
+		if trueCount == 0 {
+			return errors.New("multiple answer question must have at least one true option")
+		}
+	case QuestionTypeOrdering:
+		positions := make(map[int]bool, len(options))
+		for _, option := range options {
+			if option.Position == nil || *option.Position < 1 || *option.Position > len(options) || positions[*option.Position] {
+				return errors.New("ordering question options must have unique position from 1 to the number of options")
+			}
+			positions[*option.Position] = true
+		}
+	case QuestionTypeMatching:
+		for _, option := range options {
+			if strings.TrimSpace(option.MatchBody) == "" {
+				return errors.New("matching question options must have match_body")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown question type %s", q.Type)
+	}
+
+	return nil
+}
+
+// ValidateAnswer check that the answer fits the question type and only refers to options of the question
+func (q Question) ValidateAnswer(options []QuestionOption, answer UserQuestionAttempt) error {
+	exists := make(map[int]bool, len(options))
+	for _, option := range options {
+		exists[option.ID] = true
+	}
+
+	switch q.QuestionType() {
+	case QuestionTypeSingleChoice, QuestionTypeTrueFalse:
+		if answer.QuestionOptionID == nil {
+			return errors.New("option_id is required")
+		}
+		if !exists[*answer.QuestionOptionID] {
+			return errors.New("option is not part of the question")
+		}
+		if len(answer.QuestionOptionIDs) > 0 || answer.AnswerText != nil || len(answer.AnswerPairs) > 0 {
+			return errors.New("only option_id is accepted for this question")
+		}
+	case QuestionTypeMultipleAnswer, QuestionTypeOrdering:
+		if len(answer.QuestionOptionIDs) == 0 {
+			return errors.New("option_ids is required")
+		}
+		seen := make(map[int]bool, len(answer.QuestionOptionIDs))
+		for _, id := range answer.QuestionOptionIDs {
+			if !exists[id] {
+				return errors.New("option is not part of the question")
+			}
+			if seen[id] {
+				return errors.New("option_ids must be unique")
+			}
+			seen[id] = true
+		}
+		if q.QuestionType() == QuestionTypeOrdering && len(answer.QuestionOptionIDs) != len(options) {
+			return errors.New("option_ids must contain every option of the question")
+		}
+		if answer.QuestionOptionID != nil || answer.AnswerText != nil || len(answer.AnswerPairs) > 0 {
+			return errors.New("only option_ids is accepted for this question")
+		}
+	case QuestionTypeMatching:
+		if len(answer.AnswerPairs) == 0 {
+			return errors.New("answer_pairs is required")
+		}
+		seen := make(map[int]bool, len(answer.AnswerPairs))
+		for _, pair := range answer.AnswerPairs {
+			if !exists[pair.OptionID] || !exists[pair.MatchOptionID] {
+				return errors.New("option is not part of the question")
+			}
+			if seen[pair.OptionID] {
+				return errors.New("every option can only be paired once")
+			}
+			seen[pair.OptionID] = true
+		}
+		if answer.QuestionOptionID != nil || len(answer.QuestionOptionIDs) > 0 || answer.AnswerText != nil {
+			return errors.New("only answer_pairs is accepted for this question")
+		}
+	case QuestionTypeNumeric:
+		if answer.AnswerText == nil {
+			return errors.New("answer_text is required")
+		}
+		if _, err := parseNumber(*answer.AnswerText); err != nil {
+			return errors.New("answer_text must be a number")
+		}
+		if answer.QuestionOptionID != nil || len(answer.QuestionOptionIDs) > 0 || len(answer.AnswerPairs) > 0 {
+			return errors.New("only answer_text is accepted for this question")
+		}
+	case QuestionTypeShortText:
+		if answer.AnswerText == nil || strings.TrimSpace(*answer.AnswerText) == "" {
+			return errors.New("answer_text is required")
+		}
+		if answer.QuestionOptionID != nil || len(answer.QuestionOptionIDs) > 0 || len(answer.AnswerPairs) > 0 {
+			return errors.New("only answer_text is accepted for this question")
+		}
+	default:
+		return fmt.Errorf("unknown question type %s", q.Type)
+	}
+
+	return nil
+}
+
+// IsAnswered check whether the attempt holds an answer for the question type
+func (q Question) IsAnswered(answer UserQuestionAttempt) bool {
+	switch q.QuestionType() {
+	case QuestionTypeMultipleAnswer, QuestionTypeOrdering:
+		return len(answer.QuestionOptionIDs) > 0
+	case QuestionTypeMatching:
+		return len(answer.AnswerPairs) > 0
+	case QuestionTypeNumeric, QuestionTypeShortText:
+		return answer.AnswerText != nil && strings.TrimSpace(*answer.AnswerText) != ""
+	default:
+		return answer.QuestionOptionID != nil
+	}
+}
+
+// Grade returns the credit (0..1) of the answer
+func (q Question) Grade(options []QuestionOption, answer UserQuestionAttempt) float32 {
+	if !q.IsAnswered(answer) {
+		return 0
+	}
+
+	switch q.QuestionType() {
+	case QuestionTypeMultipleAnswer:
+		return scoring.Choice(q.TrueOptionIDs(options), answer.QuestionOptionIDs, q.IsPartial())
+	case QuestionTypeOrdering:
+		return scoring.Order(q.TrueOptionIDs(options), answer.QuestionOptionIDs, q.IsPartial())
+	case QuestionTypeMatching:
+		items := make([]int, len(options))
+		for i, option := range options {
+			items[i] = option.ID
+		}
+		pairs := make(map[int]int, len(answer.AnswerPairs))
+		for _, pair := range answer.AnswerPairs {
+			pairs[pair.OptionID] = pair.MatchOptionID
+		}
+		return scoring.Match(items, pairs, q.IsPartial())
+	case QuestionTypeNumeric:
+		given, err := parseNumber(*answer.AnswerText)
+		if err != nil || q.NumericAnswer == nil {
+			return 0
+		}
+		return scoring.Numeric(*q.NumericAnswer, q.NumericTolerance, given)
+	case QuestionTypeShortText:
+		return scoring.Text(q.AcceptedAnswers, *answer.AnswerText, q.CaseSensitive)
+	default:
+		return scoring.Choice(q.TrueOptionIDs(options), []int{*answer.QuestionOptionID}, false)
+	}
+}
+
+// TrueOptionIDs returns the correct options, in their correct order for ordering question
+// and every option for matching question
+func (q Question) TrueOptionIDs(options []QuestionOption) []int {
+	ids := []int{}
+
+	switch q.QuestionType() {
+	case QuestionTypeOrdering:
+		ordered := make([]QuestionOption, len(options))
+		copy(ordered, options)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return position(ordered[i]) < position(ordered[j])
+		})
+		for _, option := range ordered {
+			ids = append(ids, option.ID)
+		}
+	case QuestionTypeMatching:
+		for _, option := range options {
+			ids = append(ids, option.ID)
+		}
+	default:
+		for _, option := range options {
+			if option.OptionValue != nil && *option.OptionValue {
+				ids = append(ids, option.ID)
+			}
+		}
+	}
+
+	return ids
+}
+
+func position(option QuestionOption) int {
+	if option.Position == nil {
+		return 0
+	}
+
+	return *option.Position
+}
+
+// parseNumber parse numeric answer, comma is accepted as decimal separator
+func parseNumber(text string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", "."), 64)
+}
+
+// WithoutAnswerKey returns the question as it may be shown before it is answered,
+// the answer key of numeric and short text question and of the options are removed
+func (q Question) WithoutAnswerKey() Question {
+	q.NumericAnswer = nil
+	q.AcceptedAnswers = nil
+
+	options := make([]QuestionOption, len(q.QuestionOptions))
+	for i, option := range q.QuestionOptions {
+		option.OptionValue = nil
+		option.Position = nil
+		option.MatchBody = ""
+		options[i] = option
+	}
+	if q.QuestionOptions != nil {
+		q.QuestionOptions = options
+	}
+
+	return q
+}
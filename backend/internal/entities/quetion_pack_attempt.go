@@ -7,12 +7,20 @@ import (
 )
 
 type QuestionPackAttempt struct {
-	ID             int       `json:"id" gorm:"primaryKey"`
-	UserID         int       `json:"user_id"`
-	QuestionPackID int       `json:"question_pack_id"`
-	IsFinish       bool      `json:"is_finish"`
-	Score          float32   `json:"score"`
-	StartedAt      time.Time `json:"started_at"`
-	FinishedAt     time.Time `json:"finished_at"`
+	ID             int                        `json:"id" gorm:"primaryKey"`
+	UserID         int                        `json:"user_id"`
+	QuestionPackID int                        `json:"question_pack_id"`
+	IsFinish       bool                       `json:"is_finish"`
+	Score          float32                    `json:"score"`
+	MaxScore       float32                    `json:"max_score"`
+	StartedAt      time.Time                  `json:"started_at"`
+	FinishedAt     time.Time                  `json:"finished_at"`
+	Deadline       *time.Time                 `json:"deadline"`
+	Scores         []QuestionPackAttemptScore `json:"scores,omitempty"`
 	base.Timestamp
 }
+
+// IsExpired check whether the deadline of the attempt has passed
+func (q QuestionPackAttempt) IsExpired(now time.Time) bool {
+	return q.Deadline != nil && now.After(*q.Deadline)
+}
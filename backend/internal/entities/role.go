@@ -7,3 +7,5 @@ type Role struct {
 	Name string `json:"name"`
 	base.Timestamp
 }
+
+const RoleAdmin = "admin"
@@ -1,8 +1,11 @@
 package entities
 
+import "gitlab.com/project-quiz/internal/entities/base"
+
 type UserPoint struct {
 	ID     int  `json:"id" gorm:"primaryKey"`
 	UserID int  `json:"user_id"`
 	Point  int  `json:"point"`
 	User   User `json:"user"`
+	base.Timestamp
 }
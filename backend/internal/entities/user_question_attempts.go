@@ -3,12 +3,41 @@ package entities
 import "gitlab.com/project-quiz/internal/entities/base"
 
 type UserQuestionAttempt struct {
-	ID               int  `json:"id" gorm:"primaryKey"`
-	QuestionID       int  `json:"question_id"`
-	QuestionOptionID *int `json:"option_id"`
-	UserID           int  `json:"user_id"`
-	AttemptValue     bool `json:"attempt_value"`
-	IsMarked         bool `json:"is_marked"`
-	IsSubmitted      bool `json:"is_submitted"`
+	ID                    int  `json:"id" gorm:"primaryKey"`
+	QuestionID            int  `json:"question_id"`
+	QuestionOptionID      *int `json:"option_id"`
+	QuestionPackAttemptID *int `json:"question_pack_attempt_id"`
+	UserID                int  `json:"user_id"`
+	AttemptValue          bool `json:"attempt_value"`
+	IsMarked              bool `json:"is_marked"`
+	IsSubmitted           bool `json:"is_submitted"`
+	TimeSpent             int  `json:"time_spent"`
+	// Answer of multiple answer and ordering question
+	QuestionOptionIDs []int `json:"option_ids,omitempty" gorm:"serializer:json"`
+	// Answer of numeric and short text question
+	AnswerText *string `json:"answer_text,omitempty"`
+	// Answer of matching question
+	AnswerPairs []AnswerPair `json:"answer_pairs,omitempty" gorm:"serializer:json"`
+	// Share of the correct answer (0..1), more than 0 and less than 1 means partially correct
+	Credit float32 `json:"credit"`
+	// Revision of the question the attempt was answered against
+	QuestionRevisionID *int `json:"question_revision_id"`
 	base.Timestamp
 }
+
+// AnswerPair pair an option with the option whose match body is chosen for it
+type AnswerPair struct {
+	OptionID      int `json:"option_id" validate:"required"`
+	MatchOptionID int `json:"match_option_id" validate:"required"`
+}
+
+// SetAnswer replace the answer of the attempt with the answer of another attempt
+func (u *UserQuestionAttempt) SetAnswer(answer UserQuestionAttempt) {
+	u.QuestionOptionID = answer.QuestionOptionID
+	u.QuestionOptionIDs = answer.QuestionOptionIDs
+	u.AnswerText = answer.AnswerText
+	u.AnswerPairs = answer.AnswerPairs
+}
+
+// AnswerFields are the columns holding the answer of an attempt
+var AnswerFields = []string{"question_option_id", "question_option_ids", "answer_text", "answer_pairs"}
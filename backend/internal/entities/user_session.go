@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities/base"
+)
+
+const (
+	SessionRevokedLogout    = "logout"
+	SessionRevokedLogoutAll = "logout_all"
+	SessionRevokedReuse     = "refresh_token_reuse"
+	SessionRevokedByUser    = "revoked_by_user"
+	SessionRevokedByAdmin   = "revoked_by_admin"
+
+	SessionLoginPassword = "password"
+	SessionLoginBasic    = "basic"
+	SessionLoginGoogle   = "google"
+)
+
+// UserSession is a login of the user, every refresh token rotated from the login belongs to the same session.
+// Requests authenticated by basic auth share one session per device.
+type UserSession struct {
+	ID            int        `json:"id" gorm:"primaryKey"`
+	UserID        int        `json:"user_id"`
+	LoginMethod   string     `json:"login_method"`
+	UserAgent     string     `json:"user_agent"`
+	IPAddress     string     `json:"ip_address"`
+	LastSeenAt    time.Time  `json:"last_seen_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	RevokedReason string     `json:"revoked_reason"`
+	// Session of the request listing the sessions
+	Current bool `json:"current" gorm:"-"`
+	base.Timestamp
+}
+
+// IsRevoked tells whether the session can not be used anymore
+func (s UserSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// RefreshToken is a single use refresh token, only the hash of the token is stored
+type RefreshToken struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	SessionID int       `json:"session_id"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Set when the token is exchanged, using it again means the token was stolen
+	UsedAt *time.Time `json:"used_at"`
+	// Access token issued together with the refresh token
+	AccessJti       string       `json:"-"`
+	AccessExpiresAt time.Time    `json:"-"`
+	CreatedAt       time.Time    `json:"created_at"`
+	Session         *UserSession `json:"session,omitempty"`
+}
+
+// RevokedAccessToken denies the access token until it expires
+type RevokedAccessToken struct {
+	Jti       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities/base"
+)
+
+const (
+	PointReasonStreakBonus = "streak_bonus"
+
+	StreakAnswer = "answer_streak"
+	StreakDaily  = "daily_streak"
+)
+
+// StreakMilestones maps the length of a streak to its bonus points
+type StreakMilestones struct {
+	Answer map[int]int
+	Daily  map[int]int
+}
+
+// UserStreak counts consecutive correct answers and consecutive days with a submitted answer
+type UserStreak struct {
+	UserID              int `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	CurrentAnswerStreak int `json:"current_answer_streak"`
+	BestAnswerStreak    int `json:"best_answer_streak"`
+	CurrentDailyStreak  int `json:"current_daily_streak"`
+	BestDailyStreak     int `json:"best_daily_streak"`
+	// Last day the user submitted an answer
+	LastActiveDate *time.Time `json:"last_active_date" gorm:"type:date"`
+	// Submitting the same attempt again is not counted twice
+	LastAttemptID int `json:"-"`
+	base.Timestamp
+}
+
+// Record counts the submitted attempt on the day. Attempt of a question which does not count
+// toward the answer streak, such as one the user already got right, only counts as activity of the day.
+// It tells whether the streak changed, which is false when the attempt was the last one counted.
+func (s *UserStreak) Record(attemptID int, isCorrect, countsAnswer bool, day time.Time) bool {
+	if attemptID != 0 && attemptID == s.LastAttemptID {
+		return false
+	}
+	s.LastAttemptID = attemptID
+
+	if countsAnswer {
+		if isCorrect {
+			s.CurrentAnswerStreak++
+		} else {
+			s.CurrentAnswerStreak = 0
+		}
+	}
+	if s.CurrentAnswerStreak > s.BestAnswerStreak {
+		s.BestAnswerStreak = s.CurrentAnswerStreak
+	}
+
+	today := truncateDay(day)
+	switch {
+	case s.LastActiveDate == nil:
+		s.CurrentDailyStreak = 1
+	case truncateDay(*s.LastActiveDate).Equal(today):
+		// Already active today
+	case truncateDay(*s.LastActiveDate).AddDate(0, 0, 1).Equal(today):
+		s.CurrentDailyStreak++
+	default:
+		s.CurrentDailyStreak = 1
+	}
+	if s.CurrentDailyStreak > s.BestDailyStreak {
+		s.BestDailyStreak = s.CurrentDailyStreak
+	}
+	s.LastActiveDate = &today
+
+	return true
+}
+
+// AsOf shows the daily streak as broken when the user was not active yesterday nor on the day
+func (s UserStreak) AsOf(day time.Time) UserStreak {
+	if s.LastActiveDate != nil && truncateDay(*s.LastActiveDate).AddDate(0, 0, 1).Before(truncateDay(day)) {
+		s.CurrentDailyStreak = 0
+	}
+
+	return s
+}
+
+// truncateDay keeps the calendar date of t, as seen in the location of t, at midnight UTC
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities/base"
+)
+
+// VoucherBatch is a group of premium vouchers minted for a campaign
+type VoucherBatch struct {
+	ID         int              `json:"id" gorm:"primaryKey"`
+	Campaign   string           `json:"campaign"`
+	LongPeriod int              `json:"long_period"` // in days
+	Quantity   int              `json:"quantity"`
+	CodeLength int              `json:"code_length"` // without checksum character
+	Alphabet   string           `json:"alphabet"`
+	IsRevoked  bool             `json:"is_revoked"`
+	RevokedAt  *time.Time       `json:"revoked_at"`
+	Vouchers   []PremiumPackage `json:"vouchers,omitempty"`
+	base.Timestamp
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -15,10 +14,11 @@ import (
 )
 
 type analytic struct {
-	name             string
-	analyticUsecase  usecase.AnalyticUsecase
-	userPointUsecase usecase.UserPointUsecase
-	handler          Handler
+	name               string
+	analyticUsecase    usecase.AnalyticUsecase
+	userPointUsecase   usecase.UserPointUsecase
+	leaderboardUsecase usecase.LeaderboardUsecase
+	handler            Handler
 }
 
 type AnalyticHandler interface {
@@ -26,13 +26,15 @@ type AnalyticHandler interface {
 	GetAttemptAnalytic(w http.ResponseWriter, r *http.Request)
 	GetUserPoint(w http.ResponseWriter, r *http.Request)
 	GetUserPointList(w http.ResponseWriter, r *http.Request)
+	GetLeaderboard(w http.ResponseWriter, r *http.Request)
 }
 
 func NewAnalyticHandler(db *gorm.DB, m minio.MinioStorageContract) AnalyticHandler {
 	return &analytic{
-		name:             "Analytic Handler",
-		analyticUsecase:  usecase.NewAnaliticUsecase(db, m),
-		userPointUsecase: usecase.NewUserPointUsecase(db),
+		name:               "Analytic Handler",
+		analyticUsecase:    usecase.NewAnaliticUsecase(db, m),
+		userPointUsecase:   usecase.NewUserPointUsecase(db),
+		leaderboardUsecase: usecase.NewLeaderboardUsecase(db),
 	}
 }
 
@@ -47,7 +49,7 @@ func (a *analytic) GetCreatorAnalytic(w http.ResponseWriter, r *http.Request) {
 func (a *analytic) GetAttemptAnalytic(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	resp := a.analyticUsecase.GetTotalUserAttempt(userID)
 
 	a.handler.Response(w, resp, startTime, time.Now())
@@ -56,7 +58,7 @@ func (a *analytic) GetAttemptAnalytic(w http.ResponseWriter, r *http.Request) {
 func (a *analytic) GetUserPoint(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	resp := a.userPointUsecase.Get(userID)
 
 	a.handler.Response(w, resp, startTime, time.Now())
@@ -86,3 +88,29 @@ func (a *analytic) GetUserPointList(w http.ResponseWriter, r *http.Request) {
 
 	a.handler.Response(w, resp, startTime, time.Now())
 }
+
+func (a *analytic) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	var param params.LeaderboardParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		a.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		a.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	param.UserID = appctx.UserID(r.Context())
+	resp := a.leaderboardUsecase.Get(param)
+
+	a.handler.Response(w, resp, startTime, time.Now())
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -12,6 +11,7 @@ import (
 	"gitlab.com/project-quiz/internal/usecase"
 	"gitlab.com/project-quiz/utils/json"
 	"gitlab.com/project-quiz/utils/mailer"
+	"gitlab.com/project-quiz/utils/minio"
 	"gitlab.com/project-quiz/utils/validator"
 	"gorm.io/gorm"
 )
@@ -46,11 +46,15 @@ type AuthHandler interface {
 	UpdateAccount(w http.ResponseWriter, r *http.Request)
 	// Auth with Google
 	AuthWithGoogle(w http.ResponseWriter, r *http.Request)
+	// Logout from current session
+	Logout(w http.ResponseWriter, r *http.Request)
+	// Logout from every session of user
+	LogoutAll(w http.ResponseWriter, r *http.Request)
 }
 
-func NewAuthHandler(db *gorm.DB, smtp *mailer.Mailer, secret string, googleClientID string) AuthHandler {
+func NewAuthHandler(db *gorm.DB, smtp *mailer.Mailer, minio minio.MinioStorageContract, secret string, googleClientID string) AuthHandler {
 	return &auth{
-		userUsecase: usecase.NewUserUsecase(db),
+		userUsecase: usecase.NewUserUsecase(db, minio),
 		authUsecase: usecase.NewAuthUsecase(db, smtp, secret, googleClientID),
 		name:        "AUTH HANDLER",
 	}
@@ -107,6 +111,7 @@ func (a *auth) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	param.Client = ClientOf(r)
 	resp := a.authUsecase.Login(param)
 	a.handler.Response(w, resp, startTime, time.Now())
 }
@@ -130,6 +135,7 @@ func (a *auth) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	param.Client = ClientOf(r)
 	resp := a.authUsecase.Refresh(param)
 	a.handler.Response(w, resp, startTime, time.Now())
 }
@@ -231,15 +237,15 @@ func (a *auth) GetAuthenticatedUser(w http.ResponseWriter, r *http.Request) {
 	logrus.Info(fmt.Sprintf("[%s][Me] is executed", a.name))
 	startTime := time.Now()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
-	resp := a.userUsecase.Get(userID)
+	userID := appctx.UserID(r.Context())
+	resp := a.userUsecase.Me(userID)
 
 	a.handler.Response(w, resp, startTime, time.Now())
 }
 
 func (a *auth) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	ctx := appctx.NewResponse()
 
 	// Decode data
@@ -268,7 +274,7 @@ func (a *auth) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	var param params.UserUpdateParam
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.ID = userID
 
 	if err := json.Decode(r.Body, &param); err != nil {
@@ -309,6 +315,25 @@ func (a *auth) AuthWithGoogle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := a.authUsecase.AuthenticateGoogleJWT(param.Token)
+	param.Client = ClientOf(r)
+	resp := a.authUsecase.AuthenticateGoogleJWT(param)
+	a.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (a *auth) Logout(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	principal, _ := appctx.PrincipalFrom(r.Context())
+	resp := a.authUsecase.Logout(principal)
+
+	a.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (a *auth) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	principal, _ := appctx.PrincipalFrom(r.Context())
+	resp := a.authUsecase.LogoutAll(principal)
+
 	a.handler.Response(w, resp, startTime, time.Now())
 }
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/minio"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const badgeIconMaxSize = 2 << 20
+
+type badge struct {
+	handler      Handler
+	badgeUsecase usecase.BadgeUsecase
+	name         string
+}
+
+type BadgeHandler interface {
+	// Create badge from multipart form with optional icon
+	Create(w http.ResponseWriter, r *http.Request)
+	// Get list of badges
+	List(w http.ResponseWriter, r *http.Request)
+	// Get detail of badge
+	Detail(w http.ResponseWriter, r *http.Request)
+	// Update badge from multipart form, icon is replaced when given
+	Update(w http.ResponseWriter, r *http.Request)
+	// Delete badge
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+func NewBadgeHandler(db *gorm.DB, minio minio.MinioStorageContract) BadgeHandler {
+	return &badge{
+		name:         "Badge Handler",
+		badgeUsecase: usecase.NewBadgeUsecase(db, minio),
+	}
+}
+
+func (b *badge) Create(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Create] is executed", b.name))
+	startTime := time.Now()
+
+	var param params.BadgeCreateParam
+	if resp := b.formParam(r, &param); resp != nil {
+		b.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	resp := b.badgeUsecase.Create(param)
+	b.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (b *badge) List(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", b.name))
+	startTime := time.Now()
+
+	var param params.BadgeFilter
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		b.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		b.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := b.badgeUsecase.List(param)
+	b.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (b *badge) Detail(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Detail] is executed", b.name))
+	startTime := time.Now()
+
+	ID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := b.badgeUsecase.Detail(ID)
+	b.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (b *badge) Update(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Update] is executed", b.name))
+	startTime := time.Now()
+
+	var param params.BadgeUpdateParam
+	param.ID, _ = strconv.Atoi(chi.URLParam(r, "id"))
+
+	if resp := b.formParam(r, &param.BadgeCreateParam); resp != nil {
+		b.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	resp := b.badgeUsecase.Update(param)
+	b.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (b *badge) Delete(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Delete] is executed", b.name))
+	startTime := time.Now()
+
+	ID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := b.badgeUsecase.Delete(ID)
+	b.handler.Response(w, resp, startTime, time.Now())
+}
+
+// formParam decodes the fields of the multipart form and the optional icon file
+func (b *badge) formParam(r *http.Request, param *params.BadgeCreateParam) *appctx.Response {
+	if err := r.ParseMultipartForm(badgeIconMaxSize); err != nil {
+		logrus.Error(err.Error())
+		return appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if err := decoder.Decode(param, r.MultipartForm.Value); err != nil {
+		logrus.Error(err.Error())
+		return appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	_, icon, err := r.FormFile("icon")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		logrus.Error(err.Error())
+		return appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+	if icon != nil && icon.Size > badgeIconMaxSize {
+		return appctx.NewResponse().WithErrors("icon must not be larger than 2 MB").WithCode(http.StatusBadRequest)
+	}
+	param.Icon = icon
+
+	if err := validator.Validate(*param); err != nil {
+		logrus.Error(err.Error())
+		return appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+
+	"gitlab.com/project-quiz/internal/params"
+)
+
+// ClientOf returns the user agent and IP address of the request
+func ClientOf(r *http.Request) params.SessionClient {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return params.SessionClient{
+		UserAgent: r.UserAgent(),
+		IPAddress: ip,
+	}
+}
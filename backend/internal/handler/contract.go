@@ -2,10 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params/generics"
 
 	"github.com/gorilla/schema"
 )
@@ -23,3 +25,21 @@ func (h *Handler) Response(w http.ResponseWriter, resp appctx.Response, startTim
 	d, _ := json.Marshal(resp)
 	w.Write(d)
 }
+
+// File write generics.FileResponse data of a successful response as download,
+// any other response is written as JSON
+func (h *Handler) File(w http.ResponseWriter, resp appctx.Response, startTime time.Time, endTime time.Time) {
+	file, ok := resp.Data.(generics.FileResponse)
+	if resp.Code != http.StatusOK || !ok {
+		h.Response(w, resp, startTime, endTime)
+		return
+	}
+
+	for key, value := range file.Headers {
+		w.Header().Set(key, value)
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Content)
+}
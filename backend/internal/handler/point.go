@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type point struct {
+	handler Handler
+	usecase usecase.UserPointUsecase
+	name    string
+}
+
+type PointHandler interface {
+	// Admin gives points to user with a note
+	Grant(w http.ResponseWriter, r *http.Request)
+	// Admin takes points away from user with a note
+	Revoke(w http.ResponseWriter, r *http.Request)
+	// Admin lists point transactions of every user
+	Transactions(w http.ResponseWriter, r *http.Request)
+	// User lists own point transactions
+	History(w http.ResponseWriter, r *http.Request)
+}
+
+func NewPointHandler(db *gorm.DB) PointHandler {
+	return &point{
+		name:    "Point Handler",
+		usecase: usecase.NewUserPointUsecase(db),
+	}
+}
+
+func (p *point) Grant(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Grant] is executed", p.name))
+	startTime := time.Now()
+
+	param, resp := p.adjustParam(r)
+	if resp != nil {
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	p.handler.Response(w, p.usecase.Grant(param), startTime, time.Now())
+}
+
+func (p *point) Revoke(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Revoke] is executed", p.name))
+	startTime := time.Now()
+
+	param, resp := p.adjustParam(r)
+	if resp != nil {
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	p.handler.Response(w, p.usecase.Revoke(param), startTime, time.Now())
+}
+
+func (p *point) adjustParam(r *http.Request) (params.PointAdjustParam, *appctx.Response) {
+	var param params.PointAdjustParam
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error(fmt.Sprintf("[%s] Cannot decode json", p.name))
+		return param, appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		return param, appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	param.ActorID = appctx.UserID(r.Context())
+	return param, nil
+}
+
+func (p *point) Transactions(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Transactions] is executed", p.name))
+	startTime := time.Now()
+
+	param, resp := p.filterParam(r)
+	if resp != nil {
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	p.handler.Response(w, p.usecase.Transactions(param), startTime, time.Now())
+}
+
+func (p *point) History(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][History] is executed", p.name))
+	startTime := time.Now()
+
+	param, resp := p.filterParam(r)
+	if resp != nil {
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	param.UserID = appctx.UserID(r.Context())
+	p.handler.Response(w, p.usecase.Transactions(param), startTime, time.Now())
+}
+
+func (p *point) filterParam(r *http.Request) (params.PointTransactionFilterParam, *appctx.Response) {
+	var param params.PointTransactionFilterParam
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		return param, appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		return param, appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	return param, nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/rbac"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type policy struct {
+	handler       Handler
+	policyUsecase usecase.PolicyUsecase
+	name          string
+}
+
+type PolicyHandler interface {
+	// List role policies
+	List(w http.ResponseWriter, r *http.Request)
+	// Add role policy
+	Add(w http.ResponseWriter, r *http.Request)
+	// Remove role policy
+	Remove(w http.ResponseWriter, r *http.Request)
+}
+
+func NewPolicyHandler(db *gorm.DB, enforcer *rbac.Enforcer) PolicyHandler {
+	return &policy{
+		name:          "Policy Handler",
+		policyUsecase: usecase.NewPolicyUsecase(db, enforcer),
+	}
+}
+
+func (p *policy) List(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", p.name))
+	startTime := time.Now()
+
+	var param params.PolicyFilterParam
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		resp := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	p.handler.Response(w, p.policyUsecase.List(param), startTime, time.Now())
+}
+
+func (p *policy) Add(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Add] is executed", p.name))
+	startTime := time.Now()
+
+	param, resp := p.policyParam(r)
+	if resp != nil {
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	p.handler.Response(w, p.policyUsecase.Add(param), startTime, time.Now())
+}
+
+func (p *policy) Remove(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Remove] is executed", p.name))
+	startTime := time.Now()
+
+	param, resp := p.policyParam(r)
+	if resp != nil {
+		p.handler.Response(w, *resp, startTime, time.Now())
+		return
+	}
+
+	p.handler.Response(w, p.policyUsecase.Remove(param), startTime, time.Now())
+}
+
+func (p *policy) policyParam(r *http.Request) (params.Policy, *appctx.Response) {
+	var param params.Policy
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error(fmt.Sprintf("[%s] Cannot decode json", p.name))
+		return param, appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		return param, appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	return param, nil
+}
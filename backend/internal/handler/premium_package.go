@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type premiumPackage struct {
+	handler Handler
+	usecase usecase.PremiumPackageUsecase
+	name    string
+}
+
+type PremiumPackageHandler interface {
+	// Create a new premium package
+	Create(w http.ResponseWriter, r *http.Request)
+	// Get list of premium packages
+	List(w http.ResponseWriter, r *http.Request)
+	// Get detail of premium package
+	Detail(w http.ResponseWriter, r *http.Request)
+	// Update a premium package
+	Update(w http.ResponseWriter, r *http.Request)
+	// Delete a premium package
+	Delete(w http.ResponseWriter, r *http.Request)
+	// Redeem voucher for authenticated user
+	Redeem(w http.ResponseWriter, r *http.Request)
+	// Get active premium package of authenticated user
+	GetActive(w http.ResponseWriter, r *http.Request)
+}
+
+func NewPremiumPackageHandler(db *gorm.DB) PremiumPackageHandler {
+	return &premiumPackage{
+		name:    "Premium Package Handler",
+		usecase: usecase.NewPremiumPackageUsecase(db),
+	}
+}
+
+func (p *premiumPackage) Create(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Create] is executed", p.name))
+	startTime := time.Now()
+
+	var param params.PremiumPackageCreateParam
+	ctx := appctx.NewResponse()
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error("Cannot decode json")
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := p.usecase.Create(param)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (p *premiumPackage) List(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", p.name))
+	startTime := time.Now()
+
+	var param params.PremiumPackageFilterParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := p.usecase.List(param)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (p *premiumPackage) Detail(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Detail] is executed", p.name))
+	startTime := time.Now()
+
+	id := chi.URLParam(r, "id")
+	packageID, _ := strconv.Atoi(id)
+
+	resp := p.usecase.Detail(packageID)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (p *premiumPackage) Update(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Update] is executed", p.name))
+	startTime := time.Now()
+
+	var param params.PremiumPackageUpdateParam
+	ctx := appctx.NewResponse()
+
+	id := chi.URLParam(r, "id")
+	param.ID, _ = strconv.Atoi(id)
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error("Cannot decode json")
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := p.usecase.Update(param)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (p *premiumPackage) Delete(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Delete] is executed", p.name))
+	startTime := time.Now()
+
+	id := chi.URLParam(r, "id")
+	packageID, _ := strconv.Atoi(id)
+
+	resp := p.usecase.Delete(packageID)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (p *premiumPackage) Redeem(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Redeem] is executed", p.name))
+	startTime := time.Now()
+
+	var param params.PremiumPackageRedeemParam
+	ctx := appctx.NewResponse()
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error("Cannot decode json")
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	userID := appctx.UserID(r.Context())
+	param.UserID = userID
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		p.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := p.usecase.Redeem(param)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (p *premiumPackage) GetActive(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][GetActive] is executed", p.name))
+	startTime := time.Now()
+
+	userID := appctx.UserID(r.Context())
+
+	resp := p.usecase.GetActive(userID)
+	p.handler.Response(w, resp, startTime, time.Now())
+}
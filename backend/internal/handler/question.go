@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"gitlab.com/project-quiz/utils/boolpointer"
 	"gitlab.com/project-quiz/utils/json"
 	"gitlab.com/project-quiz/utils/minio"
+	"gitlab.com/project-quiz/utils/random"
 	"gitlab.com/project-quiz/utils/validator"
 	"gorm.io/gorm"
 )
@@ -41,6 +43,12 @@ type QuestionHandler interface {
 	AdminUpdateOption(w http.ResponseWriter, r *http.Request)
 	//Admin delete option
 	AdminDeleteOption(w http.ResponseWriter, r *http.Request)
+	// Contributor add Option
+	AddOptionByContributor(w http.ResponseWriter, r *http.Request)
+	// Contributor update Option
+	UpdateOptionByContributor(w http.ResponseWriter, r *http.Request)
+	// Contributor delete Option
+	DeleteOptionByContributor(w http.ResponseWriter, r *http.Request)
 	// Get list of questions
 	GetList(w http.ResponseWriter, r *http.Request)
 	// Get detail of question
@@ -53,6 +61,10 @@ type QuestionHandler interface {
 	AddTags(w http.ResponseWriter, r *http.Request)
 	// Remove tag
 	RemoveTag(w http.ResponseWriter, r *http.Request)
+	// Contributor add tags
+	AddTagsByContributor(w http.ResponseWriter, r *http.Request)
+	// Contributor remove tag
+	RemoveTagByContributor(w http.ResponseWriter, r *http.Request)
 	// Contributor Create Question
 	CreateByContributor(w http.ResponseWriter, r *http.Request)
 	// Get list question created by contributor
@@ -63,12 +75,20 @@ type QuestionHandler interface {
 	GetDetailByContributor(w http.ResponseWriter, r *http.Request)
 	// Upload Image Placement
 	UploadImagePlacement(w http.ResponseWriter, r *http.Request)
+	// Admin import questions from spreadsheet
+	Import(w http.ResponseWriter, r *http.Request)
+	// Contributor import questions from spreadsheet
+	ImportByContributor(w http.ResponseWriter, r *http.Request)
+	// Admin export questions as QTI 2.1, Moodle XML or GIFT
+	Export(w http.ResponseWriter, r *http.Request)
+	// Admin import questions from QTI 2.1, Moodle XML or GIFT
+	ImportBank(w http.ResponseWriter, r *http.Request)
 }
 
 func NewQuestionHandler(db *gorm.DB, minio minio.MinioStorageContract) QuestionHandler {
 	return &question{
 		questionUsecase: usecase.NewQuestionUsecase(db, minio),
-		attemptUsecase:  usecase.NewUserQuestionAttemptUsecase(db),
+		attemptUsecase:  usecase.NewUserQuestionAttemptUsecase(db, minio, entities.StreakMilestones{}),
 		name:            "Uestion Handler",
 	}
 }
@@ -109,6 +129,14 @@ func (q *question) AdminGetDetail(w http.ResponseWriter, r *http.Request) {
 }
 
 func (q *question) AdminAddOption(w http.ResponseWriter, r *http.Request) {
+	q.addOption(w, r, false)
+}
+
+func (q *question) AddOptionByContributor(w http.ResponseWriter, r *http.Request) {
+	q.addOption(w, r, true)
+}
+
+func (q *question) addOption(w http.ResponseWriter, r *http.Request, byContributor bool) {
 	startTime := time.Now()
 
 	var param params.QuestionOptionAdd
@@ -128,20 +156,32 @@ func (q *question) AdminAddOption(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := q.questionUsecase.AddOption(param)
+	param.ActorID = appctx.UserID(r.Context())
+	if !byContributor {
+		resp := q.questionUsecase.AddOption(param)
+		q.handler.Response(w, resp, startTime, time.Now())
+		return
+	}
+
+	param.ContributorID = param.ActorID
+	resp := q.questionUsecase.AddOptionByContributor(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
 func (q *question) AdminUpdateOption(w http.ResponseWriter, r *http.Request) {
+	q.updateOption(w, r, false)
+}
+
+func (q *question) UpdateOptionByContributor(w http.ResponseWriter, r *http.Request) {
+	q.updateOption(w, r, true)
+}
+
+func (q *question) updateOption(w http.ResponseWriter, r *http.Request, byContributor bool) {
 	startTime := time.Now()
 
 	var param params.QuestionOptionUpdate
 	ctx := appctx.NewResponse()
 
-	id := chi.URLParam(r, "id")
-	idx, _ := strconv.Atoi(id)
-	param.ID = idx
-
 	if err := json.Decode(r.Body, &param); err != nil {
 		logrus.Error(fmt.Sprintf("[%s] Cannot decode json", q.name))
 		ctx = ctx.WithErrors(err.Error())
@@ -149,6 +189,10 @@ func (q *question) AdminUpdateOption(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Option of the URL is the one checked by the router, body can not point to another
+	id := chi.URLParam(r, "id")
+	param.ID, _ = strconv.Atoi(id)
+
 	if err := validator.Validate(param); err != nil {
 		logrus.Error(err.Error())
 		ctx = ctx.WithErrors(err.Error())
@@ -156,7 +200,15 @@ func (q *question) AdminUpdateOption(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := q.questionUsecase.UpdateOption(param)
+	param.ActorID = appctx.UserID(r.Context())
+	if !byContributor {
+		resp := q.questionUsecase.UpdateOption(param)
+		q.handler.Response(w, resp, startTime, time.Now())
+		return
+	}
+
+	param.ContributorID = param.ActorID
+	resp := q.questionUsecase.UpdateOptionByContributor(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
@@ -166,7 +218,21 @@ func (q *question) AdminDeleteOption(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	idx, _ := strconv.Atoi(id)
 
-	resp := q.questionUsecase.DeleteOption(idx)
+	userID := appctx.UserID(r.Context())
+
+	resp := q.questionUsecase.DeleteOption(idx, userID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *question) DeleteOptionByContributor(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	id := chi.URLParam(r, "id")
+	idx, _ := strconv.Atoi(id)
+
+	userID := appctx.UserID(r.Context())
+
+	resp := q.questionUsecase.DeleteOptionByContributor(idx, userID)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
@@ -176,7 +242,7 @@ func (q *question) GetList(w http.ResponseWriter, r *http.Request) {
 	var param params.QuestionFilterParam
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	logrus.Debug(userID)
 
 	param.Show = "active"
@@ -211,11 +277,17 @@ func (q *question) GetList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	attemptParam := params.AttemptGetLatestAnswersParam{
-		QuestionIDs: questionListIDs,
-		UserID:      userID,
+		QuestionIDs:           questionListIDs,
+		UserID:                userID,
+		QuestionPackAttemptID: param.QuestionPackAttemptID,
 	}
 
 	attempt := q.attemptUsecase.GetLatestAnswers(attemptParam)
+	if len(attempt.Errors) > 0 {
+		q.handler.Response(w, attempt, startTime, time.Now())
+		return
+	}
+
 	attemptList, ok := attempt.Data.([]entities.UserQuestionAttempt)
 	if !ok {
 		ctx = ctx.WithErrors("error parsing attempt list")
@@ -238,12 +310,15 @@ func (q *question) GetList(w http.ResponseWriter, r *http.Request) {
 		temp.Body = questionList[i].Body
 		temp.MaterialID = questionList[i].MaterialID
 		temp.Code = questionList[i].Code
+		temp.Snippet = questionList[i].Snippet
 		for j := 0; j < len(attemptList); j++ {
 			if attemptList[j].QuestionID == temp.ID {
 				temp.IsSubmitted = attemptList[j].IsSubmitted
 				temp.IsAnswered = attemptList[j].QuestionID != 0
 				temp.IsAnswerTrue = attemptList[j].AttemptValue
-				temp.AnswerID = *attemptList[j].QuestionOptionID
+				if attemptList[j].QuestionOptionID != nil {
+					temp.AnswerID = *attemptList[j].QuestionOptionID
+				}
 				attemptList = append(attemptList[:j], attemptList[j+1:]...)
 			}
 		}
@@ -268,38 +343,48 @@ func (q *question) GetDetail(w http.ResponseWriter, r *http.Request) {
 	idx, _ := strconv.Atoi(id)
 
 	var param params.AttemptClearAnswerQuestionParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 	param.QuestionID = idx
 
 	resp := q.questionUsecase.Detail(idx)
+	if len(resp.Errors) > 0 {
+		q.handler.Response(w, resp, startTime, time.Now())
+		return
+	}
+
 	respAttemp := q.attemptUsecase.GetLatestAnswer(param)
 	question := resp.Data.(entities.Question)
-	// fmt.Println(question)
 	var response params.QuestionDetailResponse
 	response.ID = question.ID
 	response.Body = question.Body
 	response.MaterialID = question.MaterialID
+	response.Type = question.QuestionType()
 	response.IsImage = question.IsImage
 	response.ImgPath = question.ImgPath
-	response.QuestionOptions = question.QuestionOptions
+	response.QuestionOptions, response.Matches = studentOptions(question)
 	response.QuestionTags = question.QuestionTags
 	response.Code = question.Code
 	response.ImgPlacementUrl = question.ImgPlacementUrl
-	TrueAnswerID := 0
-	for i := 0; i < len(response.QuestionOptions); i++ {
-		if *response.QuestionOptions[i].OptionValue {
-			TrueAnswerID = response.QuestionOptions[i].ID
-		}
-		*response.QuestionOptions[i].OptionValue = false
-	}
 
 	attempt, ok := respAttemp.Data.(entities.UserQuestionAttempt)
 	if ok {
 		response.IsAnswerTrue = attempt.AttemptValue
 		response.IsAnswered = attempt.IsSubmitted
-		response.AnswerID = *attempt.QuestionOptionID
-		response.TrueAnswerID = TrueAnswerID
+		if attempt.QuestionOptionID != nil {
+			response.AnswerID = *attempt.QuestionOptionID
+		}
+		response.OptionIDs = attempt.QuestionOptionIDs
+		response.AnswerText = attempt.AnswerText
+		response.AnswerPairs = attempt.AnswerPairs
+
+		// The answer key is only shown once the answer is submitted
+		if attempt.IsSubmitted {
+			response.TrueAnswerIDs = question.TrueOptionIDs(question.QuestionOptions)
+			if len(response.TrueAnswerIDs) > 0 {
+				response.TrueAnswerID = response.TrueAnswerIDs[0]
+			}
+		}
 	}
 
 	mark := q.questionUsecase.GetMark(userID, param.QuestionID)
@@ -318,7 +403,7 @@ func (q *question) AddRemoveMark(w http.ResponseWriter, r *http.Request) {
 	ctx := appctx.NewResponse()
 
 	var param params.QuestionMarkAddRemoveParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 
 	if err := json.Decode(r.Body, &param); err != nil {
@@ -362,7 +447,7 @@ func (q *question) Create(w http.ResponseWriter, r *http.Request) {
 	var param params.QuestionCreate
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.ContributorID = userID
 
 	if err := json.Decode(r.Body, &param); err != nil {
@@ -406,11 +491,20 @@ func (q *question) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	param.ActorID = appctx.UserID(r.Context())
 	resp := q.questionUsecase.Update(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
 func (q *question) AddTags(w http.ResponseWriter, r *http.Request) {
+	q.addTags(w, r, false)
+}
+
+func (q *question) AddTagsByContributor(w http.ResponseWriter, r *http.Request) {
+	q.addTags(w, r, true)
+}
+
+func (q *question) addTags(w http.ResponseWriter, r *http.Request, byContributor bool) {
 	startTime := time.Now()
 	var param params.QuestionAddTags
 	ctx := appctx.NewResponse()
@@ -429,11 +523,26 @@ func (q *question) AddTags(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := q.questionUsecase.AddTags(param)
+	if !byContributor {
+		resp := q.questionUsecase.AddTags(param)
+		q.handler.Response(w, resp, startTime, time.Now())
+		return
+	}
+
+	param.ContributorID = appctx.UserID(r.Context())
+	resp := q.questionUsecase.AddTagsByContributor(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
 func (q *question) RemoveTag(w http.ResponseWriter, r *http.Request) {
+	q.removeTag(w, r, false)
+}
+
+func (q *question) RemoveTagByContributor(w http.ResponseWriter, r *http.Request) {
+	q.removeTag(w, r, true)
+}
+
+func (q *question) removeTag(w http.ResponseWriter, r *http.Request, byContributor bool) {
 	startTime := time.Now()
 	var param params.QuestionRemoveTag
 
@@ -453,7 +562,14 @@ func (q *question) RemoveTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := q.questionUsecase.RemoveTag(param)
+	if !byContributor {
+		resp := q.questionUsecase.RemoveTag(param)
+		q.handler.Response(w, resp, startTime, time.Now())
+		return
+	}
+
+	param.ContributorID = appctx.UserID(r.Context())
+	resp := q.questionUsecase.RemoveTagByContributor(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
@@ -462,9 +578,6 @@ func (q *question) CreateByContributor(w http.ResponseWriter, r *http.Request) {
 	var param params.QuestionCreate
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
-	param.ContributorID = userID
-
 	if err := json.Decode(r.Body, &param); err != nil {
 		logrus.Error(fmt.Sprintf("[%s] Cannot decode json", q.name))
 		ctx = ctx.WithErrors(err.Error())
@@ -472,6 +585,10 @@ func (q *question) CreateByContributor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Set after decoding so the body can not claim another contributor
+	userID := appctx.UserID(r.Context())
+	param.ContributorID = userID
+
 	if err := validator.Validate(param); err != nil {
 		logrus.Error(fmt.Sprintf("[%s] %s", q.name, err.Error()))
 		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -489,7 +606,7 @@ func (q *question) GetListByContributor(w http.ResponseWriter, r *http.Request)
 	var param params.QuestionFilterParam
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.ContributorID = userID
 
 	logrus.Debug(userID)
@@ -518,12 +635,6 @@ func (q *question) UpdateByContributor(w http.ResponseWriter, r *http.Request) {
 	var param params.QuestionUpdate
 	ctx := appctx.NewResponse()
 
-	id := chi.URLParam(r, "id")
-	idx, _ := strconv.Atoi(id)
-	param.ID = idx
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
-	param.ContributorID = userID
-
 	if err := json.Decode(r.Body, &param); err != nil {
 		logrus.Error(fmt.Sprintf("[%s] Cannot decode json", q.name))
 		ctx = ctx.WithErrors(err.Error())
@@ -531,6 +642,12 @@ func (q *question) UpdateByContributor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Set after decoding so the body can not point to another question or contributor
+	id := chi.URLParam(r, "id")
+	param.ID, _ = strconv.Atoi(id)
+	userID := appctx.UserID(r.Context())
+	param.ContributorID = userID
+
 	if err := validator.Validate(param); err != nil {
 		logrus.Error(fmt.Sprintf("[%s] %s", q.name, err.Error()))
 		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -538,8 +655,8 @@ func (q *question) UpdateByContributor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	param.IsActive = nil
-	resp := q.questionUsecase.Update(param)
+	param.ActorID = userID
+	resp := q.questionUsecase.UpdateByContributor(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
@@ -580,3 +697,152 @@ func (q *question) UploadImagePlacement(w http.ResponseWriter, r *http.Request)
 	resp := q.questionUsecase.UploadImagePlacement(questionIDNumber, fileHeader)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
+
+func (q *question) Import(w http.ResponseWriter, r *http.Request) {
+	q.importQuestions(w, r, true)
+}
+
+func (q *question) ImportByContributor(w http.ResponseWriter, r *http.Request) {
+	q.importQuestions(w, r, false)
+}
+
+func (q *question) importQuestions(w http.ResponseWriter, r *http.Request, isAdmin bool) {
+	startTime := time.Now()
+
+	// Spreadsheet is kept in memory up to 10 MB
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		d := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *d, startTime, time.Now())
+		return
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		d := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *d, startTime, time.Now())
+		return
+	}
+	defer file.Close()
+
+	userID := appctx.UserID(r.Context())
+	dryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+
+	param := params.QuestionImportParam{
+		FileName:      fileHeader.Filename,
+		ContributorID: userID,
+		IsAdmin:       isAdmin,
+		DryRun:        dryRun,
+	}
+
+	resp := q.questionUsecase.Import(param, file)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *question) Export(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Export] is executed", q.name))
+	startTime := time.Now()
+
+	var param params.QuestionExportParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := q.questionUsecase.Export(param)
+	q.handler.File(w, resp, startTime, time.Now())
+}
+
+// Question bank package with its images can be up to 50 MB
+const questionBankMaxSize = 50 << 20
+
+func (q *question) ImportBank(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Import Bank] is executed", q.name))
+	startTime := time.Now()
+
+	if err := r.ParseMultipartForm(questionBankMaxSize); err != nil {
+		d := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *d, startTime, time.Now())
+		return
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		d := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *d, startTime, time.Now())
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, questionBankMaxSize+1))
+	if err != nil || len(content) > questionBankMaxSize {
+		d := appctx.NewResponse().WithErrors("file must not be larger than 50 MB").WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *d, startTime, time.Now())
+		return
+	}
+
+	userID := appctx.UserID(r.Context())
+	materialID, _ := strconv.Atoi(r.FormValue("material_id"))
+	dryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+
+	param := params.QuestionBankImportParam{
+		Format:        r.FormValue("format"),
+		FileName:      fileHeader.Filename,
+		MaterialID:    materialID,
+		ContributorID: userID,
+		IsAdmin:       true,
+		DryRun:        dryRun,
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		d := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *d, startTime, time.Now())
+		return
+	}
+
+	resp := q.questionUsecase.ImportBank(param, content)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+// studentOptions hide the answer key from the options of question. Options of ordering question are
+// shuffled and match bodies of matching question are returned shuffled apart from their options.
+func studentOptions(question entities.Question) ([]params.QuestionOptionDetailResponse, []params.QuestionMatchResponse) {
+	options := make([]params.QuestionOptionDetailResponse, 0, len(question.QuestionOptions))
+	var matches []params.QuestionMatchResponse
+
+	for _, option := range question.QuestionOptions {
+		options = append(options, params.QuestionOptionDetailResponse{
+			ID:         option.ID,
+			Body:       option.Body,
+			IsImage:    option.IsImage,
+			ImgPath:    option.ImgPath,
+			QuestionID: option.QuestionID,
+		})
+
+		if question.QuestionType() == entities.QuestionTypeMatching {
+			matches = append(matches, params.QuestionMatchResponse{OptionID: option.ID, Body: option.MatchBody})
+		}
+	}
+
+	if question.QuestionType() == entities.QuestionTypeOrdering {
+		random.Shuffle(len(options), func(i, j int) {
+			options[i], options[j] = options[j], options[i]
+		})
+	}
+	random.Shuffle(len(matches), func(i, j int) {
+		matches[i], matches[j] = matches[j], matches[i]
+	})
+
+	return options, matches
+}
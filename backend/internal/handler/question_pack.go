@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"gitlab.com/project-quiz/internal/usecase"
 	"gitlab.com/project-quiz/utils/boolpointer"
 	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/minio"
 	"gitlab.com/project-quiz/utils/validator"
 	"gorm.io/gorm"
 )
@@ -29,6 +31,8 @@ type QuestionPackHandler interface {
 	AdminGetList(w http.ResponseWriter, r *http.Request)
 	// Get detail of question pack
 	GetDetail(w http.ResponseWriter, r *http.Request)
+	// Get detail of question pack for basic role, without answer keys
+	BasicGetDetail(w http.ResponseWriter, r *http.Request)
 	// Create question pack
 	Create(w http.ResponseWriter, r *http.Request)
 	// Update question pack
@@ -45,11 +49,17 @@ type QuestionPackHandler interface {
 	BasicFinishQuestionPack(w http.ResponseWriter, r *http.Request)
 	// Get Question Pack attempt list for basic role
 	BasicGetQuestionPackAttemptList(w http.ResponseWriter, r *http.Request)
+	// Get finished Question Pack attempt review for basic role
+	BasicGetQuestionPackAttemptDetail(w http.ResponseWriter, r *http.Request)
+	// Get Question Pack attempt list for admin
+	AdminGetQuestionPackAttemptList(w http.ResponseWriter, r *http.Request)
+	// Get Question Pack attempt with score breakdown for admin
+	AdminGetQuestionPackAttempt(w http.ResponseWriter, r *http.Request)
 }
 
-func NewQuestionPackHandler(db *gorm.DB) QuestionPackHandler {
+func NewQuestionPackHandler(db *gorm.DB, minio minio.MinioStorageContract) QuestionPackHandler {
 	return &questionPack{
-		questionPackUsecase: usecase.NewQuestionPackUsecase(db),
+		questionPackUsecase: usecase.NewQuestionPackUsecase(db, minio),
 		name:                "QUestion Pack Handler",
 	}
 }
@@ -115,6 +125,16 @@ func (q *questionPack) GetDetail(w http.ResponseWriter, r *http.Request) {
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
+func (q *questionPack) BasicGetDetail(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	id := chi.URLParam(r, "id")
+	idx, _ := strconv.Atoi(id)
+
+	resp := q.questionPackUsecase.StudentDetail(idx)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
 func (q *questionPack) Create(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 
@@ -227,7 +247,7 @@ func (q *questionPack) BasicTakeQuestionPack(w http.ResponseWriter, r *http.Requ
 	var param params.QuestionPackAttemptTakeParam
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 
 	if err := json.Decode(r.Body, &param); err != nil {
@@ -250,10 +270,10 @@ func (q *questionPack) BasicTakeQuestionPack(w http.ResponseWriter, r *http.Requ
 
 func (q *questionPack) BasicFinishQuestionPack(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
-	var param params.QuestionPackAttemptTakeParam
+	var param params.QuestionPackAttemptFinishParam
 	ctx := appctx.NewResponse()
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 
 	if err := json.Decode(r.Body, &param); err != nil {
@@ -263,6 +283,11 @@ func (q *questionPack) BasicFinishQuestionPack(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if param.QuestionPackAttemptID == 0 && param.LegacyQuestionPackAttemptID != 0 {
+		logrus.Warn(fmt.Sprintf("[%s][BasicFinishQuestionPack] deprecated question_pack_id is used, send question_pack_attempt_id instead", q.name))
+		param.QuestionPackAttemptID = param.LegacyQuestionPackAttemptID
+	}
+
 	if err := validator.Validate(param); err != nil {
 		logrus.Error(err.Error())
 		ctx = ctx.WithErrors(err.Error())
@@ -270,7 +295,7 @@ func (q *questionPack) BasicFinishQuestionPack(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	resp := q.questionPackUsecase.FinishQuestionPack(param.QuestionPackID, param.UserID)
+	resp := q.questionPackUsecase.FinishQuestionPack(param.QuestionPackAttemptID, param.UserID)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
@@ -294,9 +319,55 @@ func (q *questionPack) BasicGetQuestionPackAttemptList(w http.ResponseWriter, r
 		return
 	}
 
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 
 	resp := q.questionPackUsecase.GetAttemptList(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
+
+func (q *questionPack) BasicGetQuestionPackAttemptDetail(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	id := chi.URLParam(r, "id")
+	idx, _ := strconv.Atoi(id)
+
+	userID := appctx.UserID(r.Context())
+
+	resp := q.questionPackUsecase.GetQuestionPackAttemptDetail(idx, userID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionPack) AdminGetQuestionPackAttemptList(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	var param params.QuestionPackAttemptFilterParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error())
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error())
+	}
+
+	if len(ctx.Errors) > 0 {
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := q.questionPackUsecase.GetAttemptList(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionPack) AdminGetQuestionPackAttempt(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	id := chi.URLParam(r, "id")
+	idx, _ := strconv.Atoi(id)
+
+	resp := q.questionPackUsecase.GetAttempt(idx)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type questionReview struct {
+	handler Handler
+	usecase usecase.QuestionReviewUsecase
+	name    string
+}
+
+type QuestionReviewHandler interface {
+	// Contributor submits question for review
+	Submit(w http.ResponseWriter, r *http.Request)
+	// Contributor withdraws submitted question
+	Withdraw(w http.ResponseWriter, r *http.Request)
+	// Contributor gets review status and comments of own question
+	DetailByContributor(w http.ResponseWriter, r *http.Request)
+	// Admin gets review queue
+	Queue(w http.ResponseWriter, r *http.Request)
+	// Admin gets review status, comments and audit trail
+	Detail(w http.ResponseWriter, r *http.Request)
+	// Admin assigns reviewer
+	Assign(w http.ResponseWriter, r *http.Request)
+	// Reviewer approves question
+	Approve(w http.ResponseWriter, r *http.Request)
+	// Reviewer rejects question
+	Reject(w http.ResponseWriter, r *http.Request)
+	// Reviewer comments on question
+	Comment(w http.ResponseWriter, r *http.Request)
+}
+
+func NewQuestionReviewHandler(db *gorm.DB) QuestionReviewHandler {
+	return &questionReview{
+		name:    "Question Review Handler",
+		usecase: usecase.NewQuestionReviewUsecase(db),
+	}
+}
+
+func (q *questionReview) Submit(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Submit] is executed", q.name))
+	startTime := time.Now()
+
+	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	userID := appctx.UserID(r.Context())
+
+	resp := q.usecase.Submit(questionID, userID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Withdraw(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Withdraw] is executed", q.name))
+	startTime := time.Now()
+
+	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	userID := appctx.UserID(r.Context())
+
+	resp := q.usecase.Withdraw(questionID, userID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) DetailByContributor(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Detail By Contributor] is executed", q.name))
+	startTime := time.Now()
+
+	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	userID := appctx.UserID(r.Context())
+
+	resp := q.usecase.Detail(questionID, userID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Queue(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Queue] is executed", q.name))
+	startTime := time.Now()
+
+	var param params.QuestionReviewQueueParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := q.usecase.Queue(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Detail(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Detail] is executed", q.name))
+	startTime := time.Now()
+
+	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := q.usecase.Detail(questionID, 0)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Assign(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Assign] is executed", q.name))
+	startTime := time.Now()
+
+	var param params.QuestionReviewAssignParam
+	ctx := appctx.NewResponse()
+
+	// Empty body assigns the question to the caller
+	if r.ContentLength != 0 {
+		if err := json.Decode(r.Body, &param); err != nil {
+			logrus.Error("Cannot decode json")
+			ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+			q.handler.Response(w, *ctx, startTime, time.Now())
+			return
+		}
+	}
+
+	param.QuestionID, _ = strconv.Atoi(chi.URLParam(r, "id"))
+	param.ActorID = appctx.UserID(r.Context())
+
+	resp := q.usecase.Assign(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Approve(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Approve] is executed", q.name))
+	startTime := time.Now()
+
+	param, ok := q.decisionParam(w, r, startTime)
+	if !ok {
+		return
+	}
+
+	resp := q.usecase.Approve(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Reject(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Reject] is executed", q.name))
+	startTime := time.Now()
+
+	param, ok := q.decisionParam(w, r, startTime)
+	if !ok {
+		return
+	}
+
+	resp := q.usecase.Reject(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionReview) Comment(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Comment] is executed", q.name))
+	startTime := time.Now()
+
+	var param params.QuestionReviewCommentParam
+	ctx := appctx.NewResponse()
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error("Cannot decode json")
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	param.QuestionID, _ = strconv.Atoi(chi.URLParam(r, "id"))
+	param.UserID = appctx.UserID(r.Context())
+
+	resp := q.usecase.Comment(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+// decisionParam reads approve and reject body, an empty body is allowed
+func (q *questionReview) decisionParam(w http.ResponseWriter, r *http.Request, startTime time.Time) (params.QuestionReviewDecisionParam, bool) {
+	var param params.QuestionReviewDecisionParam
+	ctx := appctx.NewResponse()
+
+	if r.ContentLength != 0 {
+		if err := json.Decode(r.Body, &param); err != nil {
+			logrus.Error("Cannot decode json")
+			ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+			q.handler.Response(w, *ctx, startTime, time.Now())
+			return param, false
+		}
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return param, false
+	}
+
+	param.QuestionID, _ = strconv.Atoi(chi.URLParam(r, "id"))
+	param.ActorID = appctx.UserID(r.Context())
+
+	return param, true
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type questionRevision struct {
+	handler Handler
+	usecase usecase.QuestionRevisionUsecase
+	name    string
+}
+
+type QuestionRevisionHandler interface {
+	// Get list of question revisions
+	List(w http.ResponseWriter, r *http.Request)
+	// Get revision detail
+	Detail(w http.ResponseWriter, r *http.Request)
+	// Compare two revisions
+	Diff(w http.ResponseWriter, r *http.Request)
+	// Roll question back to a revision
+	Rollback(w http.ResponseWriter, r *http.Request)
+}
+
+func NewQuestionRevisionHandler(db *gorm.DB) QuestionRevisionHandler {
+	return &questionRevision{
+		name:    "Question Revision Handler",
+		usecase: usecase.NewQuestionRevisionUsecase(db),
+	}
+}
+
+func (q *questionRevision) List(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", q.name))
+	startTime := time.Now()
+
+	var param params.QuestionRevisionFilterParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	param.QuestionID, _ = strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := q.usecase.List(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionRevision) Detail(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Detail] is executed", q.name))
+	startTime := time.Now()
+
+	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	revisionID, _ := strconv.Atoi(chi.URLParam(r, "revisionID"))
+
+	resp := q.usecase.Detail(questionID, revisionID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionRevision) Diff(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Diff] is executed", q.name))
+	startTime := time.Now()
+
+	var param params.QuestionRevisionDiffParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		q.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	param.QuestionID, _ = strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := q.usecase.Diff(param)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (q *questionRevision) Rollback(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Rollback] is executed", q.name))
+	startTime := time.Now()
+
+	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	revisionID, _ := strconv.Atoi(chi.URLParam(r, "revisionID"))
+	userID := appctx.UserID(r.Context())
+
+	resp := q.usecase.Rollback(questionID, revisionID, userID)
+	q.handler.Response(w, resp, startTime, time.Now())
+}
@@ -69,6 +69,7 @@ func (q *questionSolution) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	param.ActorID = appctx.UserID(r.Context())
 	resp := q.questionSolutionUsecase.Create(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
@@ -119,6 +120,7 @@ func (q *questionSolution) CreateWithUploadFile(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	param.ActorID = appctx.UserID(r.Context())
 	resp := q.questionSolutionUsecase.CreateWithFile(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
@@ -172,6 +174,7 @@ func (q *questionSolution) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	param.ActorID = appctx.UserID(r.Context())
 	resp := q.questionSolutionUsecase.Update(param)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
@@ -183,7 +186,9 @@ func (q *questionSolution) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	ID, _ := strconv.Atoi(id)
 
-	resp := q.questionSolutionUsecase.Delete(ID)
+	userID := appctx.UserID(r.Context())
+
+	resp := q.questionSolutionUsecase.Delete(ID, userID)
 	q.handler.Response(w, resp, startTime, time.Now())
 }
 
@@ -10,6 +10,7 @@ import (
 	"gitlab.com/project-quiz/internal/params"
 	"gitlab.com/project-quiz/internal/usecase"
 	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/minio"
 	"gitlab.com/project-quiz/utils/validator"
 
 	"gorm.io/gorm"
@@ -37,9 +38,9 @@ type UserHandler interface {
 	Delete(w http.ResponseWriter, r *http.Request)
 }
 
-func NewUserHandler(db *gorm.DB) UserHandler {
+func NewUserHandler(db *gorm.DB, minio minio.MinioStorageContract) UserHandler {
 	return &user{
-		usecase: usecase.NewUserUsecase(db),
+		usecase: usecase.NewUserUsecase(db, minio),
 		name:    "USER HANDLER",
 	}
 }
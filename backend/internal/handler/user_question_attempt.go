@@ -9,9 +9,11 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
 	"gitlab.com/project-quiz/internal/usecase"
 	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/minio"
 	"gitlab.com/project-quiz/utils/validator"
 	"gorm.io/gorm"
 )
@@ -30,10 +32,10 @@ type UserQuestionAttemptHandler interface {
 	GetLatestAnswer(w http.ResponseWriter, r *http.Request)
 }
 
-func NewUserQuestionAttemptHandler(db *gorm.DB) UserQuestionAttemptHandler {
+func NewUserQuestionAttemptHandler(db *gorm.DB, minio minio.MinioStorageContract, milestones entities.StreakMilestones) UserQuestionAttemptHandler {
 	return &userQuestionAttempt{
 		name:           "User Question Attempt Handler",
-		attemptUsecase: usecase.NewUserQuestionAttemptUsecase(db),
+		attemptUsecase: usecase.NewUserQuestionAttemptUsecase(db, minio, milestones),
 	}
 }
 
@@ -41,7 +43,7 @@ func (u *userQuestionAttempt) MarkLatestAttempt(w http.ResponseWriter, r *http.R
 	startTime := time.Now()
 
 	var param params.AttemptSubmitAnswerQuestionParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 	ctx := appctx.NewResponse()
 
@@ -68,7 +70,7 @@ func (u *userQuestionAttempt) AnswerQuestion(w http.ResponseWriter, r *http.Requ
 	startTime := time.Now()
 
 	var param params.AttemptAnswerQuestionParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 	ctx := appctx.NewResponse()
 
@@ -95,7 +97,7 @@ func (u *userQuestionAttempt) ClearAnswer(w http.ResponseWriter, r *http.Request
 	startTime := time.Now()
 
 	var param params.AttemptClearAnswerQuestionParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 	ctx := appctx.NewResponse()
 
@@ -122,7 +124,7 @@ func (u *userQuestionAttempt) SubmitAnswer(w http.ResponseWriter, r *http.Reques
 	startTime := time.Now()
 
 	var param params.AttemptSubmitAnswerQuestionParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	param.UserID = userID
 	ctx := appctx.NewResponse()
 
@@ -149,12 +151,20 @@ func (u *userQuestionAttempt) GetLatestAnswer(w http.ResponseWriter, r *http.Req
 	startTime := time.Now()
 
 	var param params.AttemptClearAnswerQuestionParam
-	userID, _ := strconv.Atoi(r.Header.Get("user"))
+	userID := appctx.UserID(r.Context())
 	questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 	param.UserID = userID
 	param.QuestionID = questionID
 	ctx := appctx.NewResponse()
 
+	if packAttemptID := r.URL.Query().Get("question_pack_attempt_id"); packAttemptID != "" {
+		id, err := strconv.Atoi(packAttemptID)
+		if err != nil {
+			ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		}
+		param.QuestionPackAttemptID = &id
+	}
+
 	if err := validator.Validate(param); err != nil {
 		logrus.Error(fmt.Sprintf("[%s] %s", u.name, err.Error()))
 		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/usecase"
+
+	"gorm.io/gorm"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type userSession struct {
+	handler Handler
+	usecase usecase.UserSessionUsecase
+	name    string
+}
+
+type UserSessionHandler interface {
+	// List active sessions of authenticated user
+	List(w http.ResponseWriter, r *http.Request)
+	// Revoke session of authenticated user
+	Revoke(w http.ResponseWriter, r *http.Request)
+	// List active sessions of any user
+	AdminList(w http.ResponseWriter, r *http.Request)
+	// Revoke session of any user
+	AdminRevoke(w http.ResponseWriter, r *http.Request)
+}
+
+func NewUserSessionHandler(db *gorm.DB) UserSessionHandler {
+	return &userSession{
+		usecase: usecase.NewUserSessionUsecase(db),
+		name:    "USER SESSION HANDLER",
+	}
+}
+
+func (u *userSession) List(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", u.name))
+	startTime := time.Now()
+
+	principal, _ := appctx.PrincipalFrom(r.Context())
+	resp := u.usecase.List(principal.UserID, principal.SessionID)
+
+	u.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (u *userSession) Revoke(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Revoke] is executed", u.name))
+	startTime := time.Now()
+
+	userID := appctx.UserID(r.Context())
+	ID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	resp := u.usecase.Revoke(userID, ID, entities.SessionRevokedByUser)
+
+	u.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (u *userSession) AdminList(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][AdminList] is executed", u.name))
+	startTime := time.Now()
+
+	userID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	resp := u.usecase.List(userID, 0)
+
+	u.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (u *userSession) AdminRevoke(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][AdminRevoke] is executed", u.name))
+	startTime := time.Now()
+
+	userID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	ID, _ := strconv.Atoi(chi.URLParam(r, "sessionID"))
+	resp := u.usecase.Revoke(userID, ID, entities.SessionRevokedByAdmin)
+
+	u.handler.Response(w, resp, startTime, time.Now())
+}
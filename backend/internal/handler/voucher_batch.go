@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/json"
+	"gitlab.com/project-quiz/utils/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type voucherBatch struct {
+	handler Handler
+	usecase usecase.VoucherBatchUsecase
+	name    string
+}
+
+type VoucherBatchHandler interface {
+	// Generate a batch of vouchers
+	Create(w http.ResponseWriter, r *http.Request)
+	// Get list of voucher batches
+	List(w http.ResponseWriter, r *http.Request)
+	// Get detail of voucher batch
+	Detail(w http.ResponseWriter, r *http.Request)
+	// Download vouchers of a batch as CSV
+	Export(w http.ResponseWriter, r *http.Request)
+	// Revoke vouchers of a batch
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+func NewVoucherBatchHandler(db *gorm.DB) VoucherBatchHandler {
+	return &voucherBatch{
+		name:    "Voucher Batch Handler",
+		usecase: usecase.NewVoucherBatchUsecase(db),
+	}
+}
+
+func (v *voucherBatch) Create(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Create] is executed", v.name))
+	startTime := time.Now()
+
+	var param params.VoucherBatchCreateParam
+	ctx := appctx.NewResponse()
+
+	if err := json.Decode(r.Body, &param); err != nil {
+		logrus.Error("Cannot decode json")
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		v.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	if err := validator.Validate(param); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		v.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := v.usecase.Create(param)
+	v.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (v *voucherBatch) List(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", v.name))
+	startTime := time.Now()
+
+	var param params.VoucherBatchFilterParam
+	ctx := appctx.NewResponse()
+
+	if err := decoder.Decode(&param, r.URL.Query()); err != nil {
+		logrus.Error(err.Error())
+		ctx = ctx.WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		v.handler.Response(w, *ctx, startTime, time.Now())
+		return
+	}
+
+	resp := v.usecase.List(param)
+	v.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (v *voucherBatch) Detail(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Detail] is executed", v.name))
+	startTime := time.Now()
+
+	batchID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := v.usecase.Detail(batchID)
+	v.handler.Response(w, resp, startTime, time.Now())
+}
+
+func (v *voucherBatch) Export(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Export] is executed", v.name))
+	startTime := time.Now()
+
+	batchID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := v.usecase.Export(batchID)
+	v.handler.File(w, resp, startTime, time.Now())
+}
+
+func (v *voucherBatch) Revoke(w http.ResponseWriter, r *http.Request) {
+	logrus.Info(fmt.Sprintf("[%s][Revoke] is executed", v.name))
+	startTime := time.Now()
+
+	batchID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	resp := v.usecase.Revoke(batchID)
+	v.handler.Response(w, resp, startTime, time.Now())
+}
@@ -1,66 +1,75 @@
 package middleware
 
 import (
-	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"gitlab.com/project-quiz/internal/appctx"
 	"gitlab.com/project-quiz/internal/entities"
 	h "gitlab.com/project-quiz/internal/handler"
+	"gitlab.com/project-quiz/internal/rbac"
 	"gitlab.com/project-quiz/internal/repository"
 	"gitlab.com/project-quiz/utils/jwt"
 	p "gitlab.com/project-quiz/utils/password"
 
 	"gorm.io/gorm"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/sirupsen/logrus"
 )
 
-func Authorization(db *gorm.DB) func(handler http.Handler) http.Handler {
+// Authorization authenticate the request and put the principal of the user into the request context.
+// User header sent by client is removed, the user is only read from the context.
+func Authorization(db *gorm.DB, enforcer *rbac.Enforcer) func(handler http.Handler) http.Handler {
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewUserSessionRepository(db)
+
 	return func(handler http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			logrus.Info("Authorization middleware is executed")
 			startTime := time.Now()
 			hd := &h.Handler{}
-
-			// Enforce Route
-			e_route, err := casbin.NewEnforcer("./internal/config/casbin/route_model.conf", "./internal/config/casbin/route_policy.csv")
-			if err != nil {
-				log.Fatal(err)
-			}
+			r.Header.Del("user")
 
 			var user entities.User
-			var roles []entities.Role
-			userRepo := repository.NewUserRepository(db)
+			var claims *jwt.JWTClaims
 
-			if res, _ := e_route.Enforce(r.URL.Path, r.Method); res {
+			// Enforce Route
+			if enforcer.IsProtected(r.URL.Path, r.Method) {
 				authHeader := r.Header.Get("Authorization")
 				if strings.Contains(authHeader, "Bearer") {
 					logrus.Info("JWT authorization")
 
 					// Parse token
 					token := strings.ReplaceAll(authHeader, "Bearer ", "")
-					claims, err := jwt.ParseToken(token)
+					var err error
+					claims, err = jwt.ParseAccessToken(token)
 					if err != nil {
 						resp := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusUnauthorized)
 						hd.Response(w, *resp, startTime, time.Now())
 						return
 					}
 
+					// Reject token of revoked session
+					revoked, err := sessionRepo.IsAccessTokenRevoked(claims.ID)
+					if err != nil {
+						resp := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusInternalServerError)
+						hd.Response(w, *resp, startTime, time.Now())
+						return
+					}
+					if revoked {
+						resp := appctx.NewResponse().WithErrors("token has been revoked").WithCode(http.StatusUnauthorized)
+						hd.Response(w, *resp, startTime, time.Now())
+						return
+					}
+
 					// Get User
-					user, err := userRepo.Get(user, claims.UserID)
+					user, err = userRepo.Get(user, claims.UserID)
 					if err != nil {
 						resp := appctx.NewResponse().WithErrors("user not found").WithCode(http.StatusUnauthorized)
 						hd.Response(w, *resp, startTime, time.Now())
 						return
 					}
-					roles = user.Roles
-
-					r.Header.Add("user", strconv.Itoa(user.ID))
 				} else if strings.Contains(authHeader, "Basic") {
 					logrus.Info("Basic Auth authorization")
 					username, password, ok := r.BasicAuth()
@@ -71,22 +80,19 @@ func Authorization(db *gorm.DB) func(handler http.Handler) http.Handler {
 					}
 
 					// Get User
-					user, err := userRepo.GetByEmail(username)
+					var err error
+					user, err = userRepo.GetByEmail(username)
 					if err != nil {
 						resp := appctx.NewResponse().WithErrors("user not found").WithCode(http.StatusUnauthorized)
 						hd.Response(w, *resp, startTime, time.Now())
 						return
 					}
-					roles = user.Roles
 
 					if match := p.CheckPasswordHash(password, user.Password); !match {
 						resp := appctx.NewResponse().WithErrors("wrong password").WithCode(http.StatusUnauthorized)
 						hd.Response(w, *resp, startTime, time.Now())
 						return
 					}
-
-					r.Header.Add("user", strconv.Itoa(user.ID))
-
 				} else {
 					logrus.Error("Wrong authorizatio header")
 					resp := appctx.NewResponse().WithErrors("Wrong authorization header").WithCode(http.StatusBadRequest)
@@ -94,28 +100,53 @@ func Authorization(db *gorm.DB) func(handler http.Handler) http.Handler {
 					return
 				}
 
-				e, err := casbin.NewEnforcer("./internal/config/casbin/auth_model.conf", "./internal/config/casbin/policy.csv")
-				if err != nil {
-					log.Fatal(err)
-				}
-
-				isAuthorized := false
-
-				for _, role := range roles {
-					if res, _ := e.Enforce(role.Name, r.URL.Path, r.Method); res {
-						isAuthorized = true
-						break
-					}
-				}
-
-				if !isAuthorized {
+				principal := principalOf(user)
+				principal.SessionID, principal.TokenID = sessionOf(r, user, claims, sessionRepo)
+				if !enforcer.IsAllowed(principal.Roles, r.URL.Path, r.Method) {
 					resp := appctx.NewResponse().WithErrors("Unauthorized role").WithCode(http.StatusForbidden)
 					hd.Response(w, *resp, startTime, time.Now())
 					return
 				}
+
+				r = r.WithContext(appctx.WithPrincipal(r.Context(), principal))
 			}
 
 			handler.ServeHTTP(w, r)
 		})
 	}
 }
+
+// sessionOf returns the session and token ID of the request and updates last seen of the session.
+// Basic auth has no token, requests of the same device share a session.
+func sessionOf(r *http.Request, user entities.User, claims *jwt.JWTClaims, sessionRepo repository.UserSessionRepository) (int, string) {
+	client := h.ClientOf(r)
+
+	if claims != nil {
+		if claims.SessionID != 0 {
+			sessionRepo.Touch(claims.SessionID, client.IPAddress)
+		}
+		return claims.SessionID, claims.ID
+	}
+
+	session, err := sessionRepo.FirstOrCreateBasic(entities.UserSession{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	})
+	if err != nil {
+		return 0, ""
+	}
+	sessionRepo.Touch(session.ID, client.IPAddress)
+
+	return session.ID, ""
+}
+
+// principalOf collects the roles of the user. Entitlements are loaded by the routes which check them.
+func principalOf(user entities.User) appctx.Principal {
+	principal := appctx.Principal{UserID: user.ID}
+	for _, role := range user.Roles {
+		principal.Roles = append(principal.Roles, role.Name)
+	}
+
+	return principal
+}
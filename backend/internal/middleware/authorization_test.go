@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/rbac"
+)
+
+// memoryCasbinRules keeps role policies in memory instead of the database
+type memoryCasbinRules struct {
+	persist.Adapter
+	rules [][]string
+}
+
+func (m *memoryCasbinRules) LoadPolicy(model model.Model) error {
+	for _, rule := range m.rules {
+		if err := persist.LoadPolicyArray(rule, model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryCasbinRules) AddPolicy(sec string, ptype string, rule []string) error {
+	m.rules = append(m.rules, append([]string{ptype}, rule...))
+	return nil
+}
+
+func (m *memoryCasbinRules) Count() (int64, error) {
+	return int64(len(m.rules)), nil
+}
+
+func TestAuthorizationIgnoresUserHeader(t *testing.T) {
+	enforcer, err := rbac.NewEnforcerWithAdapter(&memoryCasbinRules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if header := r.Header.Get("user"); header != "" {
+			t.Errorf("expected user header to be removed, got %q", header)
+		}
+		if userID := appctx.UserID(r.Context()); userID != 0 {
+			t.Errorf("expected no authenticated user, got %d", userID)
+		}
+	})
+	authorization := Authorization(nil, enforcer)(next)
+
+	// Public route reaches the handler without the forged user
+	r := httptest.NewRequest(http.MethodGet, "/public/v1/material", nil)
+	r.Header.Set("user", "1")
+	authorization.ServeHTTP(httptest.NewRecorder(), r)
+	if !called {
+		t.Fatal("expected public route to reach the handler")
+	}
+
+	// Protected route is not authenticated by the forged user
+	called = false
+	r = httptest.NewRequest(http.MethodGet, "/basic/v1/auth/me", nil)
+	r.Header.Set("user", "1")
+	w := httptest.NewRecorder()
+	authorization.ServeHTTP(w, r)
+	if called || w.Code != http.StatusBadRequest {
+		t.Errorf("expected protected route to be rejected without authorization header, got %d", w.Code)
+	}
+}
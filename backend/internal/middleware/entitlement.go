@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gitlab.com/project-quiz/internal/appctx"
+	h "gitlab.com/project-quiz/internal/handler"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PremiumQuestionPack block non free question pack for user without active premium package.
+// Question pack ID is taken from the "id" URL param or "question_pack_id" of the JSON body.
+func PremiumQuestionPack(db *gorm.DB) func(handler http.Handler) http.Handler {
+	packRepo := repository.NewQuestionPackRepository(db)
+	premiumRepo := repository.NewPremiumPackageRepository(db)
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
+			hd := &h.Handler{}
+
+			packID, err := questionPackIDFromRequest(r)
+			if err != nil {
+				resp := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+				hd.Response(w, *resp, startTime, time.Now())
+				return
+			}
+
+			isFree, err := packRepo.IsFree(packID)
+			if err != nil {
+				// Let the handler answer not found question pack
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					handler.ServeHTTP(w, r)
+					return
+				}
+				resp := appctx.NewResponse().WithErrorObj(err)
+				hd.Response(w, *resp, startTime, time.Now())
+				return
+			}
+
+			if !isFree {
+				var premium bool
+				r, premium, err = withPremium(r, premiumRepo)
+				if err != nil {
+					resp := appctx.NewResponse().WithErrorObj(err)
+					hd.Response(w, *resp, startTime, time.Now())
+					return
+				}
+				if !premium {
+					resp := appctx.NewResponse().WithErrors("premium package is required to access this question pack").WithCode(http.StatusForbidden)
+					hd.Response(w, *resp, startTime, time.Now())
+					return
+				}
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PremiumSolution block premium only solution for user without active premium package.
+// Question ID is taken from the "id" URL param.
+func PremiumSolution(db *gorm.DB) func(handler http.Handler) http.Handler {
+	solutionRepo := repository.NewQuestionSolutionRepository(db)
+	premiumRepo := repository.NewPremiumPackageRepository(db)
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
+			hd := &h.Handler{}
+
+			questionID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+			solution, err := solutionRepo.Get(questionID)
+			if err != nil {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			if solution.IsPremium != nil && *solution.IsPremium {
+				var premium bool
+				r, premium, err = withPremium(r, premiumRepo)
+				if err != nil {
+					resp := appctx.NewResponse().WithErrorObj(err)
+					hd.Response(w, *resp, startTime, time.Now())
+					return
+				}
+				if !premium {
+					resp := appctx.NewResponse().WithErrors("premium package is required to access this solution").WithCode(http.StatusForbidden)
+					hd.Response(w, *resp, startTime, time.Now())
+					return
+				}
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// withPremium loads the premium entitlement of the authenticated user into the principal of the request.
+// It is only loaded by the routes which check it, not on every authenticated request.
+func withPremium(r *http.Request, premiumRepo repository.PremiumPackageRepository) (*http.Request, bool, error) {
+	principal, ok := appctx.PrincipalFrom(r.Context())
+	if !ok {
+		return r, false, nil
+	}
+	if principal.HasEntitlement(appctx.EntitlementPremium) {
+		return r, true, nil
+	}
+
+	if _, err := premiumRepo.GetActiveByUser(principal.UserID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return r, false, nil
+		}
+		return r, false, err
+	}
+
+	principal.Entitlements = append(principal.Entitlements, appctx.EntitlementPremium)
+	return r.WithContext(appctx.WithPrincipal(r.Context(), principal)), true, nil
+}
+
+func questionPackIDFromRequest(r *http.Request) (int, error) {
+	if id := chi.URLParam(r, "id"); id != "" {
+		return strconv.Atoi(id)
+	}
+
+	return idFromBody(r, "question_pack_id")
+}
+
+// idFromBody reads an ID field of the JSON body and puts the body back for the handler
+func idFromBody(r *http.Request, field string) (int, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var param map[string]json.RawMessage
+	if err := json.Unmarshal(body, &param); err != nil {
+		return 0, err
+	}
+
+	var id int
+	if value, ok := param[field]; ok {
+		if err := json.Unmarshal(value, &id); err != nil {
+			return 0, fmt.Errorf("%s: %w", field, err)
+		}
+	}
+
+	return id, nil
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gitlab.com/project-quiz/internal/appctx"
+	h "gitlab.com/project-quiz/internal/handler"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gorm.io/gorm"
+)
+
+// QuestionOwner block contributor from changing a question created by another contributor.
+// Question ID is taken from the "id" URL param or "question_id" of the JSON body.
+func QuestionOwner(db *gorm.DB) func(handler http.Handler) http.Handler {
+	return ownQuestion(db, func(r *http.Request) (int, error) {
+		if id := chi.URLParam(r, "id"); id != "" {
+			return strconv.Atoi(id)
+		}
+
+		return idFromBody(r, "question_id")
+	})
+}
+
+// QuestionOptionOwner block contributor from changing options of a question created by another contributor.
+// Option ID is taken from the "id" URL param, new option is checked by "question_id" of the JSON body.
+func QuestionOptionOwner(db *gorm.DB) func(handler http.Handler) http.Handler {
+	optionRepo := repository.NewQuestionOptionRepository(db)
+
+	return ownQuestion(db, func(r *http.Request) (int, error) {
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			return idFromBody(r, "question_id")
+		}
+
+		optionID, err := strconv.Atoi(id)
+		if err != nil {
+			return 0, err
+		}
+
+		option, err := optionRepo.Get(optionID)
+		if err != nil {
+			return 0, err
+		}
+
+		return option.QuestionID, nil
+	})
+}
+
+func ownQuestion(db *gorm.DB, questionIDOf func(r *http.Request) (int, error)) func(handler http.Handler) http.Handler {
+	questionRepo := repository.NewQuestionRepository(db, nil)
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
+			hd := &h.Handler{}
+
+			questionID, err := questionIDOf(r)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				resp := appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+				hd.Response(w, *resp, startTime, time.Now())
+				return
+			}
+
+			question, err := questionRepo.GetAnswerKey(questionID)
+			if err != nil {
+				// Let the handler answer not found question or option
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					handler.ServeHTTP(w, r)
+					return
+				}
+				resp := appctx.NewResponse().WithErrorObj(err)
+				hd.Response(w, *resp, startTime, time.Now())
+				return
+			}
+
+			if question.ContributorID != appctx.UserID(r.Context()) {
+				resp := appctx.NewResponse().WithErrors(usecase.ErrNotQuestionOwner.Error()).WithCode(http.StatusForbidden)
+				hd.Response(w, *resp, startTime, time.Now())
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
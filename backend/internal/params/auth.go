@@ -7,17 +7,26 @@ type AuthRegistrationParam struct {
 	ConfirmPassword string `json:"confirm_password" validate:"required"`
 }
 
+// SessionClient is the device of the request, set by handler
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
 type AuthLoginParam struct {
-	Email    string `json:"email" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Email    string        `json:"email" validate:"required"`
+	Password string        `json:"password" validate:"required"`
+	Client   SessionClient `json:"-"`
 }
 
 type AuthLoginGoogleParam struct {
-	Token string `json:"token" validate:"required"`
+	Token  string        `json:"token" validate:"required"`
+	Client SessionClient `json:"-"`
 }
 
 type AuthRefreshTokenParam struct {
-	Refresh string `json:"refresh" validate:"required"`
+	Refresh string        `json:"refresh" validate:"required"`
+	Client  SessionClient `json:"-"`
 }
 
 type AuthValidateEmailParams struct {
@@ -0,0 +1,37 @@
+package params
+
+import (
+	"mime/multipart"
+
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params/generics"
+)
+
+type BadgeFilter struct {
+	RuleType string `json:"rule_type" schema:"rule_type"`
+	IsActive *bool  `json:"is_active" schema:"is_active"`
+	generics.GenericFilter
+}
+
+// BadgeCreateParam is sent as multipart form, the icon is optional
+type BadgeCreateParam struct {
+	Name        string                `json:"name" schema:"name" validate:"required"`
+	Description string                `json:"description" schema:"description"`
+	RuleType    string                `json:"rule_type" schema:"rule_type" validate:"required,oneof=answer_count correct_answer_count pack_finish_count answer_streak daily_streak"`
+	Threshold   int                   `json:"threshold" schema:"threshold" validate:"required,gte=1"`
+	MaterialID  *int                  `json:"material_id" schema:"material_id"`
+	MinScore    float32               `json:"min_score" schema:"min_score" validate:"gte=0,lte=100"`
+	IsActive    bool                  `json:"is_active" schema:"is_active"`
+	Icon        *multipart.FileHeader `json:"-" schema:"-"`
+}
+
+type BadgeUpdateParam struct {
+	ID int `json:"id" schema:"-" validate:"required"`
+	BadgeCreateParam
+}
+
+// UserMeResponse is the authenticated user with the badges awarded to them
+type UserMeResponse struct {
+	entities.User
+	Badges []entities.UserBadge `json:"badges"`
+}
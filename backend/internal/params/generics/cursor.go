@@ -0,0 +1,13 @@
+package generics
+
+// CursorFilter opts a list into keyset pagination, which is set by pagination=cursor or by passing a cursor
+type CursorFilter struct {
+	Pagination string `json:"pagination" schema:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `json:"cursor" schema:"cursor"`
+	// Total count is skipped on keyset pagination unless asked for
+	WithCount bool `json:"with_count" schema:"with_count"`
+}
+
+func (c CursorFilter) IsCursor() bool {
+	return c.Pagination == "cursor" || c.Cursor != ""
+}
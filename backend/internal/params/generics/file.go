@@ -0,0 +1,10 @@
+package generics
+
+// FileResponse is a file returned by usecase to be written as download by handler
+type FileResponse struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+	// Extra headers sent with the file
+	Headers map[string]string `json:"-"`
+}
@@ -1,9 +1,24 @@
 package generics
 
+import "gitlab.com/project-quiz/utils/filter"
+
 type GenericFilter struct {
 	Q         string `json:"q" schema:"q"`
 	StartDate string `json:"start_date" schema:"start_date"`
 	EndDate   string `json:"end_date" schema:"end_date"`
+	Sort      string `json:"sort" schema:"sort"`
+	Order     string `json:"order" schema:"order" validate:"omitempty,oneof=asc desc ASC DESC"`
 	Page      int    `json:"page" schema:"page"`
 	Limit     int    `json:"limit" schema:"limit"`
 }
+
+// Filter is the keyword, date range and sort of the list
+func (g GenericFilter) Filter() filter.Param {
+	return filter.Param{
+		Q:         g.Q,
+		StartDate: g.StartDate,
+		EndDate:   g.EndDate,
+		Sort:      g.Sort,
+		Order:     g.Order,
+	}
+}
@@ -8,12 +8,14 @@ type MaterialFilterParam struct {
 }
 
 type MaterialCreateParam struct {
-	Name  string `json:"name"`
-	Level string `json:"level"`
+	Name       string `json:"name"`
+	Level      string `json:"level"`
+	CodePrefix string `json:"code_prefix" validate:"omitempty,alpha,max=16"`
 }
 
 type MaterialEditParam struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Level string `json:"level"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Level      string `json:"level"`
+	CodePrefix string `json:"code_prefix" validate:"omitempty,alpha,max=16"`
 }
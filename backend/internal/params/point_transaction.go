@@ -0,0 +1,18 @@
+package params
+
+import "gitlab.com/project-quiz/internal/params/generics"
+
+type PointAdjustParam struct {
+	UserID  int    `json:"user_id" validate:"required"`
+	Amount  int    `json:"amount" validate:"required,gt=0"`
+	Note    string `json:"note" validate:"required,max=500"`
+	ActorID int    `json:"-"`
+	// Retried request with the same key is applied once
+	IdempotencyKey string `json:"idempotency_key" validate:"max=100"`
+}
+
+type PointTransactionFilterParam struct {
+	UserID int    `json:"user_id" schema:"user_id"`
+	Reason string `json:"reason" schema:"reason"`
+	generics.GenericFilter
+}
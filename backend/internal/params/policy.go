@@ -0,0 +1,12 @@
+package params
+
+// Policy allows the role of subject to access routes matching object with action, "*" matches any action
+type Policy struct {
+	Subject string `json:"subject" validate:"required"`
+	Object  string `json:"object" validate:"required,startswith=/|eq=*"`
+	Action  string `json:"action" validate:"required,oneof=* GET POST PUT PATCH DELETE"`
+}
+
+type PolicyFilterParam struct {
+	Subject string `json:"subject" schema:"subject"`
+}
@@ -1,20 +1,32 @@
 package params
 
-import "gitlab.com/project-quiz/internal/params/generics"
+import (
+	"time"
+
+	"gitlab.com/project-quiz/internal/params/generics"
+)
 
 type PremiumPackageFilterParam struct {
-	UserID   int   `json:"user_id"`
-	IsActive *bool `json:"is_active"`
+	UserID     int   `json:"user_id" schema:"user_id"`
+	IsActive   *bool `json:"is_active" schema:"is_active"`
+	IsRedeemed *bool `json:"is_redeemed" schema:"is_redeemed"`
 	generics.GenericFilter
 }
 
 type PremiumPackageCreateParam struct {
-	UserID     int `json:"user_id" validate:"required"`
-	LongPeriod int `json:"long_period" validate:"required"`
+	// Package is active right away when user is given, otherwise it is a voucher
+	UserID     *int `json:"user_id"`
+	LongPeriod int  `json:"long_period" validate:"required,gt=0"`
 }
 
 type PremiumPackageUpdateParam struct {
-	ID         int `json:"id"`
-	UserID     int `json:"user_id" validate:"required"`
-	LongPeriod int `json:"long_period" validate:"required"`
+	ID          int        `json:"id"`
+	IsActive    *bool      `json:"is_active"`
+	ActiveUntil *time.Time `json:"active_until"`
+	LongPeriod  int        `json:"long_period" validate:"omitempty,gt=0"`
+}
+
+type PremiumPackageRedeemParam struct {
+	Token  string `json:"token" validate:"required"`
+	UserID int    `json:"user_id"`
 }
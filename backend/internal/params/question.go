@@ -12,28 +12,54 @@ type QuestionFilterParam struct {
 	Show            string `json:"show" schema:"show"`
 	ContributorID   int    `json:"contributor_id" schema:"contributor_id"`
 	IncludePackOnly *bool  `json:"include_pack_only" schema:"include_pack_only"`
+	ReviewStatus    string `json:"review_status" schema:"review_status" validate:"omitempty,oneof=draft submitted in_review approved rejected"`
+	// Show answer state of the given question pack attempt instead of free practice
+	QuestionPackAttemptID *int `json:"question_pack_attempt_id" schema:"question_pack_attempt_id"`
 	generics.GenericFilter
 }
 
 type QuestionDetailResponse struct {
-	ID              int                       `json:"id" gorm:"primaryKey"`
-	Body            string                    `json:"body"`
-	MaterialID      int                       `json:"material_id"`
-	IsImage         bool                      `json:"is_image"`
-	ImgPath         string                    `json:"img_path"`
-	QuestionOptions []entities.QuestionOption `json:"question_options,omitempty"`
-	IsAnswered      bool                      `json:"is_answered"`
-	AnswerID        int                       `json:"answer_id"`
-	IsAnswerTrue    bool                      `json:"is_answer_true"`
-	TrueAnswerID    int                       `json:"true_answer_id"`
-	IsMarked        bool                      `json:"is_marked"`
-	QuestionTags    []entities.QuestionTag    `json:"tags,omitempty"`
-	Code            string                    `json:"code"`
-	ImgPlacementUrl string                    `json:"img_placement_url"`
-	ContributorID   int                       `json:"contributor_id"`
+	ID              int                            `json:"id" gorm:"primaryKey"`
+	Body            string                         `json:"body"`
+	MaterialID      int                            `json:"material_id"`
+	Type            string                         `json:"type"`
+	IsImage         bool                           `json:"is_image"`
+	ImgPath         string                         `json:"img_path"`
+	QuestionOptions []QuestionOptionDetailResponse `json:"question_options,omitempty"`
+	// Match bodies of matching question, shuffled apart from their options
+	Matches      []QuestionMatchResponse `json:"matches,omitempty"`
+	IsAnswered   bool                    `json:"is_answered"`
+	AnswerID     int                     `json:"answer_id"`
+	OptionIDs    []int                   `json:"option_ids,omitempty"`
+	AnswerText   *string                 `json:"answer_text,omitempty"`
+	AnswerPairs  []entities.AnswerPair   `json:"answer_pairs,omitempty"`
+	IsAnswerTrue bool                    `json:"is_answer_true"`
+	TrueAnswerID int                     `json:"true_answer_id"`
+	// Correct options once the answer is submitted, in their correct order for ordering question
+	TrueAnswerIDs   []int                  `json:"true_answer_ids,omitempty"`
+	IsMarked        bool                   `json:"is_marked"`
+	QuestionTags    []entities.QuestionTag `json:"tags,omitempty"`
+	Code            string                 `json:"code"`
+	ImgPlacementUrl string                 `json:"img_placement_url"`
+	ContributorID   int                    `json:"contributor_id"`
 	base.Timestamp
 }
 
+// QuestionOptionDetailResponse is an option shown to student, it carries no part of the answer key
+type QuestionOptionDetailResponse struct {
+	ID         int    `json:"id"`
+	Body       string `json:"body"`
+	IsImage    bool   `json:"is_image"`
+	ImgPath    string `json:"img_path"`
+	QuestionID int    `json:"question_id"`
+}
+
+// QuestionMatchResponse is a match body of matching question, it is answered as match_option_id
+type QuestionMatchResponse struct {
+	OptionID int    `json:"option_id"`
+	Body     string `json:"body"`
+}
+
 type QuestionCreate struct {
 	Body            string                 `json:"body"`
 	MaterialID      int                    `json:"material_id"`
@@ -43,10 +69,19 @@ type QuestionCreate struct {
 	ImgPlacementUrl string                 `json:"img_placement_url"`
 	ContributorID   int                    `json:"contributor_id"`
 	IsPackOnly      *bool                  `json:"is_pack_only"`
+	Type            string                 `json:"type" validate:"omitempty,oneof=single_choice multiple_answer true_false numeric short_text ordering matching"`
+	ScoringMode     string                 `json:"scoring_mode" validate:"omitempty,oneof=all_or_nothing partial"`
+	// Answer key of numeric question
+	NumericAnswer    *float64 `json:"numeric_answer"`
+	NumericTolerance float64  `json:"numeric_tolerance" validate:"gte=0"`
+	// Answer key of short text question
+	AcceptedAnswers []string `json:"accepted_answers"`
+	CaseSensitive   bool     `json:"case_sensitive"`
 }
 
 type QuestionUpdate struct {
 	ID              int                    `json:"id" gorm:"primaryKey"`
+	ActorID         int                    `json:"-"`
 	Body            string                 `json:"body"`
 	MaterialID      int                    `json:"material_id"`
 	IsImage         bool                   `json:"is_image"`
@@ -55,6 +90,14 @@ type QuestionUpdate struct {
 	QuestionOptions []QuestionOptionUpdate `json:"question_options,omitempty"`
 	ContributorID   int                    `json:"contributor_id"`
 	IsPackOnly      *bool                  `json:"is_pack_only"`
+	Type            string                 `json:"type" validate:"omitempty,oneof=single_choice multiple_answer true_false numeric short_text ordering matching"`
+	ScoringMode     string                 `json:"scoring_mode" validate:"omitempty,oneof=all_or_nothing partial"`
+	// Answer key of numeric question
+	NumericAnswer    *float64 `json:"numeric_answer"`
+	NumericTolerance float64  `json:"numeric_tolerance" validate:"gte=0"`
+	// Answer key of short text question
+	AcceptedAnswers []string `json:"accepted_answers"`
+	CaseSensitive   bool     `json:"case_sensitive"`
 }
 
 type QuestionListResponse struct {
@@ -68,6 +111,7 @@ type QuestionListResponse struct {
 	IsMarked      bool   `json:"is_marked"`
 	Code          string `json:"code"`
 	ContributorID int    `json:"contributor_id"`
+	Snippet       string `json:"snippet,omitempty"`
 }
 
 type QuestionMarkAddRemoveParam struct {
@@ -77,11 +121,13 @@ type QuestionMarkAddRemoveParam struct {
 }
 
 type QuestionAddTags struct {
-	QuestionID int   `json:"question_id" validate:"required"`
-	TagIDs     []int `json:"tag_ids" validate:"required"`
+	QuestionID    int   `json:"question_id" validate:"required"`
+	TagIDs        []int `json:"tag_ids" validate:"required"`
+	ContributorID int   `json:"-"`
 }
 
 type QuestionRemoveTag struct {
-	QuestionID int `json:"question_id" validate:"required"`
-	TagID      int `json:"tag_id" validate:"required"`
+	QuestionID    int `json:"question_id" validate:"required"`
+	TagID         int `json:"tag_id" validate:"required"`
+	ContributorID int `json:"-"`
 }
@@ -0,0 +1,19 @@
+package params
+
+type QuestionExportParam struct {
+	Format      string `json:"format" schema:"format" validate:"required,oneof=qti moodle_xml gift"`
+	MaterialID  int    `json:"material_id" schema:"material_id"`
+	QuestionIDs []int  `json:"question_ids" schema:"question_ids"`
+	Show        string `json:"show" schema:"show" validate:"omitempty,oneof=active inactive"`
+}
+
+type QuestionBankImportParam struct {
+	Format   string `validate:"required,oneof=qti moodle_xml gift"`
+	FileName string
+	// Material of questions whose category is empty or unknown
+	MaterialID    int
+	ContributorID int
+	IsAdmin       bool
+	// Validate the questions without creating anything
+	DryRun bool
+}
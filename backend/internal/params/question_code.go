@@ -0,0 +1,30 @@
+package params
+
+type QuestionCodeResequenceParam struct {
+	// Only report what would change
+	DryRun bool
+}
+
+type QuestionCodeBackfillParam struct {
+	// Only report what would change
+	DryRun bool
+	// Number of questions given a code in one transaction
+	BatchSize int
+	// Create unique index on question code when there is no duplicate left
+	UniqueIndex bool
+}
+
+type QuestionCodePrefixSummary struct {
+	Prefix string `json:"prefix"`
+	Total  int    `json:"total"`
+	// Questions whose code differs from their place in the sequence
+	Changed int `json:"changed"`
+}
+
+type QuestionCodeReport struct {
+	DryRun      bool                        `json:"dry_run"`
+	Prefixes    []QuestionCodePrefixSummary `json:"prefixes,omitempty"`
+	Backfilled  int                         `json:"backfilled"`
+	Duplicates  []string                    `json:"duplicates"`
+	UniqueIndex bool                        `json:"unique_index"`
+}
@@ -0,0 +1,26 @@
+package params
+
+type QuestionImportParam struct {
+	FileName      string
+	ContributorID int
+	IsAdmin       bool
+	// Validate the rows without creating anything
+	DryRun bool
+}
+
+type QuestionImportRowResult struct {
+	Row        int      `json:"row"`
+	Body       string   `json:"body"`
+	QuestionID int      `json:"question_id,omitempty"`
+	Code       string   `json:"code,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+type QuestionImportResponse struct {
+	DryRun  bool                      `json:"dry_run"`
+	Total   int                       `json:"total"`
+	Valid   int                       `json:"valid"`
+	Invalid int                       `json:"invalid"`
+	Created int                       `json:"created"`
+	Rows    []QuestionImportRowResult `json:"rows"`
+}
@@ -5,6 +5,8 @@ type QuestionOptionCreate struct {
 	OptionValue bool   `json:"option_value"`
 	IsImage     bool   `json:"is_image"`
 	ImgPath     string `json:"img_path"`
+	Position    *int   `json:"position" validate:"omitempty,gt=0"`
+	MatchBody   string `json:"match_body"`
 }
 
 type QuestionOptionAdd struct {
@@ -12,7 +14,12 @@ type QuestionOptionAdd struct {
 	OptionValue bool   `json:"option_value"`
 	IsImage     bool   `json:"is_image"`
 	ImgPath     string `json:"img_path"`
+	Position    *int   `json:"position" validate:"omitempty,gt=0"`
+	MatchBody   string `json:"match_body"`
 	QuestionID  int    `json:"question_id"`
+	ActorID     int    `json:"-"`
+	// Set for options changed by the contributor
+	ContributorID int `json:"-"`
 }
 
 type QuestionOptionUpdate struct {
@@ -21,5 +28,10 @@ type QuestionOptionUpdate struct {
 	OptionValue bool   `json:"option_value"`
 	IsImage     bool   `json:"is_image"`
 	ImgPath     string `json:"img_path"`
+	Position    *int   `json:"position" validate:"omitempty,gt=0"`
+	MatchBody   string `json:"match_body"`
 	QuestionID  int    `json:"question_id"`
+	ActorID     int    `json:"-"`
+	// Set for options changed by the contributor
+	ContributorID int `json:"-"`
 }
@@ -3,8 +3,13 @@ package params
 import "gitlab.com/project-quiz/internal/params/generics"
 
 type QuestionPackCreateParam struct {
-	Name      string `json:"name"`
-	TimeLimit int    `json:"time_limit"`
+	Name         string   `json:"name"`
+	TimeLimit    int      `json:"time_limit"`
+	CorrectPoint *float32 `json:"correct_point" validate:"omitempty,gte=0"`
+	WrongPoint   *float32 `json:"wrong_point" validate:"omitempty,lte=0"`
+	BlankPoint   *float32 `json:"blank_point"`
+	MaxAttempts  *int     `json:"max_attempts" validate:"omitempty,gte=0"`
+	Cooldown     *int     `json:"cooldown" validate:"omitempty,gte=0"`
 }
 
 type QuestionPackUpdateParam struct {
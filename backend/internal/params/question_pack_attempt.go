@@ -1,9 +1,13 @@
 package params
 
-import "gitlab.com/project-quiz/internal/params/generics"
+import (
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params/generics"
+)
 
 type QuestionPackAttemptFilterParam struct {
 	generics.GenericFilter
+	generics.CursorFilter
 	UserID         int   `json:"user_id" schema:"user_id"`
 	QuestionPackID int   `json:"question_pack_id" schema:"question_pack_id"`
 	IsFinish       *bool `json:"is_finish" shcema:"is_finish"`
@@ -16,5 +20,41 @@ type QuestionPackAttemptTakeParam struct {
 
 type QuestionPackAttemptFinishParam struct {
 	QuestionPackAttemptID int `json:"question_pack_attempt_id" validate:"required"`
-	UserID                int `json:"user_id"`
+	// Deprecated: clients used to send the attempt ID as question_pack_id,
+	// it is accepted until they move to question_pack_attempt_id
+	LegacyQuestionPackAttemptID int `json:"question_pack_id"`
+	UserID                      int `json:"user_id"`
+}
+
+// QuestionPackFinishResponse is the finished attempt with the badges awarded for finishing it
+type QuestionPackFinishResponse struct {
+	entities.QuestionPackAttempt
+	Badges []entities.Badge `json:"badges"`
+}
+
+type QuestionPackAttemptDetailResponse struct {
+	Attempt      entities.QuestionPackAttempt        `json:"attempt"`
+	QuestionPack entities.QuestionPack               `json:"question_pack"`
+	Questions    []QuestionPackAttemptQuestionDetail `json:"questions"`
+}
+
+type QuestionPackAttemptQuestionDetail struct {
+	Question     entities.Question `json:"question"`
+	AnswerID     *int              `json:"answer_id"`
+	TrueAnswerID int               `json:"true_answer_id"`
+	// Answer and correct options of question types other than single choice
+	Answer        *entities.UserQuestionAttempt `json:"answer,omitempty"`
+	TrueAnswerIDs []int                         `json:"true_answer_ids,omitempty"`
+	Status        string                        `json:"status"`
+	Point         float32                       `json:"point"`
+	TimeSpent     int                           `json:"time_spent"`
+	Solution      *entities.QuestionSolution    `json:"solution"`
+}
+
+type QuestionPackTakeResponse struct {
+	entities.QuestionPackAttempt
+	Answers []entities.UserQuestionAttempt `json:"answers"`
+	// Remaining time in seconds, nil when the pack has no time limit
+	RemainingTime *int `json:"remaining_time"`
+	IsResumed     bool `json:"is_resumed"`
 }
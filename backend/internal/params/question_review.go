@@ -0,0 +1,49 @@
+package params
+
+import (
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params/generics"
+)
+
+type QuestionReviewQueueParam struct {
+	// Statuses to list, submitted and in review questions when empty
+	Status        []string `json:"status" schema:"status" validate:"dive,oneof=draft submitted in_review approved rejected"`
+	ReviewerID    int      `json:"reviewer_id" schema:"reviewer_id"`
+	MaterialID    int      `json:"material_id" schema:"material_id"`
+	ContributorID int      `json:"contributor_id" schema:"contributor_id"`
+	generics.GenericFilter
+}
+
+type QuestionReviewAssignParam struct {
+	QuestionID int `json:"-"`
+	ActorID    int `json:"-"`
+	// Reviewer of the question, the actor when empty
+	ReviewerID int    `json:"reviewer_id"`
+	Note       string `json:"note"`
+}
+
+type QuestionReviewDecisionParam struct {
+	QuestionID int    `json:"-"`
+	ActorID    int    `json:"-"`
+	Note       string `json:"note"`
+	// Reason shown to the contributor, required on rejection
+	Reason string `json:"reason" validate:"max=2000"`
+}
+
+type QuestionReviewCommentParam struct {
+	QuestionID int `json:"-"`
+	UserID     int `json:"-"`
+	// Field of the question the comment points to, e.g. body or solution
+	Field            string `json:"field" validate:"omitempty,max=64"`
+	QuestionOptionID *int   `json:"question_option_id"`
+	Body             string `json:"body" validate:"required,max=2000"`
+}
+
+type QuestionReviewDetailResponse struct {
+	QuestionID      int                              `json:"question_id"`
+	ReviewStatus    string                           `json:"review_status"`
+	ReviewerID      *int                             `json:"reviewer_id"`
+	RejectionReason string                           `json:"rejection_reason,omitempty"`
+	Comments        []entities.QuestionReviewComment `json:"comments"`
+	Logs            []entities.QuestionReviewLog     `json:"logs"`
+}
@@ -0,0 +1,24 @@
+package params
+
+import (
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params/generics"
+)
+
+type QuestionRevisionFilterParam struct {
+	QuestionID int `json:"-" schema:"-"`
+	generics.GenericFilter
+}
+
+type QuestionRevisionDiffParam struct {
+	QuestionID int `json:"-" schema:"-"`
+	From       int `json:"from" schema:"from" validate:"required"`
+	// Revision compared against, the latest revision when empty
+	To int `json:"to" schema:"to"`
+}
+
+type QuestionRevisionDiffResponse struct {
+	From    entities.QuestionRevision         `json:"from"`
+	To      entities.QuestionRevision         `json:"to"`
+	Changes []entities.QuestionRevisionChange `json:"changes"`
+}
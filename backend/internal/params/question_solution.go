@@ -10,6 +10,8 @@ type QuestionSolutionCreate struct {
 	PdfFileUrl       string `json:"pdf_file_url"`
 	SolutionImageUrl string `json:"solution_image_url"`
 	Link             string `json:"link"`
+	IsPremium        *bool  `json:"is_premium"`
+	ActorID          int    `json:"-"`
 }
 
 type QuestionSolutionWithFileUploadCreate struct {
@@ -18,6 +20,7 @@ type QuestionSolutionWithFileUploadCreate struct {
 	SolutionType string                `json:"solution_type" validate:"required"`
 	PdfFile      *multipart.FileHeader `json:"pdf_file"`
 	SolutionImg  *multipart.FileHeader `json:"solution_img"`
+	ActorID      int                   `json:"-"`
 }
 
 type QuestionSolutionUpdate struct {
@@ -29,4 +32,6 @@ type QuestionSolutionUpdate struct {
 	PdfFileUrl       string `json:"pdf_file_url"`
 	SolutionImageUrl string `json:"solution_image_url"`
 	Link             string `json:"link"`
+	IsPremium        *bool  `json:"is_premium"`
+	ActorID          int    `json:"-"`
 }
@@ -1,7 +1,31 @@
 package params
 
-import "gitlab.com/project-quiz/internal/params/generics"
+import (
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params/generics"
+)
 
 type UserPointFilterParam struct {
 	generics.GenericFilter
+	generics.CursorFilter
+}
+
+type UserPointResponse struct {
+	entities.UserPoint
+	Streak entities.UserStreak `json:"streak"`
+}
+
+type LeaderboardParam struct {
+	Scope   string `json:"scope" schema:"scope" validate:"omitempty,oneof=global material question_pack"`
+	ScopeID int    `json:"scope_id" schema:"scope_id" validate:"gte=0"`
+	Period  string `json:"period" schema:"period" validate:"omitempty,oneof=weekly monthly all_time"`
+	UserID  int    `json:"-" schema:"-"`
+	Page    int    `json:"page" schema:"page"`
+	Limit   int    `json:"limit" schema:"limit"`
+}
+
+type LeaderboardResponse struct {
+	Entries []entities.LeaderboardEntry `json:"entries"`
+	// Rank of the caller, nil when the caller has no score on the board
+	Me *entities.LeaderboardEntry `json:"me"`
 }
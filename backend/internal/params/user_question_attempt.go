@@ -1,29 +1,55 @@
 package params
 
+import "gitlab.com/project-quiz/internal/entities"
+
+// AttemptAnswerQuestionParam holds the answer in the field matching the question type:
+// option_id for single choice and true/false, option_ids for multiple answer and ordering,
+// answer_text for numeric and short text and answer_pairs for matching
 type AttemptAnswerQuestionParam struct {
-	QuestionID int `json:"question_id" validate:"required"`
-	OptionID   int `json:"option_id" validate:"required"`
-	UserID     int `json:"user_id" validate:"required"`
+	QuestionID            int                   `json:"question_id" validate:"required"`
+	OptionID              *int                  `json:"option_id"`
+	OptionIDs             []int                 `json:"option_ids"`
+	AnswerText            *string               `json:"answer_text" validate:"omitempty,max=1000"`
+	AnswerPairs           []entities.AnswerPair `json:"answer_pairs" validate:"dive"`
+	UserID                int                   `json:"user_id" validate:"required"`
+	QuestionPackAttemptID *int                  `json:"question_pack_attempt_id"`
+	// Seconds spent on the question since the previous answer
+	TimeSpent int `json:"time_spent" validate:"gte=0"`
 }
 
 type AttemptClearAnswerQuestionParam struct {
-	QuestionID int `json:"question_id" validate:"required"`
-	UserID     int `json:"user_id" validate:"required"`
+	QuestionID            int  `json:"question_id" validate:"required"`
+	UserID                int  `json:"user_id" validate:"required"`
+	QuestionPackAttemptID *int `json:"question_pack_attempt_id"`
 }
 
 type AttemptGetLatestAnswersParam struct {
-	QuestionIDs []int `json:"question_ids" validate:"required"`
-	UserID      int   `json:"user_id" validate:"required"`
+	QuestionIDs           []int `json:"question_ids" validate:"required"`
+	UserID                int   `json:"user_id" validate:"required"`
+	QuestionPackAttemptID *int  `json:"question_pack_attempt_id"`
 }
 
 type AttemptSubmitAnswerQuestionParam struct {
-	QuestionID int `json:"question_id" validate:"required"`
-	UserID     int `json:"user_id" validate:"required"`
+	QuestionID            int  `json:"question_id" validate:"required"`
+	UserID                int  `json:"user_id" validate:"required"`
+	QuestionPackAttemptID *int `json:"question_pack_attempt_id"`
 }
 
 type AttemptSubmitAnswerResponse struct {
-	TrueAnswerID     int  `json:"true_answer_id"`
-	AnswerID         int  `json:"answer_id"`
-	AttemptValue     bool `json:"attempt_value"`
-	TrueAnswerStreak int  `json:"true_answer_streak"`
+	TrueAnswerID     int     `json:"true_answer_id"`
+	AnswerID         int     `json:"answer_id"`
+	AttemptValue     bool    `json:"attempt_value"`
+	TrueAnswerStreak int     `json:"true_answer_streak"`
+	Type             string  `json:"type"`
+	Credit           float32 `json:"credit"`
+	// Correct options, in their correct order for ordering question
+	TrueAnswerIDs   []int    `json:"true_answer_ids,omitempty"`
+	NumericAnswer   *float64 `json:"numeric_answer,omitempty"`
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+
+	Streak entities.UserStreak `json:"streak"`
+	// Points given for the streak milestones reached by this answer
+	StreakBonus int `json:"streak_bonus"`
+	// Badges awarded for this answer
+	Badges []entities.Badge `json:"badges"`
 }
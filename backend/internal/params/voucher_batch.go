@@ -0,0 +1,32 @@
+package params
+
+import (
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params/generics"
+)
+
+type VoucherBatchFilterParam struct {
+	Campaign  string `json:"campaign" schema:"campaign"`
+	IsRevoked *bool  `json:"is_revoked" schema:"is_revoked"`
+	generics.GenericFilter
+}
+
+type VoucherBatchCreateParam struct {
+	Campaign   string `json:"campaign" validate:"required,max=100"`
+	Quantity   int    `json:"quantity" validate:"required,gt=0,lte=10000"`
+	LongPeriod int    `json:"long_period" validate:"required,gt=0"`
+	CodeLength int    `json:"code_length" validate:"omitempty,gte=6,lte=32"`
+	Alphabet   string `json:"alphabet" validate:"omitempty,min=2,max=64"`
+}
+
+type VoucherBatchSummary struct {
+	Total     int `json:"total"`
+	Redeemed  int `json:"redeemed"`
+	Revoked   int `json:"revoked"`
+	Available int `json:"available"`
+}
+
+type VoucherBatchDetailResponse struct {
+	entities.VoucherBatch
+	Summary VoucherBatchSummary `json:"summary"`
+}
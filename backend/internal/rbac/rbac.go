@@ -0,0 +1,137 @@
+// Package rbac holds the casbin enforcers shared by the authorization middleware and the policy API
+package rbac
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	casbinconf "gitlab.com/project-quiz/internal/config/casbin"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Enforcer decides which routes need authentication and which roles may access them.
+// It is built once, role policies are changed in place and saved to the database.
+// Changes made by other instances are picked up by Reload.
+type Enforcer struct {
+	route *casbin.SyncedEnforcer
+	auth  *casbin.SyncedEnforcer
+}
+
+// NewEnforcer builds the enforcers from the embedded models. Role policies are loaded
+// from the database, which is seeded with the embedded default policy when empty.
+func NewEnforcer(db *gorm.DB) (*Enforcer, error) {
+	return NewEnforcerWithAdapter(repository.NewCasbinRuleRepository(db))
+}
+
+// NewEnforcerWithAdapter builds the enforcers with role policies stored by the adapter
+func NewEnforcerWithAdapter(adapter repository.CasbinRuleRepository) (*Enforcer, error) {
+	routeModel, err := model.NewModelFromString(casbinconf.RouteModel)
+	if err != nil {
+		return nil, fmt.Errorf("route model: %w", err)
+	}
+	route, err := casbin.NewSyncedEnforcer(routeModel)
+	if err != nil {
+		return nil, fmt.Errorf("route enforcer: %w", err)
+	}
+	for _, line := range policyLines(casbinconf.RoutePolicy) {
+		if err := persist.LoadPolicyLine(line, route.GetModel()); err != nil {
+			return nil, fmt.Errorf("route policy: %w", err)
+		}
+	}
+
+	if err := seed(adapter); err != nil {
+		return nil, fmt.Errorf("seed policy: %w", err)
+	}
+
+	authModel, err := model.NewModelFromString(casbinconf.AuthModel)
+	if err != nil {
+		return nil, fmt.Errorf("auth model: %w", err)
+	}
+	auth, err := casbin.NewSyncedEnforcer(authModel, adapter)
+	if err != nil {
+		return nil, fmt.Errorf("auth enforcer: %w", err)
+	}
+
+	return &Enforcer{route: route, auth: auth}, nil
+}
+
+// IsProtected check whether the route needs an authenticated user
+func (e *Enforcer) IsProtected(path, method string) bool {
+	ok, _ := e.route.Enforce(path, method)
+	return ok
+}
+
+// IsAllowed check whether any of the roles may access the route
+func (e *Enforcer) IsAllowed(roles []string, path, method string) bool {
+	for _, role := range roles {
+		if ok, _ := e.auth.Enforce(role, path, method); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Policies lists role policies as subject, object and action, filtered by subject when given
+func (e *Enforcer) Policies(subject string) [][]string {
+	if subject != "" {
+		return e.auth.GetFilteredPolicy(0, subject)
+	}
+
+	return e.auth.GetPolicy()
+}
+
+// AddPolicy adds a role policy, false when it exists already
+func (e *Enforcer) AddPolicy(subject, object, action string) (bool, error) {
+	return e.auth.AddPolicy(subject, object, action)
+}
+
+// RemovePolicy removes a role policy, false when it does not exist
+func (e *Enforcer) RemovePolicy(subject, object, action string) (bool, error) {
+	return e.auth.RemovePolicy(subject, object, action)
+}
+
+// Reload loads the role policies from the database again, so policies changed
+// through another instance of the server are enforced here too
+func (e *Enforcer) Reload() error {
+	return e.auth.LoadPolicy()
+}
+
+// seed stores the embedded default policy when no policy is stored yet
+func seed(adapter repository.CasbinRuleRepository) error {
+	count, err := adapter.Count()
+	if err != nil || count > 0 {
+		return err
+	}
+
+	for _, line := range policyLines(casbinconf.AuthPolicy) {
+		values := strings.Split(line, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		if err := adapter.AddPolicy(values[0][:1], values[0], values[1:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// policyLines returns the policy lines of a csv, without blank lines and comments
+func policyLines(csv string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(csv))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
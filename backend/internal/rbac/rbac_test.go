@@ -0,0 +1,85 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// sharedRules is the policy storage shared by every instance of the server
+type sharedRules struct {
+	persist.Adapter
+	rules [][]string
+}
+
+func (s *sharedRules) LoadPolicy(model model.Model) error {
+	for _, rule := range s.rules {
+		if err := persist.LoadPolicyArray(rule, model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sharedRules) AddPolicy(sec string, ptype string, rule []string) error {
+	s.rules = append(s.rules, append([]string{ptype}, rule...))
+	return nil
+}
+
+func (s *sharedRules) RemovePolicy(sec string, ptype string, rule []string) error {
+	for i, stored := range s.rules {
+		if len(stored) == len(rule)+1 && stored[0] == ptype && equal(stored[1:], rule) {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *sharedRules) Count() (int64, error) {
+	return int64(len(s.rules)), nil
+}
+
+func equal(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReloadPicksUpPolicyChangedByAnotherInstance(t *testing.T) {
+	rules := &sharedRules{}
+	changed, err := NewEnforcerWithAdapter(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewEnforcerWithAdapter(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := changed.AddPolicy("reviewer", "/admin/v1/question*", "GET"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := changed.RemovePolicy("basic", "/basic*", "*"); err != nil {
+		t.Fatal(err)
+	}
+
+	if other.IsAllowed([]string{"reviewer"}, "/admin/v1/question", "GET") || !other.IsAllowed([]string{"basic"}, "/basic/v1/auth/me", "GET") {
+		t.Fatal("expected the other instance to keep its policies until reload")
+	}
+
+	if err := other.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !other.IsAllowed([]string{"reviewer"}, "/admin/v1/question", "GET") {
+		t.Error("expected added policy to be enforced after reload")
+	}
+	if other.IsAllowed([]string{"basic"}, "/basic/v1/auth/me", "GET") {
+		t.Error("expected removed policy to be dropped after reload")
+	}
+}
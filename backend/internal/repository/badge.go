@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
+	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type badgeRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type BadgeRepository interface {
+	// Create badge
+	Create(badge entities.Badge) (entities.Badge, error)
+	// Update every field of badge
+	Update(badge entities.Badge) (entities.Badge, error)
+	// Get badge
+	Get(ID int) (entities.Badge, error)
+	// List and filter badges
+	List(param params.BadgeFilter) ([]entities.Badge, int, error)
+	// Delete badge together with its awards
+	Delete(ID int) error
+	// List active badges of the rules which are not awarded to the user yet
+	ListPending(userID int, ruleTypes []string) ([]entities.Badge, error)
+	// Progress of the user on the rule of the badge
+	Progress(badge entities.Badge, userID int) (int, error)
+	// Award badge to the user, false when the user already has it
+	Award(userID, badgeID int, awardedAt time.Time) (bool, error)
+	// List badges awarded to the user, newest first
+	ListUserBadges(userID int) ([]entities.UserBadge, error)
+}
+
+func NewBadgeRepository(db *gorm.DB) BadgeRepository {
+	return &badgeRepo{
+		db:   db,
+		name: "Badge Repository",
+	}
+}
+
+var badgeFilter = filter.Config{
+	SearchColumns: []string{"name", "description"},
+	SortFields:    map[string]string{"id": "id", "name": "name", "threshold": "threshold", "created_at": "created_at"},
+	DefaultSort:   "created_at",
+}
+
+func (b *badgeRepo) Create(badge entities.Badge) (entities.Badge, error) {
+	if err := b.db.Create(&badge).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Create] %s", b.name, err.Error()))
+		return badge, err
+	}
+
+	return badge, nil
+}
+
+func (b *badgeRepo) Update(badge entities.Badge) (entities.Badge, error) {
+	if err := b.db.Save(&badge).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Update] %s", b.name, err.Error()))
+		return badge, err
+	}
+
+	return badge, nil
+}
+
+func (b *badgeRepo) Get(ID int) (entities.Badge, error) {
+	var badge entities.Badge
+
+	if err := b.db.First(&badge, ID).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get] %s", b.name, err.Error()))
+		return badge, err
+	}
+
+	return badge, nil
+}
+
+func (b *badgeRepo) List(param params.BadgeFilter) ([]entities.Badge, int, error) {
+	var badges []entities.Badge
+	var count int64
+
+	db := b.db.Model(&entities.Badge{}).Scopes(filter.Where(param.Filter(), badgeFilter))
+
+	if param.RuleType != "" {
+		db = db.Where("rule_type = ?", param.RuleType)
+	}
+
+	if param.IsActive != nil {
+		db = db.Where("is_active = ?", *param.IsActive)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", b.name, err.Error()))
+		return badges, 0, err
+	}
+
+	if err := db.Scopes(filter.Sort(param.Filter(), badgeFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&badges).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", b.name, err.Error()))
+		return badges, 0, err
+	}
+
+	return badges, int(count), nil
+}
+
+func (b *badgeRepo) Delete(ID int) error {
+	result := b.db.Delete(&entities.Badge{}, ID)
+	if result.Error != nil {
+		logrus.Error(fmt.Sprintf("[%s][Delete] %s", b.name, result.Error.Error()))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (b *badgeRepo) ListPending(userID int, ruleTypes []string) ([]entities.Badge, error) {
+	var badges []entities.Badge
+
+	if err := b.db.Where("is_active = ? AND rule_type IN ?", true, ruleTypes).
+		Where("NOT EXISTS (SELECT 1 FROM user_badges AS ub WHERE ub.badge_id = badges.id AND ub.user_id = ?)", userID).
+		Order("id asc").Find(&badges).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][ListPending] %s", b.name, err.Error()))
+		return badges, err
+	}
+
+	return badges, nil
+}
+
+func (b *badgeRepo) Progress(badge entities.Badge, userID int) (int, error) {
+	var progress int64
+	var db *gorm.DB
+
+	switch badge.RuleType {
+	case entities.BadgeRuleAnswerCount, entities.BadgeRuleCorrectAnswerCount:
+		// A question answered more than once counts once
+		db = b.db.Table("user_question_attempts AS a").Select("COUNT(DISTINCT a.question_id)").
+			Where("a.user_id = ? AND a.is_submitted = ?", userID, true)
+		if badge.RuleType == entities.BadgeRuleCorrectAnswerCount {
+			db = db.Where("a.attempt_value = ?", true)
+		}
+		if badge.MaterialID != nil {
+			db = db.Joins("INNER JOIN questions AS q ON q.id = a.question_id").Where("q.material_id = ?", *badge.MaterialID)
+		}
+	case entities.BadgeRulePackFinishCount:
+		db = b.db.Table("question_pack_attempts").Select("COUNT(*)").
+			Where("user_id = ? AND is_finish = ? AND max_score > 0 AND score * 100 >= ? * max_score", userID, true, badge.MinScore)
+	case entities.BadgeRuleAnswerStreak:
+		db = b.db.Table("user_streaks").Select("COALESCE(MAX(best_answer_streak), 0)").Where("user_id = ?", userID)
+	case entities.BadgeRuleDailyStreak:
+		db = b.db.Table("user_streaks").Select("COALESCE(MAX(best_daily_streak), 0)").Where("user_id = ?", userID)
+	default:
+		return 0, fmt.Errorf("unknown badge rule %s", badge.RuleType)
+	}
+
+	if err := db.Scan(&progress).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Progress] %s", b.name, err.Error()))
+		return 0, err
+	}
+
+	return int(progress), nil
+}
+
+func (b *badgeRepo) Award(userID, badgeID int, awardedAt time.Time) (bool, error) {
+	award := entities.UserBadge{UserID: userID, BadgeID: badgeID, AwardedAt: awardedAt}
+
+	result := b.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "badge_id"}},
+		DoNothing: true,
+	}).Create(&award)
+	if result.Error != nil {
+		logrus.Error(fmt.Sprintf("[%s][Award] %s", b.name, result.Error.Error()))
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (b *badgeRepo) ListUserBadges(userID int) ([]entities.UserBadge, error) {
+	var badges []entities.UserBadge
+
+	if err := b.db.Preload("Badge").Where("user_id = ?", userID).Order("awarded_at desc, id desc").Find(&badges).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][ListUserBadges] %s", b.name, err.Error()))
+		return badges, err
+	}
+
+	return badges, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type casbinRuleRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+// CasbinRuleRepository is the casbin adapter storing policies in casbin_rules
+type CasbinRuleRepository interface {
+	persist.Adapter
+	// Count stored rules
+	Count() (int64, error)
+}
+
+func NewCasbinRuleRepository(db *gorm.DB) CasbinRuleRepository {
+	return &casbinRuleRepo{
+		db:   db,
+		name: "Casbin Rule Repository",
+	}
+}
+
+func (c *casbinRuleRepo) LoadPolicy(m model.Model) error {
+	var rules []entities.CasbinRule
+
+	if err := c.db.Order("id asc").Find(&rules).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][LoadPolicy] %s", c.name, err.Error()))
+		return err
+	}
+
+	for _, rule := range rules {
+		if err := persist.LoadPolicyArray(append([]string{rule.Ptype}, rule.Values()...), m); err != nil {
+			logrus.Error(fmt.Sprintf("[%s][LoadPolicy] %s", c.name, err.Error()))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *casbinRuleRepo) SavePolicy(m model.Model) error {
+	var rules []entities.CasbinRule
+	for _, sec := range []string{"p", "g"} {
+		for ptype, assertion := range m[sec] {
+			for _, values := range assertion.Policy {
+				rules = append(rules, entities.NewCasbinRule(ptype, values))
+			}
+		}
+	}
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entities.CasbinRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+
+		return tx.Create(&rules).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][SavePolicy] %s", c.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (c *casbinRuleRepo) AddPolicy(sec string, ptype string, values []string) error {
+	rule := entities.NewCasbinRule(ptype, values)
+
+	if err := c.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rule).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][AddPolicy] %s", c.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (c *casbinRuleRepo) RemovePolicy(sec string, ptype string, values []string) error {
+	rule := entities.NewCasbinRule(ptype, values)
+
+	if err := c.db.Where(map[string]interface{}{
+		"ptype": rule.Ptype, "v0": rule.V0, "v1": rule.V1, "v2": rule.V2, "v3": rule.V3, "v4": rule.V4, "v5": rule.V5,
+	}).Delete(&entities.CasbinRule{}).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][RemovePolicy] %s", c.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (c *casbinRuleRepo) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	db := c.db.Where("ptype = ?", ptype)
+	for i, value := range fieldValues {
+		// Empty value matches anything
+		if value != "" && fieldIndex+i <= 5 {
+			db = db.Where(fmt.Sprintf("v%d = ?", fieldIndex+i), value)
+		}
+	}
+
+	if err := db.Delete(&entities.CasbinRule{}).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][RemoveFilteredPolicy] %s", c.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (c *casbinRuleRepo) Count() (int64, error) {
+	var count int64
+
+	if err := c.db.Model(&entities.CasbinRule{}).Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Count] %s", c.name, err.Error()))
+		return 0, err
+	}
+
+	return count, nil
+}
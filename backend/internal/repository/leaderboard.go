@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
+	"gorm.io/gorm"
+)
+
+type leaderboardRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type LeaderboardRepository interface {
+	// Refresh replaces the snapshot of every board of the period with scores since the start of the period
+	Refresh(period string, since, now time.Time) error
+	// List entries of a board ordered by rank
+	List(param params.LeaderboardParam) ([]entities.LeaderboardEntry, int, error)
+	// Get entry of the user on a board
+	GetUserEntry(scope string, scopeID int, period string, userID int) (entities.LeaderboardEntry, error)
+}
+
+func NewLeaderboardRepository(db *gorm.DB) LeaderboardRepository {
+	return &leaderboardRepo{
+		db:   db,
+		name: "Leaderboard Repository",
+	}
+}
+
+// leaderboardQueries select scope_id, user_id and score of every board of a scope. Scores are earned
+// points on global and material boards, points are tied to materials through the answered attempt.
+var leaderboardQueries = map[string]string{
+	entities.LeaderboardScopeGlobal: `
+		SELECT 0 AS scope_id, user_id, SUM(amount) AS score
+		FROM point_transactions
+		WHERE created_at >= @since
+		GROUP BY user_id`,
+	entities.LeaderboardScopeMaterial: `
+		SELECT q.material_id AS scope_id, pt.user_id, SUM(pt.amount) AS score
+		FROM point_transactions AS pt
+		INNER JOIN user_question_attempts AS a ON pt.source_type = @attempt AND a.id = pt.source_id
+		INNER JOIN questions AS q ON q.id = a.question_id
+		WHERE pt.created_at >= @since
+		GROUP BY q.material_id, pt.user_id`,
+	entities.LeaderboardScopeQuestionPack: `
+		SELECT question_pack_id AS scope_id, user_id, MAX(score) AS score
+		FROM question_pack_attempts
+		WHERE is_finish = true AND finished_at >= @since
+		GROUP BY question_pack_id, user_id`,
+}
+
+func (l *leaderboardRepo) Refresh(period string, since, now time.Time) error {
+	for scope, query := range leaderboardQueries {
+		err := l.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("scope = ? AND period = ?", scope, period).Delete(&entities.LeaderboardEntry{}).Error; err != nil {
+				return err
+			}
+
+			return tx.Exec(`INSERT INTO leaderboard_entries (scope, scope_id, period, user_id, score, rank, refreshed_at)
+				SELECT CAST(@scope AS VARCHAR), s.scope_id, CAST(@period AS VARCHAR), s.user_id, s.score,
+					RANK() OVER (PARTITION BY s.scope_id ORDER BY s.score DESC), CAST(@now AS TIMESTAMP)
+				FROM (`+query+`) AS s
+				WHERE s.score > 0`, map[string]interface{}{
+				"scope":   scope,
+				"period":  period,
+				"since":   since,
+				"now":     now,
+				"attempt": entities.PointSourceAttempt,
+			}).Error
+		})
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Refresh] %s %s: %s", l.name, scope, period, err.Error()))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *leaderboardRepo) List(param params.LeaderboardParam) ([]entities.LeaderboardEntry, int, error) {
+	var entries []entities.LeaderboardEntry
+	var count int64
+
+	db := l.db.Model(&entities.LeaderboardEntry{}).Where("scope = ? AND scope_id = ? AND period = ?", param.Scope, param.ScopeID, param.Period)
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", l.name, err.Error()))
+		return entries, 0, err
+	}
+
+	if err := db.Preload("User").Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).
+		Order("rank asc, user_id asc").Find(&entries).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", l.name, err.Error()))
+		return entries, 0, err
+	}
+
+	return entries, int(count), nil
+}
+
+func (l *leaderboardRepo) GetUserEntry(scope string, scopeID int, period string, userID int) (entities.LeaderboardEntry, error) {
+	var entry entities.LeaderboardEntry
+
+	if err := l.db.Preload("User").Where("scope = ? AND scope_id = ? AND period = ? AND user_id = ?", scope, scopeID, period, userID).
+		First(&entry).Error; err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
@@ -2,11 +2,11 @@ package repository
 
 import (
 	"fmt"
-	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 	"gorm.io/gorm"
 )
@@ -29,6 +29,8 @@ type MaterialRepository interface {
 	Get(ID int) (entities.Material, error)
 	// Delete Role
 	Delete(ID int) (entities.Material, error)
+	// Get all materials
+	GetAll() ([]entities.Material, error)
 }
 
 // Create new role repository instance
@@ -61,21 +63,28 @@ func (m *materialRepo) Get(ID int) (entities.Material, error) {
 	return material, nil
 }
 
+var materialFilter = filter.Config{
+	SearchColumns: []string{"name", "code_prefix"},
+	SortFields:    map[string]string{"id": "id", "name": "name", "level": "level", "created_at": "created_at"},
+	DefaultSort:   "created_at",
+}
+
 func (m *materialRepo) List(param params.MaterialFilterParam) ([]entities.Material, int, error) {
 	var materials []entities.Material
 
 	var count int64
-	db := m.db
-
-	if param.Q != "" {
-		db = db.Where("LOWER(name) like ?", "%"+strings.ToLower(param.Q)+"%")
-	}
+	db := m.db.Model(&entities.Material{}).Scopes(filter.Where(param.Filter(), materialFilter))
 
 	if param.Level != "" {
 		db = db.Where("level = ?", param.Level)
 	}
 
-	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&materials).Count(&count).Error; err != nil {
+	if err := db.Count(&count).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][List] %s", m.name, err.Error()))
+		return materials, int(count), err
+	}
+
+	if err := db.Debug().Scopes(filter.Sort(param.Filter(), materialFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&materials).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][List] %s", m.name, err.Error()))
 		return materials, int(count), err
 	}
@@ -116,3 +125,14 @@ func (m *materialRepo) Delete(ID int) (entities.Material, error) {
 
 	return material, nil
 }
+
+func (m *materialRepo) GetAll() ([]entities.Material, error) {
+	var materials []entities.Material
+
+	if err := m.db.Order("id asc").Find(&materials).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][Get All] %s", m.name, err.Error()))
+		return materials, err
+	}
+
+	return materials, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
+	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
+	"gitlab.com/project-quiz/utils/postgres"
+	"gorm.io/gorm"
+)
+
+var ErrInsufficientPoint = errors.New("point balance is not enough")
+
+type pointTransactionRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type PointTransactionRepository interface {
+	// Apply transaction to the balance of the user atomically. Transaction whose idempotency key
+	// was applied before is not applied again, the earlier transaction is returned with false.
+	Apply(transaction entities.PointTransaction) (entities.PointTransaction, bool, error)
+	// List transactions, newest first
+	List(param params.PointTransactionFilterParam) ([]entities.PointTransaction, int, error)
+}
+
+func NewPointTransactionRepository(db *gorm.DB) PointTransactionRepository {
+	return &pointTransactionRepo{
+		db:   db,
+		name: "Point Transaction Repository",
+	}
+}
+
+var pointTransactionFilter = filter.Config{
+	SearchColumns: []string{"note"},
+	SortFields:    map[string]string{"id": "id", "amount": "amount", "created_at": "created_at"},
+	DefaultSort:   "id",
+}
+
+func (p *pointTransactionRepo) Apply(transaction entities.PointTransaction) (entities.PointTransaction, bool, error) {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		// Row of the user is locked by the upsert until commit, so concurrent transactions queue up
+		var balance int
+		if err := tx.Raw(`INSERT INTO user_points (user_id, point, created_at, updated_at) VALUES (?, ?, NOW(), NOW())
+			ON CONFLICT (user_id) DO UPDATE SET point = user_points.point + EXCLUDED.point, updated_at = EXCLUDED.updated_at
+			RETURNING point`, transaction.UserID, transaction.Amount).Scan(&balance).Error; err != nil {
+			return err
+		}
+
+		if transaction.Amount < 0 && balance < 0 {
+			return ErrInsufficientPoint
+		}
+
+		transaction.Balance = balance
+		return tx.Create(&transaction).Error
+	})
+	if err == nil {
+		return transaction, true, nil
+	}
+
+	if transaction.IdempotencyKey != nil && postgres.IsUniqueViolation(err) {
+		var applied entities.PointTransaction
+		if err := p.db.Where("idempotency_key = ?", *transaction.IdempotencyKey).First(&applied).Error; err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Apply] %s", p.name, err.Error()))
+			return transaction, false, err
+		}
+
+		return applied, false, nil
+	}
+
+	logrus.Error(fmt.Sprintf("[%s][Apply] %s", p.name, err.Error()))
+	return transaction, false, err
+}
+
+func (p *pointTransactionRepo) List(param params.PointTransactionFilterParam) ([]entities.PointTransaction, int, error) {
+	var transactions []entities.PointTransaction
+	var count int64
+
+	db := p.db.Model(&entities.PointTransaction{}).Scopes(filter.Where(param.Filter(), pointTransactionFilter))
+
+	if param.UserID != 0 {
+		db = db.Where("user_id = ?", param.UserID)
+	}
+
+	if param.Reason != "" {
+		db = db.Where("reason = ?", param.Reason)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", p.name, err.Error()))
+		return transactions, 0, err
+	}
+
+	if err := db.Scopes(filter.Sort(param.Filter(), pointTransactionFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&transactions).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", p.name, err.Error()))
+		return transactions, 0, err
+	}
+
+	return transactions, int(count), nil
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
@@ -11,6 +13,11 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrVoucherRedeemed = errors.New("voucher has already been redeemed")
+	ErrVoucherRevoked  = errors.New("voucher has been revoked")
+)
+
 type PremiumPackageRepo struct {
 	db   *gorm.DB
 	name string
@@ -27,6 +34,14 @@ type PremiumPackageRepository interface {
 	Get(ID int) (entities.PremiumPackage, error)
 	// Delete Role
 	Delete(ID int) (entities.PremiumPackage, error)
+	// Get premium package by its voucher token
+	GetByToken(token string) (entities.PremiumPackage, error)
+	// Get active premium package of user with the latest active until
+	GetActiveByUser(userID int, now time.Time) (entities.PremiumPackage, error)
+	// Get tokens which are already used by existing premium packages
+	GetExistingTokens(tokens []string) ([]string, error)
+	// Bind unredeemed voucher to user
+	Redeem(pp entities.PremiumPackage, userID int, activeUntil time.Time, now time.Time) (entities.PremiumPackage, error)
 }
 
 // Create new role repository instance
@@ -68,16 +83,27 @@ func (r *PremiumPackageRepo) List(param params.PremiumPackageFilterParam) ([]ent
 	var pps []entities.PremiumPackage
 
 	var count int64
-	r.db.Find(&pps).Count(&count)
-
 	db := r.db
 
 	if param.UserID != 0 {
 		db = db.Where("user_id = ?", param.UserID)
 	}
 
-	if *param.IsActive {
-		db.Where("is_active", *param.IsActive)
+	if param.IsActive != nil {
+		db = db.Where("is_active = ?", *param.IsActive)
+	}
+
+	if param.IsRedeemed != nil {
+		if *param.IsRedeemed {
+			db = db.Where("user_id IS NOT NULL")
+		} else {
+			db = db.Where("user_id IS NULL")
+		}
+	}
+
+	if err := db.Model(&pps).Count(&count).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][List] %s", r.name, err.Error()))
+		return pps, 0, err
 	}
 
 	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&pps).Error; err != nil {
@@ -91,7 +117,7 @@ func (r *PremiumPackageRepo) List(param params.PremiumPackageFilterParam) ([]ent
 func (r *PremiumPackageRepo) Update(pp entities.PremiumPackage) (entities.PremiumPackage, error) {
 	log.Info(fmt.Sprintf("[%s][Update] is executed", r.name))
 
-	if err := r.db.Model(&pp).Updates(&pp).Error; err != nil {
+	if err := r.db.Model(&pp).Select("is_active", "active_until", "long_period").Updates(&pp).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][Update] %s", r.name, err.Error()))
 		return pp, err
 	}
@@ -110,3 +136,62 @@ func (r *PremiumPackageRepo) Delete(ID int) (entities.PremiumPackage, error) {
 
 	return pp, nil
 }
+
+func (r *PremiumPackageRepo) GetByToken(token string) (entities.PremiumPackage, error) {
+	var pp entities.PremiumPackage
+
+	if err := r.db.Where("token = ?", token).First(&pp).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][Get By Token] %s", r.name, err.Error()))
+		return pp, err
+	}
+
+	return pp, nil
+}
+
+func (r *PremiumPackageRepo) GetActiveByUser(userID int, now time.Time) (entities.PremiumPackage, error) {
+	var pp entities.PremiumPackage
+
+	if err := r.db.Where("user_id = ? AND is_active = ? AND active_until > ?", userID, true, now).Order("active_until desc").First(&pp).Error; err != nil {
+		return pp, err
+	}
+
+	return pp, nil
+}
+
+func (r *PremiumPackageRepo) Redeem(pp entities.PremiumPackage, userID int, activeUntil time.Time, now time.Time) (entities.PremiumPackage, error) {
+	log.Info(fmt.Sprintf("[%s][Redeem] is executed", r.name))
+
+	// Only bind voucher which is not redeemed nor revoked yet, the check and the update are done in one statement
+	result := r.db.Model(&pp).Where("user_id IS NULL AND revoked_at IS NULL").Updates(map[string]interface{}{
+		"user_id":      userID,
+		"is_active":    true,
+		"active_until": activeUntil,
+		"redeemed_at":  now,
+	})
+	if result.Error != nil {
+		log.Error(fmt.Sprintf("[%s][Redeem] %s", r.name, result.Error.Error()))
+		return pp, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return pp, ErrVoucherRedeemed
+	}
+
+	pp.UserID = &userID
+	pp.IsActive = true
+	pp.ActiveUntil = &activeUntil
+	pp.RedeemedAt = &now
+
+	return pp, nil
+}
+
+func (r *PremiumPackageRepo) GetExistingTokens(tokens []string) ([]string, error) {
+	var existing []string
+
+	if err := r.db.Model(&entities.PremiumPackage{}).Where("token IN ?", tokens).Pluck("token", &existing).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][Get Existing Tokens] %s", r.name, err.Error()))
+		return existing, err
+	}
+
+	return existing, nil
+}
@@ -7,6 +7,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
 	"gitlab.com/project-quiz/utils/minio"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 	"gorm.io/gorm"
@@ -19,8 +20,13 @@ type questionRepo struct {
 }
 
 type QuestionRepository interface {
-	// Create a new role
+	// Use the transaction for the following changes
+	WithTx(tx *gorm.DB) QuestionRepository
+	// Create a new question with its first revision, recorded as created by the contributor
 	Create(question entities.Question) (entities.Question, error)
+	// Create many questions with their options, tags, solutions and first revision in one transaction.
+	// Tags without ID are created once per name. Solution at index i belongs to question at index i and may be nil.
+	CreateMany(questions []entities.Question, solutions []*entities.QuestionSolution) ([]entities.Question, error)
 	// Update role
 	Update(question entities.Question) (entities.Question, error)
 	// List role
@@ -29,6 +35,10 @@ type QuestionRepository interface {
 	ListJoinMaterial(param params.QuestionFilterParam) ([]entities.QuestionAdminList, int, error)
 	// Get Role
 	Get(ID int) (entities.Question, error)
+	// Get question with its options for grading, without generating storage url
+	GetAnswerKey(ID int) (entities.Question, error)
+	// List questions with their options and tags for export, without generating storage url
+	ListForExport(param params.QuestionExportParam) ([]entities.Question, error)
 	// Get Total
 	GetTotal() (int, error)
 	// Delete Role
@@ -47,8 +57,24 @@ func NewQuestionRepository(db *gorm.DB, m minio.MinioStorageContract) QuestionRe
 	}
 }
 
+func (q *questionRepo) WithTx(tx *gorm.DB) QuestionRepository {
+	return &questionRepo{
+		db:    tx,
+		minio: q.minio,
+		name:  q.name,
+	}
+}
+
 func (q *questionRepo) Create(question entities.Question) (entities.Question, error) {
-	if err := q.db.Create(&question).Error; err != nil {
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&question).Error; err != nil {
+			return err
+		}
+
+		_, err := recordRevision(tx, question.ID, question.ContributorID, entities.RevisionActionCreate, nil)
+		return err
+	})
+	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Create] %s", q.name, err.Error()))
 		return question, err
 	}
@@ -58,6 +84,64 @@ func (q *questionRepo) Create(question entities.Question) (entities.Question, er
 	return question, nil
 }
 
+func (q *questionRepo) CreateMany(questions []entities.Question, solutions []*entities.QuestionSolution) ([]entities.Question, error) {
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		if err := createNewTags(tx, questions); err != nil {
+			return err
+		}
+
+		for i := range questions {
+			// Questions are created one by one so every question gets its own code
+			if err := tx.Create(&questions[i]).Error; err != nil {
+				return fmt.Errorf("question %d: %w", i+1, err)
+			}
+
+			if i < len(solutions) && solutions[i] != nil {
+				solutions[i].QuestionID = questions[i].ID
+				if err := tx.Create(solutions[i]).Error; err != nil {
+					return fmt.Errorf("solution of question %d: %w", i+1, err)
+				}
+			}
+
+			if _, err := recordRevision(tx, questions[i].ID, questions[i].ContributorID, entities.RevisionActionImport, nil); err != nil {
+				return fmt.Errorf("revision of question %d: %w", i+1, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("[%s][Create Many] %s", q.name, err.Error()))
+		return questions, err
+	}
+
+	return questions, nil
+}
+
+// createNewTags creates the tags without ID of the questions, questions sharing a tag name share the created tag
+func createNewTags(tx *gorm.DB, questions []entities.Question) error {
+	created := make(map[string]entities.QuestionTag)
+
+	for i := range questions {
+		for j, tag := range questions[i].QuestionTags {
+			if tag.ID != 0 {
+				continue
+			}
+
+			key := strings.ToLower(tag.Name)
+			if _, ok := created[key]; !ok {
+				if err := tx.Create(&tag).Error; err != nil {
+					return fmt.Errorf("tag %s: %w", tag.Name, err)
+				}
+				created[key] = tag
+			}
+			questions[i].QuestionTags[j] = created[key]
+		}
+	}
+
+	return nil
+}
+
 func (q *questionRepo) Get(ID int) (entities.Question, error) {
 	var question entities.Question
 	var questionOptions []entities.QuestionOption
@@ -69,7 +153,7 @@ func (q *questionRepo) Get(ID int) (entities.Question, error) {
 		return question, err
 	}
 
-	if err := q.db.Debug().Select("id", "question_id", "body", "is_image", "img_path", "option_value", "created_at", "updated_at").Where("question_id = ?", ID).Order("created_at asc").Find(&questionOptions).Error; err != nil {
+	if err := q.db.Debug().Select("id", "question_id", "body", "is_image", "img_path", "option_value", "position", "match_body", "created_at", "updated_at").Where("question_id = ?", ID).Order("created_at asc").Find(&questionOptions).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][GET] %s", q.name, err.Error()))
 		return question, err
 	}
@@ -85,11 +169,68 @@ func (q *questionRepo) Get(ID int) (entities.Question, error) {
 	return question, nil
 }
 
+func (q *questionRepo) GetAnswerKey(ID int) (entities.Question, error) {
+	var question entities.Question
+
+	if err := q.db.Preload("QuestionOptions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id asc")
+	}).First(&question, ID).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][Get Answer Key] %s", q.name, err.Error()))
+		return question, err
+	}
+
+	return question, nil
+}
+
+func (q *questionRepo) ListForExport(param params.QuestionExportParam) ([]entities.Question, error) {
+	var questions []entities.Question
+
+	db := q.db.Preload("QuestionTags").Preload("QuestionOptions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc, id asc")
+	})
+
+	if param.MaterialID != 0 {
+		db = db.Where("material_id = ?", param.MaterialID)
+	}
+
+	if len(param.QuestionIDs) > 0 {
+		db = db.Where("id IN ?", param.QuestionIDs)
+	}
+
+	switch param.Show {
+	case "active":
+		db = db.Where("is_active = ?", true)
+	case "inactive":
+		db = db.Where("is_active = ?", false)
+	}
+
+	if err := db.Order("material_id asc, id asc").Find(&questions).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][List For Export] %s", q.name, err.Error()))
+		return questions, err
+	}
+
+	return questions, nil
+}
+
+// questionFilter sorts by relevance of the keyword or newest first when client does not ask for a sort.
+// Keyword is searched by questionSearch instead.
+func questionFilter(table string) filter.Config {
+	return filter.Config{
+		DateColumn: table + ".created_at",
+		SortFields: map[string]string{
+			"id":         table + ".id",
+			"code":       table + ".code",
+			"created_at": table + ".created_at",
+			"updated_at": table + ".updated_at",
+		},
+	}
+}
+
 func (q *questionRepo) List(param params.QuestionFilterParam) ([]entities.Question, int, error) {
 	var questions []entities.Question
 
 	var count int64
-	db := q.db
+	db := q.db.Model(&entities.Question{})
 
 	if param.MaterialID != 0 {
 		db = db.Where("material_id = ?", param.MaterialID)
@@ -108,13 +249,39 @@ func (q *questionRepo) List(param params.QuestionFilterParam) ([]entities.Questi
 
 	if param.IncludePackOnly != nil {
 		if *param.IncludePackOnly {
-			db = db.Where("is_pack_only = ?", true).Or("is_pack_only = ?", false)
+			db = db.Where("is_pack_only = ? OR is_pack_only = ?", true, false)
 		} else {
 			db = db.Where("is_pack_only = ?", false)
 		}
 	}
 
-	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&questions).Count(&count).Error; err != nil {
+	search := newQuestionSearch(param.Q, "questions")
+	if search != nil {
+		db = search.Where(db)
+	}
+
+	db = db.Scopes(filter.Where(param.Filter(), questionFilter("questions")))
+
+	if err := db.Count(&count).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return questions, int(count), err
+	}
+
+	if search != nil {
+		// Snippet only shows the body so options and solutions are not given away
+		db = search.Select(db, "questions.*", "body")
+	}
+	if param.Sort == "" {
+		if search != nil {
+			db = db.Order(search.Order())
+		} else {
+			db = db.Order("created_at desc")
+		}
+	} else {
+		db = db.Scopes(filter.Sort(param.Filter(), questionFilter("questions")))
+	}
+
+	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Find(&questions).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
 		return questions, int(count), err
 	}
@@ -137,68 +304,56 @@ func (q *questionRepo) GetTotal() (int, error) {
 
 func (q *questionRepo) ListJoinMaterial(param params.QuestionFilterParam) ([]entities.QuestionAdminList, int, error) {
 	var questions []entities.QuestionAdminList
-
 	var count int64
-	db := q.db
-	hasWhere := false
-	var queryValues []interface{}
-	var queryValuesPagination []interface{}
-
-	sqlStatment := `
-	select q.id, q.body, q.is_image, q.code, m."name" as material, q.img_path, q.is_active, q.contributor_id, q.created_at, q.updated_at from questions as q
-	inner join materials as m
-	on q.material_id = m.id
-	`
-	sqlStatmentCount := `
-	select count(q.id) from questions as q
-	inner join materials as m
-	on q.material_id = m.id
-	`
+
+	db := q.db.Table("questions AS q").Joins("INNER JOIN materials AS m ON q.material_id = m.id")
+
 	if param.MaterialID != 0 {
-		hasWhere = true
-		sqlStatment += ` where material_id = ?`
-		sqlStatmentCount += ` where material_id = ?`
-		queryValues = append(queryValues, param.MaterialID)
+		db = db.Where("q.material_id = ?", param.MaterialID)
 	}
 
 	if param.Code != "" {
-		if !hasWhere {
-			sqlStatment += ` where`
-			sqlStatmentCount += ` where`
-			sqlStatment += ` LOWER(q.code) like ?`
-			sqlStatmentCount += ` LOWER(q.code) like ?`
-			hasWhere = true
-		} else {
-			sqlStatment += ` AND LOWER(q.code) like ?`
-			sqlStatmentCount += ` AND LOWER(q.code) like ?`
-		}
-		queryValues = append(queryValues, param.Code)
+		db = db.Where("LOWER(q.code) LIKE ?", "%"+strings.ToLower(param.Code)+"%")
 	}
 
 	if param.ContributorID != 0 {
-		if !hasWhere {
-			sqlStatment += ` where`
-			sqlStatmentCount += ` where`
-			sqlStatment += ` q.contributor_id = ?`
-			sqlStatmentCount += ` q.contributor_id = ?`
-			hasWhere = true
-		} else {
-			sqlStatment += ` AND q.contributor_id = ?`
-			sqlStatmentCount += ` AND q.contributor_id = ?`
-		}
-		queryValues = append(queryValues, param.ContributorID)
+		db = db.Where("q.contributor_id = ?", param.ContributorID)
+	}
+
+	if param.ReviewStatus != "" {
+		db = db.Where("q.review_status = ?", param.ReviewStatus)
+	}
+
+	search := newQuestionSearch(param.Q, "q")
+	if search != nil {
+		db = search.Where(db)
 	}
 
-	sqlStatment += ` order by q.created_at desc offset ? limit ?`
-	queryValuesPagination = append(queryValues, (param.Page-1)*param.Limit)
-	queryValuesPagination = append(queryValuesPagination, param.Limit)
+	db = db.Scopes(filter.Where(param.Filter(), questionFilter("q")))
 
-	if err := db.Debug().Table("questions").Raw(sqlStatment, queryValuesPagination...).Scan(&questions).Error; err != nil {
+	if err := db.Count(&count).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][List With Join] %s", q.name, err.Error()))
 		return questions, int(count), err
 	}
 
-	if err := db.Debug().Table("questions").Raw(sqlStatmentCount, queryValues...).Scan(&count).Error; err != nil {
+	columns := `q.id, q.body, q.is_image, q.code, m."name" AS material, q.img_path, q.is_active, q.contributor_id, q.review_status, q.created_at, q.updated_at`
+	if search != nil {
+		// Admin and contributor see which option or tag matches
+		db = search.Select(db, columns, "search_document")
+	} else {
+		db = db.Select(columns)
+	}
+	if param.Sort == "" {
+		if search != nil {
+			db = db.Order(search.Order())
+		} else {
+			db = db.Order("q.created_at desc")
+		}
+	} else {
+		db = db.Scopes(filter.Sort(param.Filter(), questionFilter("q")))
+	}
+
+	if err := db.Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Scan(&questions).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][List With Join] %s", q.name, err.Error()))
 		return questions, int(count), err
 	}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/questioncode"
+	"gorm.io/gorm"
+)
+
+// numberedQuestionsSQL numbers questions by ID within the code prefix of their material
+const numberedQuestionsSQL = `WITH numbered AS (
+	SELECT q.id, q.code, COALESCE(NULLIF(m.code_prefix, ''), @prefix) AS prefix,
+		ROW_NUMBER() OVER (PARTITION BY COALESCE(NULLIF(m.code_prefix, ''), @prefix) ORDER BY q.id) AS n
+	FROM questions AS q
+	LEFT JOIN materials AS m ON m.id = q.material_id
+)`
+
+type questionCodeRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type QuestionCodeRepository interface {
+	// Count questions per prefix and how many codes resequencing would change
+	Plan() ([]params.QuestionCodePrefixSummary, error)
+	// Renumber question codes from 1 per prefix and reset the counters
+	Resequence() ([]params.QuestionCodePrefixSummary, error)
+	// Count questions without code
+	CountMissing() (int, error)
+	// Give codes to questions without one, batch by batch
+	Backfill(batchSize int) (int, error)
+	// Move counters after the highest code in use
+	SyncCounters() error
+	// List codes used by more than one question
+	Duplicates() ([]string, error)
+	// Create unique index on question code without locking the table
+	CreateUniqueIndex() error
+}
+
+func NewQuestionCodeRepository(db *gorm.DB) QuestionCodeRepository {
+	return &questionCodeRepo{
+		db:   db,
+		name: "Question Code Repository",
+	}
+}
+
+func (q *questionCodeRepo) Plan() ([]params.QuestionCodePrefixSummary, error) {
+	return q.plan(q.db)
+}
+
+func (q *questionCodeRepo) plan(db *gorm.DB) ([]params.QuestionCodePrefixSummary, error) {
+	var summaries []params.QuestionCodePrefixSummary
+
+	err := db.Raw(numberedQuestionsSQL+`
+		SELECT prefix, COUNT(*) AS total, COUNT(*) FILTER (WHERE code IS DISTINCT FROM prefix || n) AS changed
+		FROM numbered GROUP BY prefix ORDER BY prefix`,
+		map[string]interface{}{"prefix": questioncode.DefaultPrefix}).Scan(&summaries).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Plan] %s", q.name, err.Error()))
+		return summaries, err
+	}
+
+	return summaries, nil
+}
+
+func (q *questionCodeRepo) Resequence() ([]params.QuestionCodePrefixSummary, error) {
+	var summaries []params.QuestionCodePrefixSummary
+	args := map[string]interface{}{"prefix": questioncode.DefaultPrefix}
+
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		// Creating questions waits until the codes are renumbered, reading them does not
+		if err := tx.Exec(`LOCK TABLE question_code_counters IN SHARE ROW EXCLUSIVE MODE`).Error; err != nil {
+			return err
+		}
+
+		var err error
+		summaries, err = q.plan(tx)
+		if err != nil {
+			return err
+		}
+
+		// Changed codes are moved aside first so renumbering never hits a code still in use
+		if err := tx.Exec(numberedQuestionsSQL+`
+			UPDATE questions SET code = '~' || questions.id FROM numbered
+			WHERE questions.id = numbered.id AND numbered.code IS DISTINCT FROM numbered.prefix || numbered.n`, args).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(numberedQuestionsSQL+`
+			UPDATE questions SET code = numbered.prefix || numbered.n FROM numbered
+			WHERE questions.id = numbered.id AND questions.code LIKE '~%'`, args).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(numberedQuestionsSQL+`
+			INSERT INTO question_code_counters (prefix, last_value)
+			SELECT prefix, MAX(n) FROM numbered GROUP BY prefix
+			ON CONFLICT (prefix) DO UPDATE SET last_value = EXCLUDED.last_value, updated_at = CURRENT_TIMESTAMP`, args).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Resequence] %s", q.name, err.Error()))
+		return summaries, err
+	}
+
+	return summaries, nil
+}
+
+func (q *questionCodeRepo) CountMissing() (int, error) {
+	var count int64
+
+	if err := q.db.Table("questions").Where("code IS NULL OR code = ''").Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Count Missing] %s", q.name, err.Error()))
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (q *questionCodeRepo) Backfill(batchSize int) (int, error) {
+	total := 0
+
+	for {
+		done := 0
+		err := q.db.Transaction(func(tx *gorm.DB) error {
+			var rows []struct {
+				ID     int
+				Prefix string
+			}
+
+			// Skip locked rows so a running server is never blocked by the backfill
+			if err := tx.Raw(`SELECT q.id, COALESCE(m.code_prefix, '') AS prefix
+				FROM questions AS q
+				LEFT JOIN materials AS m ON m.id = q.material_id
+				WHERE q.code IS NULL OR q.code = ''
+				ORDER BY q.id
+				LIMIT ?
+				FOR UPDATE OF q SKIP LOCKED`, batchSize).Scan(&rows).Error; err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				code, err := questioncode.Next(tx, row.Prefix)
+				if err != nil {
+					return err
+				}
+
+				if err := tx.Exec(`UPDATE questions SET code = ? WHERE id = ?`, code, row.ID).Error; err != nil {
+					return err
+				}
+			}
+
+			done = len(rows)
+			return nil
+		})
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Backfill] %s", q.name, err.Error()))
+			return total, err
+		}
+
+		total += done
+		if done < batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (q *questionCodeRepo) SyncCounters() error {
+	err := q.db.Exec(`INSERT INTO question_code_counters (prefix, last_value)
+		SELECT substring(code FROM '^([a-z]+)[0-9]+$'), MAX(substring(code FROM '^[a-z]+([0-9]+)$')::BIGINT)
+		FROM questions
+		WHERE code ~ '^[a-z]+[0-9]+$'
+		GROUP BY 1
+		ON CONFLICT (prefix) DO UPDATE SET last_value = GREATEST(question_code_counters.last_value, EXCLUDED.last_value), updated_at = CURRENT_TIMESTAMP`).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Sync Counters] %s", q.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (q *questionCodeRepo) Duplicates() ([]string, error) {
+	var codes []string
+
+	if err := q.db.Raw(`SELECT code FROM questions WHERE code <> '' GROUP BY code HAVING COUNT(*) > 1 ORDER BY code`).Scan(&codes).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Duplicates] %s", q.name, err.Error()))
+		return codes, err
+	}
+
+	return codes, nil
+}
+
+func (q *questionCodeRepo) CreateUniqueIndex() error {
+	if err := q.db.Exec(`CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS questions_code_key ON questions (code)`).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Create Unique Index] %s", q.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
@@ -14,6 +14,8 @@ type questionOption struct {
 }
 
 type QuestionOptionRepository interface {
+	// Use the transaction for the following changes
+	WithTx(tx *gorm.DB) QuestionOptionRepository
 	// Get option detail
 	Get(ID int) (entities.QuestionOption, error)
 	// Add Question Option
@@ -23,6 +25,10 @@ type QuestionOptionRepository interface {
 	// Delete Question Option
 	Delete(ID int) (bool, error)
 	GetTrueOption(questionID int) (entities.QuestionOption, error)
+	// Get true options of many questions
+	GetTrueOptions(questionIDs []int) ([]entities.QuestionOption, error)
+	// Get options of many questions
+	GetByQuestionIDs(questionIDs []int) ([]entities.QuestionOption, error)
 }
 
 func NewQuestionOptionRepository(db *gorm.DB) QuestionOptionRepository {
@@ -32,6 +38,13 @@ func NewQuestionOptionRepository(db *gorm.DB) QuestionOptionRepository {
 	}
 }
 
+func (q *questionOption) WithTx(tx *gorm.DB) QuestionOptionRepository {
+	return &questionOption{
+		db:   tx,
+		name: q.name,
+	}
+}
+
 func (q *questionOption) Get(ID int) (entities.QuestionOption, error) {
 	var questionOption entities.QuestionOption
 
@@ -54,6 +67,28 @@ func (q *questionOption) GetTrueOption(questionID int) (entities.QuestionOption,
 	return questionOption, nil
 }
 
+func (q *questionOption) GetTrueOptions(questionIDs []int) ([]entities.QuestionOption, error) {
+	var questionOptions []entities.QuestionOption
+
+	if err := q.db.Where("question_id IN ? AND option_value = ?", questionIDs, true).Find(&questionOptions).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetTrueOptions] %s", q.name, err.Error()))
+		return questionOptions, err
+	}
+
+	return questionOptions, nil
+}
+
+func (q *questionOption) GetByQuestionIDs(questionIDs []int) ([]entities.QuestionOption, error) {
+	var questionOptions []entities.QuestionOption
+
+	if err := q.db.Where("question_id IN ?", questionIDs).Order("created_at asc").Find(&questionOptions).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetByQuestionIDs] %s", q.name, err.Error()))
+		return questionOptions, err
+	}
+
+	return questionOptions, nil
+}
+
 func (q *questionOption) Create(param entities.QuestionOption) (entities.QuestionOption, error) {
 	if err := q.db.Debug().Create(&param).Error; err != nil {
 		logrus.Error(fmt.Sprintf("[%s][Create] %s", q.name, err.Error()))
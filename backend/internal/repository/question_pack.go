@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 	"gorm.io/gorm"
 )
@@ -31,6 +32,10 @@ type QuestionPackRepository interface {
 	AddQuestions(ID int, questionIDs []int) error
 	// Delete Question
 	DeleteQuestions(ID int, questionIDs []int) error
+	// Check whether question is part of question pack
+	HasQuestion(ID int, questionID int) (bool, error)
+	// Check whether question pack is free
+	IsFree(ID int) (bool, error)
 }
 
 func NewQuestionPackRepository(db *gorm.DB) QuestionPackRepository {
@@ -56,18 +61,27 @@ func (q *questionPackRepo) Update(pack entities.QuestionPack) (entities.Question
 	return pack, nil
 }
 
+var questionPackFilter = filter.Config{
+	SearchColumns: []string{"name"},
+	SortFields:    map[string]string{"id": "id", "name": "name", "time_limit": "time_limit", "created_at": "created_at"},
+	DefaultSort:   "created_at",
+}
+
 func (q *questionPackRepo) GetList(param params.QuestionPackFilterParam) ([]entities.QuestionPack, int, error) {
 	var packs []entities.QuestionPack
 	var count int64
-	db := q.db
-
-	db.Model(&packs).Count(&count)
+	db := q.db.Model(&entities.QuestionPack{}).Scopes(filter.Where(param.Filter(), questionPackFilter))
 
 	if param.IsActive != nil {
 		db = db.Where("is_active = ?", *param.IsActive)
 	}
 
-	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&packs).Error; err != nil {
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return packs, 0, err
+	}
+
+	if err := db.Debug().Scopes(filter.Sort(param.Filter(), questionPackFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&packs).Error; err != nil {
 		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
 		return packs, 0, err
 	}
@@ -151,3 +165,25 @@ func (q *questionPackRepo) Delete(ID int) error {
 
 	return nil
 }
+
+func (q *questionPackRepo) HasQuestion(ID int, questionID int) (bool, error) {
+	var count int64
+
+	if err := q.db.Table("question_pack_items").Where("question_pack_id = ? AND question_id = ?", ID, questionID).Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Has Question] %s", q.name, err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (q *questionPackRepo) IsFree(ID int) (bool, error) {
+	var pack entities.QuestionPack
+
+	if err := q.db.Select("id", "is_free").First(&pack, ID).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Is Free] %s", q.name, err.Error()))
+		return false, err
+	}
+
+	return pack.IsFree != nil && *pack.IsFree, nil
+}
@@ -1,15 +1,21 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
+	"gitlab.com/project-quiz/utils/pagination/cursor"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 	"gorm.io/gorm"
 )
 
+var ErrQuestionPackAttemptFinished = errors.New("question pack attempt is already finished")
+
 type questionPackAttemptRepo struct {
 	db   *gorm.DB
 	name string
@@ -24,6 +30,22 @@ type QuestionPackAttemptRepository interface {
 	Get(ID int) (entities.QuestionPackAttempt, error)
 	// Get Lis Question packet attemp
 	GetList(param params.QuestionPackAttemptFilterParam) ([]entities.QuestionPackAttempt, int, error)
+	// Get list of question pack attempt by keyset pagination, newest first. Next cursor is empty on the last page.
+	GetListByCursor(param params.QuestionPackAttemptFilterParam) ([]entities.QuestionPackAttempt, string, error)
+	// Count question pack attempt matching the filter
+	Count(param params.QuestionPackAttemptFilterParam) (int, error)
+	// Get question pack attempt with its score breakdown
+	GetWithScores(ID int) (entities.QuestionPackAttempt, error)
+	// Finish question pack attempt and store its score breakdown
+	Finish(pack entities.QuestionPackAttempt, scores []entities.QuestionPackAttemptScore) (entities.QuestionPackAttempt, error)
+	// Get latest unfinished attempt of user on a question pack
+	GetOpen(userID, questionPackID int) (entities.QuestionPackAttempt, error)
+	// Get latest finished attempt of user on a question pack
+	GetLatestFinished(userID, questionPackID int) (entities.QuestionPackAttempt, error)
+	// Count attempts of user on a question pack
+	CountByUserAndPack(userID, questionPackID int) (int, error)
+	// Get unfinished attempts whose deadline has passed, ordered by ID after the given ID
+	GetExpired(now time.Time, afterID, limit int) ([]entities.QuestionPackAttempt, error)
 }
 
 func NewQuestionPackAttemptRepository(db *gorm.DB) QuestionPackAttemptRepository {
@@ -62,37 +84,183 @@ func (q *questionPackAttemptRepo) Get(ID int) (entities.QuestionPackAttempt, err
 	return pack, nil
 }
 
+// Keyword of attempts is not searched, they have no text of their own
+var questionPackAttemptFilter = filter.Config{
+	DateColumn:  "started_at",
+	SortFields:  map[string]string{"id": "id", "score": "score", "started_at": "started_at", "finished_at": "finished_at", "created_at": "created_at"},
+	DefaultSort: "created_at",
+}
+
 func (q *questionPackAttemptRepo) GetList(param params.QuestionPackAttemptFilterParam) ([]entities.QuestionPackAttempt, int, error) {
 	var packs []entities.QuestionPackAttempt
 	var count int64
-	db := q.db
-	useFilterCount := false
+	db := q.listQuery(param)
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return packs, 0, err
+	}
+
+	if err := db.Debug().Scopes(filter.Sort(param.Filter(), questionPackAttemptFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&packs).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return packs, 0, err
+	}
+
+	return packs, int(count), nil
+}
+
+func (q *questionPackAttemptRepo) GetListByCursor(param params.QuestionPackAttemptFilterParam) ([]entities.QuestionPackAttempt, string, error) {
+	var packs []entities.QuestionPackAttempt
+	var createdAt time.Time
+	var id int
+
+	if param.Sort != "" {
+		return packs, "", cursor.ErrSortNotSupported
+	}
+
+	if param.Cursor != "" {
+		var err error
+		if id, err = cursor.Decode(param.Cursor, &createdAt); err != nil {
+			return packs, "", err
+		}
+	}
+
+	limit := cursor.Limit(param.Limit)
+	if err := q.listQuery(param).Scopes(cursor.Scope("created_at", "id", createdAt, id, limit)).Find(&packs).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List By Cursor] %s", q.name, err.Error()))
+		return packs, "", err
+	}
+
+	if len(packs) <= limit {
+		return packs, "", nil
+	}
+
+	packs = packs[:limit]
+	last := packs[limit-1]
+	next, err := cursor.Encode(last.CreatedAt, last.ID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List By Cursor] %s", q.name, err.Error()))
+		return packs, "", err
+	}
+
+	return packs, next, nil
+}
+
+func (q *questionPackAttemptRepo) Count(param params.QuestionPackAttemptFilterParam) (int, error) {
+	var count int64
 
-	db.Model(&packs).Count(&count)
+	if err := q.listQuery(param).Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Count] %s", q.name, err.Error()))
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (q *questionPackAttemptRepo) listQuery(param params.QuestionPackAttemptFilterParam) *gorm.DB {
+	db := q.db.Model(&entities.QuestionPackAttempt{}).Scopes(filter.Where(param.Filter(), questionPackAttemptFilter))
 
 	if param.IsFinish != nil {
 		db = db.Where("is_finish = ?", *param.IsFinish)
-		useFilterCount = true
 	}
 
 	if param.UserID != 0 {
 		db = db.Where("user_id = ?", param.UserID)
-		useFilterCount = true
 	}
 
 	if param.QuestionPackID != 0 {
 		db = db.Where("question_pack_id = ?", param.QuestionPackID)
-		useFilterCount = true
 	}
 
-	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&packs).Error; err != nil {
-		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
-		return packs, 0, err
+	return db
+}
+
+func (q *questionPackAttemptRepo) GetWithScores(ID int) (entities.QuestionPackAttempt, error) {
+	var pack entities.QuestionPackAttempt
+
+	if err := q.db.Preload("Scores").First(&pack, ID).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetWithScores] %s", q.name, err.Error()))
+		return pack, err
+	}
+
+	return pack, nil
+}
+
+func (q *questionPackAttemptRepo) Finish(pack entities.QuestionPackAttempt, scores []entities.QuestionPackAttemptScore) (entities.QuestionPackAttempt, error) {
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		// Guard against the attempt being finished twice, e.g. by the user and the expiry worker
+		result := tx.Model(&pack).Where("is_finish = ?", false).Select("is_finish", "finished_at", "score", "max_score").Updates(&pack)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrQuestionPackAttemptFinished
+		}
+
+		if err := tx.Where("question_pack_attempt_id = ?", pack.ID).Delete(&entities.QuestionPackAttemptScore{}).Error; err != nil {
+			return err
+		}
+
+		if len(scores) == 0 {
+			return nil
+		}
+
+		for i := range scores {
+			scores[i].QuestionPackAttemptID = pack.ID
+		}
+
+		return tx.Create(&scores).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Finish] %s", q.name, err.Error()))
+		return pack, err
 	}
 
-	if useFilterCount {
-		db.Count(&count)
+	pack.Scores = scores
+	return pack, nil
+}
+
+func (q *questionPackAttemptRepo) GetExpired(now time.Time, afterID, limit int) ([]entities.QuestionPackAttempt, error) {
+	var packs []entities.QuestionPackAttempt
+
+	if err := q.db.Where("is_finish = ? AND deadline IS NOT NULL AND deadline < ? AND id > ?", false, now, afterID).Order("id asc").Limit(limit).Find(&packs).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetExpired] %s", q.name, err.Error()))
+		return packs, err
 	}
 
-	return packs, int(count), nil
+	return packs, nil
+}
+
+func (q *questionPackAttemptRepo) GetOpen(userID, questionPackID int) (entities.QuestionPackAttempt, error) {
+	var pack entities.QuestionPackAttempt
+
+	if err := q.db.Where("user_id = ? AND question_pack_id = ? AND is_finish = ?", userID, questionPackID, false).Order("started_at desc").First(&pack).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetOpen] %s", q.name, err.Error()))
+		return pack, err
+	}
+
+	return pack, nil
+}
+
+func (q *questionPackAttemptRepo) GetLatestFinished(userID, questionPackID int) (entities.QuestionPackAttempt, error) {
+	var pack entities.QuestionPackAttempt
+
+	if err := q.db.Where("user_id = ? AND question_pack_id = ? AND is_finish = ?", userID, questionPackID, true).Order("finished_at desc").First(&pack).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetLatestFinished] %s", q.name, err.Error()))
+		return pack, err
+	}
+
+	return pack, nil
+}
+
+func (q *questionPackAttemptRepo) CountByUserAndPack(userID, questionPackID int) (int, error) {
+	var count int64
+
+	if err := q.db.Model(&entities.QuestionPackAttempt{}).Where("user_id = ? AND question_pack_id = ?", userID, questionPackID).Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][CountByUserAndPack] %s", q.name, err.Error()))
+		return 0, err
+	}
+
+	return int(count), nil
 }
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
+	"gorm.io/gorm"
+)
+
+var ErrReviewStatusChanged = errors.New("review status of the question has been changed, please reload")
+
+type questionReviewRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type QuestionReviewRepository interface {
+	// Move question from the given status and record the transition in one transaction
+	Transition(questionID int, from string, changes map[string]interface{}, log entities.QuestionReviewLog) (entities.QuestionReviewLog, error)
+	// List questions waiting for review
+	Queue(param params.QuestionReviewQueueParam) ([]entities.QuestionReviewQueueItem, int, error)
+	// Add reviewer comment
+	AddComment(comment entities.QuestionReviewComment) (entities.QuestionReviewComment, error)
+	// List comments of a question
+	ListComments(questionID int) ([]entities.QuestionReviewComment, error)
+	// List review audit trail of a question
+	ListLogs(questionID int) ([]entities.QuestionReviewLog, error)
+}
+
+func NewQuestionReviewRepository(db *gorm.DB) QuestionReviewRepository {
+	return &questionReviewRepo{
+		db:   db,
+		name: "Question Review Repository",
+	}
+}
+
+func (q *questionReviewRepo) Transition(questionID int, from string, changes map[string]interface{}, log entities.QuestionReviewLog) (entities.QuestionReviewLog, error) {
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		// Questions created before the review workflow may have no status yet
+		query := tx.Model(&entities.Question{}).Where("id = ?", questionID)
+		if from == entities.ReviewStatusApproved {
+			query = query.Where("(review_status = ? OR review_status = '' OR review_status IS NULL)", from)
+		} else {
+			query = query.Where("review_status = ?", from)
+		}
+
+		res := query.Updates(changes)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrReviewStatusChanged
+		}
+
+		return tx.Create(&log).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Transition] %s", q.name, err.Error()))
+		return log, err
+	}
+
+	return log, nil
+}
+
+func (q *questionReviewRepo) Queue(param params.QuestionReviewQueueParam) ([]entities.QuestionReviewQueueItem, int, error) {
+	var items []entities.QuestionReviewQueueItem
+	var count int64
+
+	statuses := param.Status
+	if len(statuses) == 0 {
+		statuses = []string{entities.ReviewStatusSubmitted, entities.ReviewStatusInReview}
+	}
+
+	db := q.db.Table("questions AS q").
+		Joins("INNER JOIN materials AS m ON q.material_id = m.id").
+		Where("q.review_status IN ?", statuses)
+
+	if param.ReviewerID != 0 {
+		db = db.Where("q.reviewer_id = ?", param.ReviewerID)
+	}
+
+	if param.MaterialID != 0 {
+		db = db.Where("q.material_id = ?", param.MaterialID)
+	}
+
+	if param.ContributorID != 0 {
+		db = db.Where("q.contributor_id = ?", param.ContributorID)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Queue] %s", q.name, err.Error()))
+		return items, 0, err
+	}
+
+	// Oldest submission first so questions do not wait forever
+	if err := db.Select(`q.id, q.code, q.body, m."name" AS material, q.contributor_id, q.review_status, q.reviewer_id, q.submitted_at, q.updated_at`).
+		Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).
+		Order("q.submitted_at asc NULLS LAST, q.id asc").
+		Scan(&items).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Queue] %s", q.name, err.Error()))
+		return items, 0, err
+	}
+
+	return items, int(count), nil
+}
+
+func (q *questionReviewRepo) AddComment(comment entities.QuestionReviewComment) (entities.QuestionReviewComment, error) {
+	if err := q.db.Create(&comment).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Add Comment] %s", q.name, err.Error()))
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+func (q *questionReviewRepo) ListComments(questionID int) ([]entities.QuestionReviewComment, error) {
+	var comments []entities.QuestionReviewComment
+
+	if err := q.db.Where("question_id = ?", questionID).Order("created_at asc, id asc").Find(&comments).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List Comments] %s", q.name, err.Error()))
+		return comments, err
+	}
+
+	return comments, nil
+}
+
+func (q *questionReviewRepo) ListLogs(questionID int) ([]entities.QuestionReviewLog, error) {
+	var logs []entities.QuestionReviewLog
+
+	if err := q.db.Where("question_id = ?", questionID).Order("created_at asc, id asc").Find(&logs).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List Logs] %s", q.name, err.Error()))
+		return logs, err
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type questionRevisionRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type QuestionRevisionRepository interface {
+	// Record current content of the question as a new revision, nothing is recorded when it equals the latest revision
+	Record(questionID, actorID int, action string) (entities.QuestionRevision, error)
+	// Revise runs the change in one transaction with the revision recorded before and after it,
+	// the change is rolled back when a revision can not be recorded
+	Revise(questionID, actorID int, action string, change func(tx *gorm.DB) error) (entities.QuestionRevision, error)
+	// List revisions of a question, newest first
+	List(questionID, page, limit int) ([]entities.QuestionRevision, int, error)
+	// Get revision of a question
+	Get(questionID, revisionID int) (entities.QuestionRevision, error)
+	// Restore question, options and solution to the revision and record it as a new revision
+	Restore(revision entities.QuestionRevision, actorID int) (entities.QuestionRevision, error)
+}
+
+func NewQuestionRevisionRepository(db *gorm.DB) QuestionRevisionRepository {
+	return &questionRevisionRepo{
+		db:   db,
+		name: "Question Revision Repository",
+	}
+}
+
+func (q *questionRevisionRepo) Record(questionID, actorID int, action string) (entities.QuestionRevision, error) {
+	var revision entities.QuestionRevision
+
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		revision, err = recordRevision(tx, questionID, actorID, action, nil)
+		return err
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Record] %s", q.name, err.Error()))
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+func (q *questionRevisionRepo) Revise(questionID, actorID int, action string, change func(tx *gorm.DB) error) (entities.QuestionRevision, error) {
+	var revision entities.QuestionRevision
+
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		// Keep what the question looks like before the change, in case it was changed without a revision
+		if _, err := recordRevision(tx, questionID, actorID, entities.RevisionActionBaseline, nil); err != nil {
+			return err
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		var err error
+		revision, err = recordRevision(tx, questionID, actorID, action, nil)
+		return err
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Revise] question %d: %s", q.name, questionID, err.Error()))
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+// recordRevision must run inside a transaction, the question row is locked so revision numbers do not collide
+func recordRevision(tx *gorm.DB, questionID, actorID int, action string, rollbackOf *int) (entities.QuestionRevision, error) {
+	var question entities.Question
+	var options []entities.QuestionOption
+	var solutions []entities.QuestionSolution
+	var latest entities.QuestionRevision
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, questionID).Error; err != nil {
+		return latest, err
+	}
+
+	if err := tx.Where("question_id = ?", questionID).Order("id asc").Find(&options).Error; err != nil {
+		return latest, err
+	}
+
+	if err := tx.Where("question_id = ?", questionID).Order("id asc").Limit(1).Find(&solutions).Error; err != nil {
+		return latest, err
+	}
+
+	var solution *entities.QuestionSolution
+	if len(solutions) > 0 {
+		solution = &solutions[0]
+	}
+	snapshot := entities.NewQuestionSnapshot(question, options, solution)
+
+	err := tx.Where("question_id = ?", questionID).Order("revision desc").First(&latest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return latest, err
+	}
+	if err == nil && rollbackOf == nil && latest.Snapshot.Equal(snapshot) {
+		return latest, nil
+	}
+
+	revision := entities.QuestionRevision{
+		QuestionID: questionID,
+		Revision:   latest.Revision + 1,
+		ActorID:    actorID,
+		Action:     action,
+		RollbackOf: rollbackOf,
+		Snapshot:   snapshot,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return revision, err
+	}
+
+	if err := tx.Model(&entities.Question{}).Where("id = ?", questionID).UpdateColumn("revision_id", revision.ID).Error; err != nil {
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+func (q *questionRevisionRepo) List(questionID, page, limit int) ([]entities.QuestionRevision, int, error) {
+	var revisions []entities.QuestionRevision
+	var count int64
+
+	db := q.db.Model(&entities.QuestionRevision{}).Where("question_id = ?", questionID)
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return revisions, 0, err
+	}
+
+	if err := db.Scopes(gorm_pagination.Paginate(page, limit)).Order("revision desc").Find(&revisions).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return revisions, 0, err
+	}
+
+	return revisions, int(count), nil
+}
+
+func (q *questionRevisionRepo) Get(questionID, revisionID int) (entities.QuestionRevision, error) {
+	var revision entities.QuestionRevision
+
+	if err := q.db.Where("question_id = ?", questionID).First(&revision, revisionID).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get] %s", q.name, err.Error()))
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+func (q *questionRevisionRepo) Restore(revision entities.QuestionRevision, actorID int) (entities.QuestionRevision, error) {
+	var restored entities.QuestionRevision
+	snapshot := revision.Snapshot
+
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		// Keep what the question looks like now before it is overwritten
+		if _, err := recordRevision(tx, revision.QuestionID, actorID, entities.RevisionActionBaseline, nil); err != nil {
+			return err
+		}
+
+		isPackOnly := snapshot.IsPackOnly
+		question := entities.Question{
+			ID:               revision.QuestionID,
+			Body:             snapshot.Body,
+			MaterialID:       snapshot.MaterialID,
+			IsImage:          snapshot.IsImage,
+			ImgPath:          snapshot.ImgPath,
+			ImgPlacementUrl:  snapshot.ImgPlacementUrl,
+			IsPackOnly:       &isPackOnly,
+			Type:             snapshot.Type,
+			ScoringMode:      snapshot.ScoringMode,
+			NumericAnswer:    snapshot.NumericAnswer,
+			NumericTolerance: snapshot.NumericTolerance,
+			AcceptedAnswers:  snapshot.AcceptedAnswers,
+			CaseSensitive:    snapshot.CaseSensitive,
+		}
+		if err := tx.Model(&question).Select("body", "material_id", "is_image", "img_path", "img_placement_url", "is_pack_only", "type",
+			"scoring_mode", "numeric_answer", "numeric_tolerance", "accepted_answers", "case_sensitive").Updates(&question).Error; err != nil {
+			return err
+		}
+
+		// Options keep their ID so attempts still point to the option they answered
+		var keep []int
+		for _, item := range snapshot.Options {
+			optionValue := item.OptionValue
+			option := entities.QuestionOption{
+				ID:          item.ID,
+				QuestionID:  revision.QuestionID,
+				Body:        item.Body,
+				OptionValue: &optionValue,
+				IsImage:     item.IsImage,
+				ImgPath:     item.ImgPath,
+				Position:    item.Position,
+				MatchBody:   item.MatchBody,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"body", "option_value", "is_image", "img_path", "position", "match_body", "updated_at"}),
+			}).Create(&option).Error; err != nil {
+				return err
+			}
+			keep = append(keep, item.ID)
+		}
+
+		deleteOptions := tx.Where("question_id = ?", revision.QuestionID)
+		if len(keep) > 0 {
+			deleteOptions = deleteOptions.Where("id NOT IN ?", keep)
+		}
+		if err := deleteOptions.Delete(&entities.QuestionOption{}).Error; err != nil {
+			return err
+		}
+
+		if snapshot.Solution == nil {
+			if err := tx.Where("question_id = ?", revision.QuestionID).Delete(&entities.QuestionSolution{}).Error; err != nil {
+				return err
+			}
+		} else {
+			isPremium := snapshot.Solution.IsPremium
+			solution := entities.QuestionSolution{
+				QuestionID:     revision.QuestionID,
+				SolutionType:   snapshot.Solution.SolutionType,
+				SolutionText:   snapshot.Solution.SolutionText,
+				SolutionImgUrl: snapshot.Solution.SolutionImgUrl,
+				PdfFileUrl:     snapshot.Solution.PdfFileUrl,
+				Link:           snapshot.Solution.Link,
+				IsPremium:      &isPremium,
+			}
+
+			res := tx.Model(&entities.QuestionSolution{}).Where("question_id = ?", revision.QuestionID).
+				Select("solution_type", "solution_text", "solution_img_url", "pdf_file_url", "link", "is_premium").Updates(&solution)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				if err := tx.Create(&solution).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var err error
+		restored, err = recordRevision(tx, revision.QuestionID, actorID, entities.RevisionActionRollback, &revision.ID)
+		return err
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Restore] %s", q.name, err.Error()))
+		return restored, err
+	}
+
+	return restored, nil
+}
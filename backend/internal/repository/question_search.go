@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// questionTsQuery matches the keyword stemmed in Indonesian or in English
+const questionTsQuery = "(websearch_to_tsquery('indonesian', ?) || websearch_to_tsquery('english', ?))"
+
+const questionHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// questionSearch is the full text search of question list over body, options and tags.
+// Solutions are not indexed, premium solutions must not be found by students without the package.
+type questionSearch struct {
+	keyword string
+	table   string
+}
+
+// newQuestionSearch returns nil when there is no keyword to search
+func newQuestionSearch(keyword, table string) *questionSearch {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil
+	}
+
+	return &questionSearch{keyword: keyword, table: table}
+}
+
+func (s *questionSearch) Where(db *gorm.DB) *gorm.DB {
+	return db.Where(s.table+".search_vector @@ "+questionTsQuery, s.keyword, s.keyword)
+}
+
+// Select selects the columns and the highlighted fragments of the column as snippet
+func (s *questionSearch) Select(db *gorm.DB, columns, column string) *gorm.DB {
+	return db.Select(columns+", ts_headline('indonesian', "+s.table+"."+column+", "+questionTsQuery+", ?) AS snippet",
+		s.keyword, s.keyword, questionHeadlineOptions)
+}
+
+// Order puts the best matches first
+func (s *questionSearch) Order() clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "ts_rank_cd(" + s.table + ".search_vector, " + questionTsQuery + ") DESC, " + s.table + ".id DESC",
+		Vars:               []interface{}{s.keyword, s.keyword},
+		WithoutParentheses: true,
+	}}
+}
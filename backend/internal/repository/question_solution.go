@@ -28,10 +28,14 @@ type questionSolution struct {
 }
 
 type QuestionSolutionRepository interface {
+	// Use the transaction for the following changes
+	WithTx(tx *gorm.DB) QuestionSolutionRepository
 	// Get option detail
 	Get(questionID int) (entities.QuestionSolution, error)
 	// Get question solution by ID
 	GetByID(ID int) (entities.QuestionSolution, error)
+	// Get solutions of many questions
+	GetByQuestionIDs(questionIDs []int) ([]entities.QuestionSolution, error)
 	// Create question solution
 	Create(solution entities.QuestionSolution) (entities.QuestionSolution, error)
 	// Update question solution
@@ -47,6 +51,13 @@ func NewQuestionSolutionRepository(db *gorm.DB) QuestionSolutionRepository {
 	}
 }
 
+func (q *questionSolution) WithTx(tx *gorm.DB) QuestionSolutionRepository {
+	return &questionSolution{
+		db:   tx,
+		name: q.name,
+	}
+}
+
 func (q *questionSolution) Get(questionID int) (entities.QuestionSolution, error) {
 	var questionSolution entities.QuestionSolution
 
@@ -69,6 +80,17 @@ func (q *questionSolution) GetByID(ID int) (entities.QuestionSolution, error) {
 	return questionSolution, nil
 }
 
+func (q *questionSolution) GetByQuestionIDs(questionIDs []int) ([]entities.QuestionSolution, error) {
+	var questionSolutions []entities.QuestionSolution
+
+	if err := q.db.Where("question_id IN ?", questionIDs).Find(&questionSolutions).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetByQuestionIDs] %s", q.name, err.Error()))
+		return questionSolutions, err
+	}
+
+	return questionSolutions, nil
+}
+
 func (q *questionSolution) Create(solution entities.QuestionSolution) (entities.QuestionSolution, error) {
 	// Validate solution type
 	if valid := solutionValid(solution.SolutionType, solutionTypeChoices); !valid {
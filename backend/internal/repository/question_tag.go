@@ -6,6 +6,7 @@ import (
 
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 
 	log "github.com/sirupsen/logrus"
@@ -30,6 +31,8 @@ type QuestionTagRepository interface {
 	Get(ID int) (entities.QuestionTag, error)
 	// Get qeuestion tag By name
 	GetByName(name string) (entities.QuestionTag, error)
+	// Get tags by their names, case insensitive
+	ListByNames(names []string) ([]entities.QuestionTag, error)
 	// Delete qeuestion tag
 	Delete(ID int) (entities.QuestionTag, error)
 }
@@ -67,21 +70,26 @@ func (q *QuestionTagRepo) Get(ID int) (entities.QuestionTag, error) {
 	return tag, nil
 }
 
+var questionTagFilter = filter.Config{
+	SearchColumns: []string{"name"},
+	SortFields:    map[string]string{"id": "id", "name": "name", "created_at": "created_at"},
+	DefaultSort:   "created_at",
+}
+
 func (q *QuestionTagRepo) List(param params.QuestionTagFilter) ([]entities.QuestionTag, int, error) {
 	log.Info(fmt.Sprintf("[%s][Update] is executed", q.name))
 
 	var tags []entities.QuestionTag
 
 	var count int64
-	q.db.Find(&tags).Count(&count)
-
-	db := q.db
+	db := q.db.Model(&entities.QuestionTag{}).Scopes(filter.Where(param.Filter(), questionTagFilter))
 
-	if param.Q != "" {
-		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(param.Q)+"%")
+	if err := db.Count(&count).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
+		return tags, int(count), err
 	}
 
-	if err := db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&tags).Error; err != nil {
+	if err := db.Debug().Scopes(filter.Sort(param.Filter(), questionTagFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&tags).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][List] %s", q.name, err.Error()))
 		return tags, int(count), err
 	}
@@ -135,3 +143,19 @@ func (q *QuestionTagRepo) ListIn(IDs []int) ([]entities.QuestionTag, int, error)
 
 	return tags, int(count), nil
 }
+
+func (q *QuestionTagRepo) ListByNames(names []string) ([]entities.QuestionTag, error) {
+	var tags []entities.QuestionTag
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	if err := q.db.Where("LOWER(name) IN ?", lowered).Find(&tags).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][ListByNames] %s", q.name, err.Error()))
+		return tags, err
+	}
+
+	return tags, nil
+}
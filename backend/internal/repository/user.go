@@ -5,6 +5,7 @@ import (
 
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 
 	log "github.com/sirupsen/logrus"
@@ -75,21 +76,27 @@ func (u *userRepo) GetByEmail(email string) (entities.User, error) {
 	return user, nil
 }
 
+var userFilter = filter.Config{
+	SearchColumns: []string{"name", "email"},
+	SortFields:    map[string]string{"id": "id", "name": "name", "email": "email", "created_at": "created_at"},
+	DefaultSort:   "created_at",
+}
+
 func (u *userRepo) List(users []entities.User, param params.UserListParams) ([]entities.User, int, error) {
 	log.Info(fmt.Sprintf("[%s][Update] is executed", u.name))
 
 	var count int64
 
-	db := u.db
-	if param.Q != "" {
-		db = db.Where("name LIKE ?", param.Q+"%").Or("email LIKE ?", param.Q+"%")
-	}
+	db := u.db.Model(&entities.User{}).Scopes(filter.Where(param.Filter(), userFilter))
 
-	db.Debug().Select("id").Find(&users).Count(&count)
+	if err := db.Count(&count).Error; err != nil {
+		log.Error(fmt.Sprintf("[%s][List] %s", u.name, err.Error()))
+		return users, int(count), err
+	}
 
-	db = db.Debug().Scopes(gorm_pagination.Paginate(param.Page, param.Limit))
+	db = db.Debug().Scopes(filter.Sort(param.Filter(), userFilter), gorm_pagination.Paginate(param.Page, param.Limit))
 
-	if err := db.Order("created_at desc").Find(&users).Error; err != nil {
+	if err := db.Find(&users).Error; err != nil {
 		log.Error(fmt.Sprintf("[%s][List] %s", u.name, err.Error()))
 		return users, int(count), err
 	}
@@ -1,12 +1,13 @@
 package repository
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/filter"
+	"gitlab.com/project-quiz/utils/pagination/cursor"
 	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
 	"gorm.io/gorm"
 )
@@ -16,17 +17,16 @@ type userPoint struct {
 	name string
 }
 
+// UserPointRepository reads balances, which only change through PointTransactionRepository
 type UserPointRepository interface {
 	// Get User Point
 	GetByUser(UserID int) (entities.UserPoint, error)
 	// Get List of User Point
 	GetListOfUserPoint(params.UserPointFilterParam) ([]entities.UserPoint, int, error)
-	// Update User Point
-	Update(entities.UserPoint) (entities.UserPoint, error)
-	// Create User Point
-	Create(entities.UserPoint) (entities.UserPoint, error)
-	// Update or Create User Point
-	UpdateOrCreate(UserID, AddedPoint int) (entities.UserPoint, error)
+	// Get List of User Point by keyset pagination, highest point first. Next cursor is empty on the last page.
+	GetListOfUserPointByCursor(params.UserPointFilterParam) ([]entities.UserPoint, string, error)
+	// Count User Point matching the filter
+	CountOfUserPoint(params.UserPointFilterParam) (int, error)
 }
 
 func NewUserPointRepository(db *gorm.DB) UserPointRepository {
@@ -47,69 +47,81 @@ func (u *userPoint) GetByUser(UserID int) (entities.UserPoint, error) {
 	return up, nil
 }
 
-func (u *userPoint) Update(up entities.UserPoint) (entities.UserPoint, error) {
-	if err := u.db.Debug().Model(&up).Updates(&up).Error; err != nil {
-		logrus.Error(fmt.Sprintf("[%s][Update] %s", u.name, err.Error()))
-		return up, err
-	}
-
-	return up, nil
+// User is joined so points can be searched and sorted by the name of their user
+var userPointFilter = filter.Config{
+	SearchColumns: []string{`"User".name`, `"User".email`},
+	DateColumn:    "user_points.updated_at",
+	SortFields:    map[string]string{"point": "user_points.point", "name": `"User".name`, "updated_at": "user_points.updated_at"},
+	DefaultSort:   "point",
 }
 
-func (u *userPoint) Create(up entities.UserPoint) (entities.UserPoint, error) {
-	if err := u.db.Debug().Create(&up).Error; err != nil {
-		logrus.Error(fmt.Sprintf("[%s][Create] %s", u.name, err.Error()))
-		return up, err
+func (u *userPoint) GetListOfUserPoint(param params.UserPointFilterParam) ([]entities.UserPoint, int, error) {
+	var userPoints []entities.UserPoint
+
+	var count int64
+	db := u.listQuery(param)
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetListOfUserPoint] %s", u.name, err.Error()))
+		return userPoints, int(count), err
 	}
 
-	return up, nil
+	if err := db.Debug().Scopes(filter.Sort(param.Filter(), userPointFilter), gorm_pagination.Paginate(param.Page, param.Limit)).Find(&userPoints).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetListOfUserPoint] %s", u.name, err.Error()))
+		return userPoints, int(count), err
+	}
+
+	return userPoints, int(count), nil
 }
 
-func (u *userPoint) UpdateOrCreate(UserID, AddedPoint int) (entities.UserPoint, error) {
-	var up entities.UserPoint
+func (u *userPoint) GetListOfUserPointByCursor(param params.UserPointFilterParam) ([]entities.UserPoint, string, error) {
+	var userPoints []entities.UserPoint
+	var point, id int
 
-	// Check if user point exist
-	up, err := u.GetByUser(UserID)
+	if param.Sort != "" {
+		return userPoints, "", cursor.ErrSortNotSupported
+	}
 
-	// Update user point
-	if err == nil {
-		up.Point += AddedPoint
-		up, er := u.Update(up)
-		if er != nil {
-			logrus.Error(fmt.Sprintf("[%s][UpdateOrCreate] %s", u.name, er.Error()))
-			return up, er
+	if param.Cursor != "" {
+		var err error
+		if id, err = cursor.Decode(param.Cursor, &point); err != nil {
+			return userPoints, "", err
 		}
 	}
 
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		// logrus.Error(fmt.Sprintf("[%s][UpdateOrCreate] %s", u.name, err.Error()))
-		return up, err
+	limit := cursor.Limit(param.Limit)
+	db := u.listQuery(param).Scopes(cursor.Scope("user_points.point", "user_points.id", point, id, limit))
+	if err := db.Find(&userPoints).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetListOfUserPointByCursor] %s", u.name, err.Error()))
+		return userPoints, "", err
 	}
 
-	// Create new User Point
-	up.UserID = UserID
-	up.Point = AddedPoint
-	up, err = u.Create(up)
+	if len(userPoints) <= limit {
+		return userPoints, "", nil
+	}
+
+	userPoints = userPoints[:limit]
+	last := userPoints[limit-1]
+	next, err := cursor.Encode(last.Point, last.ID)
 	if err != nil {
-		logrus.Error(fmt.Sprintf("[%s][UpdateOrCreate] %s", u.name, err.Error()))
-		return up, err
+		logrus.Error(fmt.Sprintf("[%s][GetListOfUserPointByCursor] %s", u.name, err.Error()))
+		return userPoints, "", err
 	}
 
-	return up, nil
+	return userPoints, next, nil
 }
 
-func (u *userPoint) GetListOfUserPoint(param params.UserPointFilterParam) ([]entities.UserPoint, int, error) {
-	var userPoints []entities.UserPoint
-
+func (u *userPoint) CountOfUserPoint(param params.UserPointFilterParam) (int, error) {
 	var count int64
-	u.db.Find(&userPoints).Count(&count)
 
-	db := u.db
-
-	if err := db.Debug().Preload("User").Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("point desc").Find(&userPoints).Error; err != nil {
-		logrus.Error(fmt.Sprintf("[%s][GetListOfUserPoint] %s", u.name, err.Error()))
-		return userPoints, int(count), err
+	if err := u.listQuery(param).Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][CountOfUserPoint] %s", u.name, err.Error()))
+		return 0, err
 	}
 
-	return userPoints, int(count), nil
+	return int(count), nil
+}
+
+func (u *userPoint) listQuery(param params.UserPointFilterParam) *gorm.DB {
+	return u.db.Model(&entities.UserPoint{}).Joins("User").Scopes(filter.Where(param.Filter(), userPointFilter))
 }
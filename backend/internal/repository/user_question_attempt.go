@@ -20,11 +20,15 @@ type UserQuestionAttemptRepository interface {
 	Update(attempt entities.UserQuestionAttempt) (entities.UserQuestionAttempt, error)
 	// Update Field
 	UpdateField(attempt entities.UserQuestionAttempt, fields []string) (entities.UserQuestionAttempt, error)
-	// Get Latest
-	GetLatest(questionID int, userID int) (entities.UserQuestionAttempt, error)
+	// Get Latest, packAttemptID nil means free practice answer
+	GetLatest(questionID int, userID int, packAttemptID *int) (entities.UserQuestionAttempt, error)
 	// Get Latets answered
-	GetLatestSubmitted(questionID int, userID int) (entities.UserQuestionAttempt, error)
-	GetLatestSubmittedAnswers(questionIDs []int, userID int) ([]entities.UserQuestionAttempt, error)
+	GetLatestSubmitted(questionID int, userID int, packAttemptID *int) (entities.UserQuestionAttempt, error)
+	GetLatestSubmittedAnswers(questionIDs []int, userID int, packAttemptID *int) ([]entities.UserQuestionAttempt, error)
+	// Get latest answer of every question answered inside a question pack attempt
+	GetPackAttemptAnswers(packAttemptID int) ([]entities.UserQuestionAttempt, error)
+	// Check whether the user submitted a correct answer of the question other than the attempt
+	HasCorrectAnswer(questionID, userID, exceptAttemptID int) (bool, error)
 	GetTotalAttempt(userID int) (int, error)
 	GetTotalAttemptWithValueType(userID int, valueType bool) (int, error)
 }
@@ -63,9 +67,21 @@ func (uqa *userQuestionAttempt) UpdateField(attempt entities.UserQuestionAttempt
 	return attempt, nil
 }
 
-func (uqa *userQuestionAttempt) GetLatest(questionID int, userID int) (entities.UserQuestionAttempt, error) {
+// packAttemptScope separate answers given inside a question pack attempt
+// from the free practice ones
+func packAttemptScope(packAttemptID *int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if packAttemptID == nil {
+			return db.Where("question_pack_attempt_id IS NULL")
+		}
+
+		return db.Where("question_pack_attempt_id = ?", *packAttemptID)
+	}
+}
+
+func (uqa *userQuestionAttempt) GetLatest(questionID int, userID int, packAttemptID *int) (entities.UserQuestionAttempt, error) {
 	var attempt entities.UserQuestionAttempt
-	if err := uqa.db.Where("question_id = ? AND user_id = ? AND is_submitted = ?", questionID, userID, false).Order("created_at desc").First(&attempt).Error; err != nil {
+	if err := uqa.db.Scopes(packAttemptScope(packAttemptID)).Where("question_id = ? AND user_id = ? AND is_submitted = ?", questionID, userID, false).Order("created_at desc").First(&attempt).Error; err != nil {
 		logrus.Error(fmt.Sprintf("[%s][GetLatest] %s", uqa.name, err.Error()))
 		return attempt, err
 	}
@@ -73,26 +89,57 @@ func (uqa *userQuestionAttempt) GetLatest(questionID int, userID int) (entities.
 	return attempt, nil
 }
 
-func (uqa *userQuestionAttempt) GetLatestSubmitted(questionID int, userID int) (entities.UserQuestionAttempt, error) {
+func (uqa *userQuestionAttempt) GetLatestSubmitted(questionID int, userID int, packAttemptID *int) (entities.UserQuestionAttempt, error) {
 	var attempt entities.UserQuestionAttempt
-	if err := uqa.db.Where("question_id = ? AND user_id = ? AND is_submitted = ?", questionID, userID, true).Order("created_at desc").First(&attempt).Error; err != nil {
+	if err := uqa.db.Scopes(packAttemptScope(packAttemptID)).Where("question_id = ? AND user_id = ? AND is_submitted = ?", questionID, userID, true).Order("created_at desc").First(&attempt).Error; err != nil {
 		logrus.Error(fmt.Sprintf("[%s][GetLatest] %s", uqa.name, err.Error()))
 		return attempt, err
 	}
 
 	return attempt, nil
 }
-func (uqa *userQuestionAttempt) GetLatestSubmittedAnswers(questionIDs []int, userID int) ([]entities.UserQuestionAttempt, error) {
+func (uqa *userQuestionAttempt) GetLatestSubmittedAnswers(questionIDs []int, userID int, packAttemptID *int) ([]entities.UserQuestionAttempt, error) {
 	var attempts []entities.UserQuestionAttempt
-	sqlStatement := `select id, question_id, question_option_id, user_id, attempt_value, is_submitted
+	latest := uqa.db.Model(&entities.UserQuestionAttempt{}).
+		Select("*, row_number() over(partition by question_id, user_id order by is_submitted desc, created_at desc) as rn").
+		Scopes(packAttemptScope(packAttemptID)).
+		Where("user_id = ? AND question_id IN ?", userID, questionIDs)
+
+	if err := uqa.db.Debug().Table("(?) as R", latest).
+		Select("id, question_id, question_option_id, question_pack_attempt_id, user_id, attempt_value, is_marked, is_submitted, time_spent, question_option_ids, answer_text, answer_pairs, credit").
+		Where("R.rn = 1").Scan(&attempts).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetLatestSubmittedAnswers] %s", uqa.name, err.Error()))
+		return attempts, err
+	}
+
+	return attempts, nil
+}
+
+func (uqa *userQuestionAttempt) HasCorrectAnswer(questionID, userID, exceptAttemptID int) (bool, error) {
+	var count int64
+	if err := uqa.db.Model(&entities.UserQuestionAttempt{}).
+		Where("question_id = ? AND user_id = ? AND is_submitted = ? AND attempt_value = ? AND id <> ?", questionID, userID, true, true, exceptAttemptID).
+		Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][HasCorrectAnswer] %s", uqa.name, err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (uqa *userQuestionAttempt) GetPackAttemptAnswers(packAttemptID int) ([]entities.UserQuestionAttempt, error) {
+	var attempts []entities.UserQuestionAttempt
+	sqlStatement := `select id, question_id, question_option_id, question_pack_attempt_id, user_id, attempt_value, is_marked, is_submitted, time_spent,
+		question_option_ids, answer_text, answer_pairs, credit
 	from (
-		select id, question_id, question_option_id, user_id, attempt_value, is_submitted, row_number() over(partition by question_id, user_id order by is_submitted desc, created_at desc) as rn
+		select *, row_number() over(partition by question_id order by created_at desc, id desc) as rn
 		from user_question_attempts uqa
+		where uqa.question_pack_attempt_id = ?
 	) as R
-	where R.rn = 1 and R.user_id = ? and R.question_id in ?`
+	where R.rn = 1`
 
-	if err := uqa.db.Debug().Raw(sqlStatement, userID, questionIDs).Scan(&attempts).Error; err != nil {
-		logrus.Error(fmt.Sprintf("[%s][GetLatestSubmittedAnswers] %s", uqa.name, err.Error()))
+	if err := uqa.db.Raw(sqlStatement, packAttemptID).Scan(&attempts).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetPackAttemptAnswers] %s", uqa.name, err.Error()))
 		return attempts, err
 	}
 
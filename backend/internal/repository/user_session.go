@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRefreshTokenUsed is returned when the refresh token was already exchanged
+var ErrRefreshTokenUsed = errors.New("refresh token has been used")
+
+// sessionTouchInterval limits how often last seen of a session is written
+const sessionTouchInterval = time.Minute
+
+type userSessionRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type UserSessionRepository interface {
+	// Create a new session of user
+	Create(session entities.UserSession) (entities.UserSession, error)
+	// Get the active basic auth session of the device, create it when there is none
+	FirstOrCreateBasic(session entities.UserSession) (entities.UserSession, error)
+	// Get session by ID
+	Get(ID int) (entities.UserSession, error)
+	// List sessions of user which are not revoked and seen after since
+	ListActive(userID int, since time.Time) ([]entities.UserSession, error)
+	// Update last seen and IP address of session
+	Touch(ID int, ipAddress string) error
+	// Store refresh token of a session
+	CreateToken(token entities.RefreshToken) error
+	// Get refresh token with its session by the token hash
+	GetToken(tokenHash string) (entities.RefreshToken, error)
+	// Mark the refresh token as used and store the next token of the session, ErrRefreshTokenUsed when the token was already used
+	Rotate(used entities.RefreshToken, next entities.RefreshToken) error
+	// Revoke session and deny the access tokens issued for it
+	Revoke(sessionID int, reason string) error
+	// Revoke every session of user and deny the access tokens issued for them
+	RevokeAll(userID int, reason string) error
+	// Check whether the access token has been revoked
+	IsAccessTokenRevoked(jti string) (bool, error)
+}
+
+func NewUserSessionRepository(db *gorm.DB) UserSessionRepository {
+	return &userSessionRepo{
+		db:   db,
+		name: "User Session Repository",
+	}
+}
+
+func (u *userSessionRepo) Create(session entities.UserSession) (entities.UserSession, error) {
+	session.LastSeenAt = time.Now()
+	if err := u.db.Create(&session).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Create] %s", u.name, err.Error()))
+		return session, err
+	}
+
+	return session, nil
+}
+
+func (u *userSessionRepo) FirstOrCreateBasic(session entities.UserSession) (entities.UserSession, error) {
+	query := u.db.Where("user_id = ? AND user_agent = ? AND ip_address = ? AND login_method = ? AND revoked_at IS NULL",
+		session.UserID, session.UserAgent, session.IPAddress, entities.SessionLoginBasic)
+
+	var found entities.UserSession
+	err := query.Session(&gorm.Session{}).First(&found).Error
+	if err == nil {
+		return found, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.Error(fmt.Sprintf("[%s][FirstOrCreateBasic] %s", u.name, err.Error()))
+		return found, err
+	}
+
+	// Concurrent first requests of the device are deduplicated by the unique index
+	session.LoginMethod = entities.SessionLoginBasic
+	session.LastSeenAt = time.Now()
+	if err := u.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&session).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][FirstOrCreateBasic] %s", u.name, err.Error()))
+		return session, err
+	}
+	if session.ID != 0 {
+		return session, nil
+	}
+
+	if err := query.Session(&gorm.Session{}).First(&found).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][FirstOrCreateBasic] %s", u.name, err.Error()))
+		return found, err
+	}
+
+	return found, nil
+}
+
+func (u *userSessionRepo) Get(ID int) (entities.UserSession, error) {
+	var session entities.UserSession
+
+	if err := u.db.First(&session, ID).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get] %s", u.name, err.Error()))
+		return session, err
+	}
+
+	return session, nil
+}
+
+func (u *userSessionRepo) ListActive(userID int, since time.Time) ([]entities.UserSession, error) {
+	var sessions []entities.UserSession
+
+	err := u.db.Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, since).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][ListActive] %s", u.name, err.Error()))
+		return sessions, err
+	}
+
+	return sessions, nil
+}
+
+func (u *userSessionRepo) Touch(ID int, ipAddress string) error {
+	now := time.Now()
+
+	err := u.db.Model(&entities.UserSession{}).
+		Where("id = ? AND last_seen_at < ?", ID, now.Add(-sessionTouchInterval)).
+		Updates(map[string]interface{}{"last_seen_at": now, "ip_address": ipAddress}).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Touch] %s", u.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (u *userSessionRepo) CreateToken(token entities.RefreshToken) error {
+	if err := u.db.Create(&token).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][CreateToken] %s", u.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (u *userSessionRepo) GetToken(tokenHash string) (entities.RefreshToken, error) {
+	var token entities.RefreshToken
+
+	err := u.db.Preload("Session").Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetToken] %s", u.name, err.Error()))
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (u *userSessionRepo) Rotate(used entities.RefreshToken, next entities.RefreshToken) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		// Only one of concurrent exchanges of the same token can mark it as used
+		res := tx.Model(&entities.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", used.ID).
+			Update("used_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenUsed
+		}
+
+		return tx.Create(&next).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Rotate] %s", u.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (u *userSessionRepo) Revoke(sessionID int, reason string) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, "id = ?", sessionID, reason)
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Revoke] %s", u.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (u *userSessionRepo) RevokeAll(userID int, reason string) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, "user_id = ?", userID, reason)
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][RevokeAll] %s", u.name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (u *userSessionRepo) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+
+	err := u.db.Model(&entities.RevokedAccessToken{}).Where("jti = ?", jti).Count(&count).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][IsAccessTokenRevoked] %s", u.name, err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// revokeSessions revoke the sessions matched by the condition and put their unexpired access tokens into the denylist
+func revokeSessions(tx *gorm.DB, condition string, arg interface{}, reason string) error {
+	now := time.Now()
+
+	var sessionIDs []int
+	if err := tx.Model(&entities.UserSession{}).Where(condition, arg).Where("revoked_at IS NULL").Pluck("id", &sessionIDs).Error; err != nil {
+		return err
+	}
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	err := tx.Model(&entities.UserSession{}).Where("id IN ?", sessionIDs).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason, "updated_at": now}).Error
+	if err != nil {
+		return err
+	}
+
+	var tokens []entities.RefreshToken
+	if err := tx.Where("session_id IN ? AND access_expires_at > ?", sessionIDs, now).Find(&tokens).Error; err != nil {
+		return err
+	}
+
+	denied := make([]entities.RevokedAccessToken, 0, len(tokens))
+	for _, token := range tokens {
+		denied = append(denied, entities.RevokedAccessToken{Jti: token.AccessJti, ExpiresAt: token.AccessExpiresAt})
+	}
+	if len(denied) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&denied).Error; err != nil {
+			return err
+		}
+	}
+
+	// Expired access tokens are rejected anyway, keep the denylist small
+	return tx.Where("expires_at <= ?", now).Delete(&entities.RevokedAccessToken{}).Error
+}
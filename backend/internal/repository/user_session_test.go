@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities"
+)
+
+func TestRotateRefreshTokenConcurrently(t *testing.T) {
+	sessionRepo := NewUserSessionRepository(db)
+
+	user := entities.User{Name: "rotate test", Email: fmt.Sprintf("rotate-%d@test.local", time.Now().UnixNano())}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	defer db.Delete(&user)
+
+	session, err := sessionRepo.Create(entities.UserSession{UserID: user.ID, LoginMethod: entities.SessionLoginPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	token := entities.RefreshToken{SessionID: session.ID, TokenHash: fmt.Sprintf("rotate-%d", now.UnixNano()), ExpiresAt: now.Add(time.Hour), AccessJti: "jti-0", AccessExpiresAt: now.Add(time.Hour)}
+	if err := sessionRepo.CreateToken(token); err != nil {
+		t.Fatal(err)
+	}
+	token, err = sessionRepo.GetToken(token.TokenHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const clients = 8
+	errs := make(chan error, clients)
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			next := entities.RefreshToken{SessionID: session.ID, TokenHash: fmt.Sprintf("%s-%d", token.TokenHash, i), ExpiresAt: now.Add(time.Hour), AccessJti: fmt.Sprintf("jti-%d", i+1), AccessExpiresAt: now.Add(time.Hour)}
+			errs <- sessionRepo.Rotate(token, next)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrRefreshTokenUsed):
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+
+	if succeeded != 1 {
+		t.Errorf("expected exactly one rotation to succeed, got %d", succeeded)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userStreakRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type UserStreakRepository interface {
+	// Get streak of user, user without submitted answer has an empty streak
+	Get(userID int) (entities.UserStreak, error)
+	// Record submitted attempt into the streak of user, it returns the streak before and after
+	Record(userID, attemptID int, isCorrect, countsAnswer bool, day time.Time) (entities.UserStreak, entities.UserStreak, error)
+}
+
+func NewUserStreakRepository(db *gorm.DB) UserStreakRepository {
+	return &userStreakRepo{
+		db:   db,
+		name: "User Streak Repository",
+	}
+}
+
+func (u *userStreakRepo) Get(userID int) (entities.UserStreak, error) {
+	streak := entities.UserStreak{UserID: userID}
+
+	err := u.db.Where("user_id = ?", userID).First(&streak).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.Error(fmt.Sprintf("[%s][Get] %s", u.name, err.Error()))
+		return streak, err
+	}
+
+	return streak, nil
+}
+
+func (u *userStreakRepo) Record(userID, attemptID int, isCorrect, countsAnswer bool, day time.Time) (entities.UserStreak, entities.UserStreak, error) {
+	var before, after entities.UserStreak
+
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.UserStreak{UserID: userID}).Error; err != nil {
+			return err
+		}
+
+		// Concurrent submits of the user wait for each other here
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&before).Error; err != nil {
+			return err
+		}
+
+		after = before
+		if !after.Record(attemptID, isCorrect, countsAnswer, day) {
+			return nil
+		}
+
+		return tx.Save(&after).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Record] %s", u.name, err.Error()))
+		return before, after, err
+	}
+
+	return before, after, nil
+}
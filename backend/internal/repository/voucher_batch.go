@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/pagination/gorm_pagination"
+	"gorm.io/gorm"
+)
+
+var ErrVoucherBatchRevoked = errors.New("voucher batch has already been revoked")
+
+type voucherBatchRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+type VoucherBatchRepository interface {
+	// Create voucher batch together with its vouchers
+	Create(batch entities.VoucherBatch, vouchers []entities.PremiumPackage) (entities.VoucherBatch, error)
+	// Get voucher batch detail
+	Get(ID int) (entities.VoucherBatch, error)
+	// Get distinct alphabet and code length of voucher batches
+	Formats() ([]entities.VoucherBatch, error)
+	// Get list of voucher batch
+	List(param params.VoucherBatchFilterParam) ([]entities.VoucherBatch, int, error)
+	// Get all vouchers of a batch
+	GetVouchers(ID int) ([]entities.PremiumPackage, error)
+	// Count vouchers of a batch by their state
+	GetSummary(ID int) (params.VoucherBatchSummary, error)
+	// Revoke batch and its unredeemed vouchers, returns number of revoked vouchers
+	Revoke(ID int, now time.Time) (int, error)
+}
+
+func NewVoucherBatchRepository(db *gorm.DB) VoucherBatchRepository {
+	return &voucherBatchRepo{
+		db:   db,
+		name: "Voucher Batch Repository",
+	}
+}
+
+func (v *voucherBatchRepo) Create(batch entities.VoucherBatch, vouchers []entities.PremiumPackage) (entities.VoucherBatch, error) {
+	err := v.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+
+		for i := range vouchers {
+			vouchers[i].VoucherBatchID = &batch.ID
+		}
+
+		return tx.CreateInBatches(&vouchers, 500).Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Create] %s", v.name, err.Error()))
+		return batch, err
+	}
+
+	return batch, nil
+}
+
+func (v *voucherBatchRepo) Get(ID int) (entities.VoucherBatch, error) {
+	var batch entities.VoucherBatch
+
+	if err := v.db.First(&batch, ID).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get] %s", v.name, err.Error()))
+		return batch, err
+	}
+
+	return batch, nil
+}
+
+func (v *voucherBatchRepo) Formats() ([]entities.VoucherBatch, error) {
+	var formats []entities.VoucherBatch
+
+	if err := v.db.Model(&entities.VoucherBatch{}).Distinct("alphabet", "code_length").Find(&formats).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Formats] %s", v.name, err.Error()))
+		return formats, err
+	}
+
+	return formats, nil
+}
+
+func (v *voucherBatchRepo) List(param params.VoucherBatchFilterParam) ([]entities.VoucherBatch, int, error) {
+	var batches []entities.VoucherBatch
+	var count int64
+
+	db := v.db.Model(&entities.VoucherBatch{})
+
+	if param.Campaign != "" {
+		db = db.Where("campaign ILIKE ?", "%"+param.Campaign+"%")
+	}
+
+	if param.IsRevoked != nil {
+		db = db.Where("is_revoked = ?", *param.IsRevoked)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", v.name, err.Error()))
+		return batches, 0, err
+	}
+
+	if err := db.Scopes(gorm_pagination.Paginate(param.Page, param.Limit)).Order("created_at desc").Find(&batches).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", v.name, err.Error()))
+		return batches, 0, err
+	}
+
+	return batches, int(count), nil
+}
+
+func (v *voucherBatchRepo) GetVouchers(ID int) ([]entities.PremiumPackage, error) {
+	var vouchers []entities.PremiumPackage
+
+	if err := v.db.Where("voucher_batch_id = ?", ID).Order("id asc").Find(&vouchers).Error; err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get Vouchers] %s", v.name, err.Error()))
+		return vouchers, err
+	}
+
+	return vouchers, nil
+}
+
+func (v *voucherBatchRepo) GetSummary(ID int) (params.VoucherBatchSummary, error) {
+	var summary params.VoucherBatchSummary
+
+	err := v.db.Model(&entities.PremiumPackage{}).
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE user_id IS NOT NULL) AS redeemed,
+			COUNT(*) FILTER (WHERE user_id IS NULL AND revoked_at IS NOT NULL) AS revoked,
+			COUNT(*) FILTER (WHERE user_id IS NULL AND revoked_at IS NULL) AS available`).
+		Where("voucher_batch_id = ?", ID).
+		Scan(&summary).Error
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get Summary] %s", v.name, err.Error()))
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+func (v *voucherBatchRepo) Revoke(ID int, now time.Time) (int, error) {
+	var revoked int64
+
+	err := v.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.VoucherBatch{}).
+			Where("id = ? AND is_revoked = ?", ID, false).
+			Updates(map[string]interface{}{"is_revoked": true, "revoked_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrVoucherBatchRevoked
+		}
+
+		// Vouchers which are already redeemed stay with their user
+		result = tx.Model(&entities.PremiumPackage{}).
+			Where("voucher_batch_id = ? AND user_id IS NULL AND revoked_at IS NULL", ID).
+			Update("revoked_at", now)
+		revoked = result.RowsAffected
+
+		return result.Error
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Revoke] %s", v.name, err.Error()))
+		return 0, err
+	}
+
+	return int(revoked), nil
+}
@@ -20,18 +20,27 @@ func (rtr *router) AdminRouterV1() http.Handler {
 	router.Mount("/question-solution", rtr.questionSolutionRouterV1())
 	router.Mount("/analytic", rtr.analyticAdminRouterV1())
 	router.Mount("/question-pack", rtr.questionPackAdminRouterV1())
+	router.Mount("/premium-package", rtr.premiumPackageAdminRouterV1())
+	router.Mount("/voucher-batch", rtr.voucherBatchAdminRouterV1())
+	router.Mount("/question-review", rtr.questionReviewAdminRouterV1())
+	router.Mount("/point", rtr.pointAdminRouterV1())
+	router.Mount("/badge", rtr.badgeAdminRouterV1())
+	router.Mount("/policy", rtr.policyAdminRouterV1())
 
 	return router
 }
 
 func (rtr *router) userAdminRouterV1() http.Handler {
-	userHandler := handler.NewUserHandler(rtr.cfg.DB)
+	userHandler := handler.NewUserHandler(rtr.cfg.DB, rtr.cfg.Minio)
+	sessionHandler := handler.NewUserSessionHandler(rtr.cfg.DB)
 	router := chi.NewRouter()
 
 	router.Post("/", userHandler.Create)
 	router.Get("/", userHandler.List)
 	router.Get("/{id}", userHandler.Get)
 	router.Put("/{id}", userHandler.Update)
+	router.Get("/{id}/sessions", sessionHandler.AdminList)
+	router.Delete("/{id}/sessions/{sessionID}", sessionHandler.AdminRevoke)
 
 	return router
 }
@@ -53,13 +62,22 @@ func (rtr *router) roleAdminRouterV1() http.Handler {
 
 func (rtr *router) questionAdminRouterV1() http.Handler {
 	questionHandler := handler.NewQuestionHandler(rtr.cfg.DB, rtr.cfg.Minio)
+	revisionHandler := handler.NewQuestionRevisionHandler(rtr.cfg.DB)
 	router := chi.NewRouter()
 
 	router.Get("/", questionHandler.AdminGetList)
 	router.Post("/", questionHandler.Create)
+	router.Post("/import", questionHandler.Import)
+	router.Get("/export", questionHandler.Export)
+	router.Post("/import/bank", questionHandler.ImportBank)
 	router.Get("/{id}", questionHandler.AdminGetDetail)
 	router.Put("/{id}", questionHandler.Update)
 
+	router.Get("/{id}/revisions", revisionHandler.List)
+	router.Get("/{id}/revisions/diff", revisionHandler.Diff)
+	router.Get("/{id}/revisions/{revisionID}", revisionHandler.Detail)
+	router.Post("/{id}/revisions/{revisionID}/rollback", revisionHandler.Rollback)
+
 	router.Post("/option/", questionHandler.AdminAddOption)
 	router.Delete("/option/{id}", questionHandler.AdminDeleteOption)
 	router.Put("/option/{id}", questionHandler.AdminUpdateOption)
@@ -123,7 +141,7 @@ func (rtr *router) analyticAdminRouterV1() http.Handler {
 }
 
 func (rtr *router) questionPackAdminRouterV1() http.Handler {
-	questionPackHandler := handler.NewQuestionPackHandler(rtr.cfg.DB)
+	questionPackHandler := handler.NewQuestionPackHandler(rtr.cfg.DB, rtr.cfg.Minio)
 	router := chi.NewRouter()
 
 	router.Post("/", questionPackHandler.Create)
@@ -133,6 +151,83 @@ func (rtr *router) questionPackAdminRouterV1() http.Handler {
 	router.Delete("/{id}", questionPackHandler.Delete)
 	router.Post("/add-question", questionPackHandler.AddQuestion)
 	router.Post("/delete-question", questionPackHandler.DeleteQuestion)
+	router.Get("/attempt", questionPackHandler.AdminGetQuestionPackAttemptList)
+	router.Get("/attempt/{id}", questionPackHandler.AdminGetQuestionPackAttempt)
+
+	return router
+}
+
+func (rtr *router) premiumPackageAdminRouterV1() http.Handler {
+	premiumPackageHandler := handler.NewPremiumPackageHandler(rtr.cfg.DB)
+	router := chi.NewRouter()
+
+	router.Post("/", premiumPackageHandler.Create)
+	router.Get("/", premiumPackageHandler.List)
+	router.Get("/{id}", premiumPackageHandler.Detail)
+	router.Put("/{id}", premiumPackageHandler.Update)
+	router.Delete("/{id}", premiumPackageHandler.Delete)
+
+	return router
+}
+
+func (rtr *router) voucherBatchAdminRouterV1() http.Handler {
+	voucherBatchHandler := handler.NewVoucherBatchHandler(rtr.cfg.DB)
+	router := chi.NewRouter()
+
+	router.Post("/", voucherBatchHandler.Create)
+	router.Get("/", voucherBatchHandler.List)
+	router.Get("/{id}", voucherBatchHandler.Detail)
+	router.Get("/{id}/export", voucherBatchHandler.Export)
+	router.Post("/{id}/revoke", voucherBatchHandler.Revoke)
+
+	return router
+}
+
+func (rtr *router) questionReviewAdminRouterV1() http.Handler {
+	reviewHandler := handler.NewQuestionReviewHandler(rtr.cfg.DB)
+	router := chi.NewRouter()
+
+	router.Get("/", reviewHandler.Queue)
+	router.Get("/{id}", reviewHandler.Detail)
+	router.Post("/{id}/assign", reviewHandler.Assign)
+	router.Post("/{id}/approve", reviewHandler.Approve)
+	router.Post("/{id}/reject", reviewHandler.Reject)
+	router.Post("/{id}/comment", reviewHandler.Comment)
+
+	return router
+}
+
+func (rtr *router) pointAdminRouterV1() http.Handler {
+	pointHandler := handler.NewPointHandler(rtr.cfg.DB)
+	router := chi.NewRouter()
+
+	router.Get("/transactions", pointHandler.Transactions)
+	router.Post("/grant", pointHandler.Grant)
+	router.Post("/revoke", pointHandler.Revoke)
+
+	return router
+}
+
+func (rtr *router) badgeAdminRouterV1() http.Handler {
+	badgeHandler := handler.NewBadgeHandler(rtr.cfg.DB, rtr.cfg.Minio)
+	router := chi.NewRouter()
+
+	router.Post("/", badgeHandler.Create)
+	router.Get("/", badgeHandler.List)
+	router.Get("/{id}", badgeHandler.Detail)
+	router.Put("/{id}", badgeHandler.Update)
+	router.Delete("/{id}", badgeHandler.Delete)
+
+	return router
+}
+
+func (rtr *router) policyAdminRouterV1() http.Handler {
+	policyHandler := handler.NewPolicyHandler(rtr.cfg.DB, rtr.cfg.Enforcer)
+	router := chi.NewRouter()
+
+	router.Get("/", policyHandler.List)
+	router.Post("/", policyHandler.Add)
+	router.Delete("/", policyHandler.Remove)
 
 	return router
 }
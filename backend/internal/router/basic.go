@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"gitlab.com/project-quiz/internal/handler"
+	m "gitlab.com/project-quiz/internal/middleware"
 )
 
 func (rtr *router) BasicRouterV1() http.Handler {
@@ -15,17 +16,23 @@ func (rtr *router) BasicRouterV1() http.Handler {
 	router.Mount("/analytic", rtr.basicAnaylticRouterV1())
 	router.Mount("/question-pack", rtr.questionPackBasicRouterV1())
 	router.Mount("/product", rtr.productBasicRouterV1())
+	router.Mount("/premium", rtr.premiumBasicRouterV1())
 
 	return router
 }
 
 func (rtr *router) basicAuthRouterV1() http.Handler {
 	router := chi.NewRouter()
-	authHandler := handler.NewAuthHandler(rtr.cfg.DB, &rtr.cfg.SMTP, rtr.cfg.Secret, rtr.cfg.GoogleClientID)
+	authHandler := handler.NewAuthHandler(rtr.cfg.DB, &rtr.cfg.SMTP, rtr.cfg.Minio, rtr.cfg.Secret, rtr.cfg.GoogleClientID)
+	sessionHandler := handler.NewUserSessionHandler(rtr.cfg.DB)
 
 	router.Get("/me", authHandler.GetAuthenticatedUser)
 	router.Post("/update-password", authHandler.UpdatePassword)
 	router.Post("/update-account", authHandler.UpdateAccount)
+	router.Post("/logout", authHandler.Logout)
+	router.Post("/logout-all", authHandler.LogoutAll)
+	router.Get("/sessions", sessionHandler.List)
+	router.Delete("/sessions/{id}", sessionHandler.Revoke)
 
 	return router
 }
@@ -33,7 +40,7 @@ func (rtr *router) basicAuthRouterV1() http.Handler {
 func (rtr *router) basicQuestionRouterV1() http.Handler {
 	router := chi.NewRouter()
 	questionHandler := handler.NewQuestionHandler(rtr.cfg.DB, rtr.cfg.Minio)
-	attemptHandler := handler.NewUserQuestionAttemptHandler(rtr.cfg.DB)
+	attemptHandler := handler.NewUserQuestionAttemptHandler(rtr.cfg.DB, rtr.cfg.Minio, rtr.cfg.StreakMilestones)
 
 	router.Get("/", questionHandler.GetList)
 	router.Get("/{id}", questionHandler.GetDetail)
@@ -43,7 +50,7 @@ func (rtr *router) basicQuestionRouterV1() http.Handler {
 	router.Post("/submit-answer", attemptHandler.SubmitAnswer)
 	router.Get("/answer/{id}", attemptHandler.GetLatestAnswer)
 	router.Put("/add-remove-mark", questionHandler.AddRemoveMark)
-	router.Get("/solution/{id}", questionHandler.GetSolution)
+	router.With(m.PremiumSolution(rtr.cfg.DB)).Get("/solution/{id}", questionHandler.GetSolution)
 
 	return router
 }
@@ -51,23 +58,27 @@ func (rtr *router) basicQuestionRouterV1() http.Handler {
 func (rtr *router) basicAnaylticRouterV1() http.Handler {
 	router := chi.NewRouter()
 	analyticHandler := handler.NewAnalyticHandler(rtr.cfg.DB, rtr.cfg.Minio)
+	pointHandler := handler.NewPointHandler(rtr.cfg.DB)
 
 	router.Get("/attempt", analyticHandler.GetAttemptAnalytic)
 	router.Get("/point", analyticHandler.GetUserPoint)
+	router.Get("/point/history", pointHandler.History)
 	router.Get("/point-list", analyticHandler.GetUserPointList)
+	router.Get("/leaderboard", analyticHandler.GetLeaderboard)
 
 	return router
 }
 
 func (rtr *router) questionPackBasicRouterV1() http.Handler {
-	questionPackHandler := handler.NewQuestionPackHandler(rtr.cfg.DB)
+	questionPackHandler := handler.NewQuestionPackHandler(rtr.cfg.DB, rtr.cfg.Minio)
 	router := chi.NewRouter()
 
 	router.Get("/", questionPackHandler.GetList)
-	router.Get("/{id}", questionPackHandler.GetDetail)
-	router.Post("/take", questionPackHandler.BasicTakeQuestionPack)
+	router.With(m.PremiumQuestionPack(rtr.cfg.DB)).Get("/{id}", questionPackHandler.BasicGetDetail)
+	router.With(m.PremiumQuestionPack(rtr.cfg.DB)).Post("/take", questionPackHandler.BasicTakeQuestionPack)
 	router.Post("/finish", questionPackHandler.BasicFinishQuestionPack)
 	router.Get("/attempt", questionPackHandler.BasicGetQuestionPackAttemptList)
+	router.Get("/attempt/{id}", questionPackHandler.BasicGetQuestionPackAttemptDetail)
 
 	return router
 }
@@ -84,3 +95,13 @@ func (rtr *router) productBasicRouterV1() http.Handler {
 
 	return router
 }
+
+func (rtr *router) premiumBasicRouterV1() http.Handler {
+	premiumPackageHandler := handler.NewPremiumPackageHandler(rtr.cfg.DB)
+	router := chi.NewRouter()
+
+	router.Get("/", premiumPackageHandler.GetActive)
+	router.Post("/redeem", premiumPackageHandler.Redeem)
+
+	return router
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"gitlab.com/project-quiz/internal/handler"
+	m "gitlab.com/project-quiz/internal/middleware"
 )
 
 func (rtr *router) ContributorRouterV1() http.Handler {
@@ -19,19 +20,27 @@ func (rtr *router) ContributorRouterV1() http.Handler {
 
 func (rtr *router) questionContributorRouterV1() http.Handler {
 	question := handler.NewQuestionHandler(rtr.cfg.DB, rtr.cfg.Minio)
+	review := handler.NewQuestionReviewHandler(rtr.cfg.DB)
+	questionOwner := m.QuestionOwner(rtr.cfg.DB)
+	optionOwner := m.QuestionOptionOwner(rtr.cfg.DB)
 	router := chi.NewRouter()
 
 	router.Get("/", question.GetListByContributor)
 	router.Post("/", question.CreateByContributor)
-	router.Put("/{id}", question.UpdateByContributor)
+	router.Post("/import", question.ImportByContributor)
+	router.With(questionOwner).Put("/{id}", question.UpdateByContributor)
 	router.Get("/{id}", question.GetDetailByContributor)
+	router.With(questionOwner).Post("/{id}/submit", review.Submit)
+	router.With(questionOwner).Post("/{id}/withdraw", review.Withdraw)
+	router.Get("/{id}/review", review.DetailByContributor)
 
-	router.Post("/option", question.AdminAddOption)
-	router.Delete("/option/{id}", question.AdminDeleteOption)
-	router.Put("/option/{id}", question.AdminUpdateOption)
+	// Options and tags can only be changed while the question is draft or rejected
+	router.With(optionOwner).Post("/option", question.AddOptionByContributor)
+	router.With(optionOwner).Delete("/option/{id}", question.DeleteOptionByContributor)
+	router.With(optionOwner).Put("/option/{id}", question.UpdateOptionByContributor)
 
-	router.Post("/tags", question.AddTags)
-	router.Post("/tags/remove", question.RemoveTag)
+	router.With(questionOwner).Post("/tags", question.AddTagsByContributor)
+	router.With(questionOwner).Post("/tags/remove", question.RemoveTagByContributor)
 
 	return router
 }
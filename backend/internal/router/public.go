@@ -17,7 +17,7 @@ func (rtr *router) PublicRouterV1() http.Handler {
 }
 
 func (rtr *router) publicAuthRouterV1() http.Handler {
-	authHandler := handler.NewAuthHandler(rtr.cfg.DB, &rtr.cfg.SMTP, rtr.cfg.Secret, rtr.cfg.GoogleClientID)
+	authHandler := handler.NewAuthHandler(rtr.cfg.DB, &rtr.cfg.SMTP, rtr.cfg.Minio, rtr.cfg.Secret, rtr.cfg.GoogleClientID)
 	router := chi.NewRouter()
 
 	router.Post("/registration", authHandler.Register)
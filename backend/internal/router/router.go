@@ -6,7 +6,9 @@ import (
 
 	sentryhttp "github.com/getsentry/sentry-go/http"
 	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
 	m "gitlab.com/project-quiz/internal/middleware"
+	"gitlab.com/project-quiz/internal/rbac"
 	mail "gitlab.com/project-quiz/utils/mailer"
 	"gitlab.com/project-quiz/utils/minio"
 
@@ -27,6 +29,10 @@ type RouterCfg struct {
 	Secret         string
 	AesSecret      string
 	GoogleClientID string
+	// Bonus points of answer and daily streak milestones
+	StreakMilestones entities.StreakMilestones
+	// Casbin enforcers shared by every request
+	Enforcer *rbac.Enforcer
 }
 
 func NewRouter(r *RouterCfg) Router {
@@ -40,7 +46,7 @@ func (rtr *router) Route() http.Handler {
 	rtr.router.Use(m.Cors(rtr.cfg.DB))
 	rtr.router.Use(m.Logger)
 	rtr.router.Use(m.Recovery)
-	rtr.router.Use(m.Authorization(rtr.cfg.DB))
+	rtr.router.Use(m.Authorization(rtr.cfg.DB, rtr.cfg.Enforcer))
 	rtr.router.Use(m.Pagination)
 
 	// Sentry
@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/rbac"
 	"gitlab.com/project-quiz/internal/router"
 	mail "gitlab.com/project-quiz/utils/mailer"
 	"gitlab.com/project-quiz/utils/minio"
@@ -22,17 +24,23 @@ type HttpServerCfg struct {
 	Secret         string
 	AesSecret      string
 	GoogleClientID string
+	// Bonus points of answer and daily streak milestones
+	StreakMilestones entities.StreakMilestones
+	// Casbin enforcers shared by every request
+	Enforcer *rbac.Enforcer
 }
 
 func NewServer(h *HttpServerCfg) Server {
 	return &httpServer{
 		router: router.NewRouter(&router.RouterCfg{
-			DB:             h.DB,
-			SMTP:           h.SMTP,
-			Minio:          h.Minio,
-			Secret:         h.Secret,
-			AesSecret:      h.AesSecret,
-			GoogleClientID: h.GoogleClientID,
+			DB:               h.DB,
+			SMTP:             h.SMTP,
+			Minio:            h.Minio,
+			Secret:           h.Secret,
+			AesSecret:        h.AesSecret,
+			GoogleClientID:   h.GoogleClientID,
+			StreakMilestones: h.StreakMilestones,
+			Enforcer:         h.Enforcer,
 		}).Route(),
 	}
 }
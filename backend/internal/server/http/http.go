@@ -34,7 +34,7 @@ func (h *httpServer) Run(ctx context.Context, port int) {
 	}()
 
 	if err := server.Shutdown(ctxShutDown); err != nil {
-		log.Fatal("server shutdown failed: %v", err)
+		log.Fatalf("server shutdown failed: %v", err)
 	}
 
 	log.Info("server existed properly")
@@ -14,6 +14,7 @@ import (
 	"gitlab.com/project-quiz/utils/mailer"
 	"gitlab.com/project-quiz/utils/oauth"
 	"gitlab.com/project-quiz/utils/password"
+	"gitlab.com/project-quiz/utils/random"
 	"gitlab.com/project-quiz/utils/template"
 	"gorm.io/gorm"
 
@@ -24,6 +25,7 @@ import (
 type auth struct {
 	userRepo       repository.UserRepository
 	tokenRepo      repository.TokenRepository
+	sessionRepo    repository.UserSessionRepository
 	name           string
 	smtp           *mailer.Mailer
 	googleClientID string
@@ -45,13 +47,18 @@ type AuthUsecase interface {
 	// Validate email
 	ValidateEmail(param params.AuthValidateEmailParams) appctx.Response
 	// Authenticate google JWT
-	AuthenticateGoogleJWT(token string) appctx.Response
+	AuthenticateGoogleJWT(param params.AuthLoginGoogleParam) appctx.Response
+	// Revoke the session of the access token
+	Logout(principal appctx.Principal) appctx.Response
+	// Revoke every session of the user
+	LogoutAll(principal appctx.Principal) appctx.Response
 }
 
 func NewAuthUsecase(db *gorm.DB, smtp *mailer.Mailer, secret string, googleClientID string) AuthUsecase {
 	return &auth{
 		userRepo:       repository.NewUserRepository(db),
 		tokenRepo:      repository.NewTokenRepository(db, secret),
+		sessionRepo:    repository.NewUserSessionRepository(db),
 		name:           "Auth Usecase",
 		smtp:           smtp,
 		googleClientID: googleClientID,
@@ -121,49 +128,148 @@ func (a *auth) Login(param params.AuthLoginParam) appctx.Response {
 	}
 
 	// Generate JWT Token
-	access, err := jwt.GenerateToken("access", int(user.ID))
+	token, err := a.startSession(user.ID, entities.SessionLoginPassword, param.Client)
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Login] %s", a.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error())
 	}
 
-	refresh, err := jwt.GenerateToken("refresh", int(user.ID))
-	if err != nil {
-		log.Error(fmt.Sprintf("[%s][Login] %s", a.name, err.Error()))
-		return *appctx.NewResponse().WithErrors(err.Error())
-	}
-
-	t := time.Now().UTC().Add(time.Hour*time.Duration(4) - time.Minute*time.Duration(5))
 	data := map[string]interface{}{
-		"token": map[string]interface{}{
-			"access":  access,
-			"refresh": refresh,
-			"timeout": t,
-		},
-		"user": user,
+		"token": token,
+		"user":  user,
 	}
 
 	return *appctx.NewResponse().WithData(data)
 }
 
 func (a *auth) Refresh(param params.AuthRefreshTokenParam) appctx.Response {
-	// Parse Token
-	ss, err := jwt.RefreshToken(param.Refresh)
+	token, err := a.sessionRepo.GetToken(random.HashToken(param.Refresh))
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Refresh] %s", a.name, err.Error()))
-		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(401)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return *appctx.NewResponse().WithErrors("refresh token is invalid").WithCode(401)
+		}
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	if token.Session == nil || token.Session.IsRevoked() {
+		return *appctx.NewResponse().WithErrors("session has been revoked").WithCode(401)
+	}
+
+	if token.UsedAt != nil {
+		return a.revokeReusedSession(token)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return *appctx.NewResponse().WithErrors("refresh token has expired").WithCode(401)
+	}
+
+	data, next, err := a.issueTokens(*token.Session)
+	if err != nil {
+		log.Error(fmt.Sprintf("[%s][Refresh] %s", a.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	if err := a.sessionRepo.Rotate(token, next); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenUsed) {
+			return a.revokeReusedSession(token)
+		}
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	if err := a.sessionRepo.Touch(token.SessionID, param.Client.IPAddress); err != nil {
+		log.Error(fmt.Sprintf("[%s][Refresh] %s", a.name, err.Error()))
+	}
+
+	return *appctx.NewResponse().WithData(map[string]interface{}{"token": data})
+}
+
+func (a *auth) Logout(principal appctx.Principal) appctx.Response {
+	if principal.SessionID == 0 {
+		return *appctx.NewResponse().WithErrors("logout requires a bearer token").WithCode(400)
+	}
+
+	if err := a.sessionRepo.Revoke(principal.SessionID, entities.SessionRevokedLogout); err != nil {
+		log.Error(fmt.Sprintf("[%s][Logout] %s", a.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	return *appctx.NewResponse().WithMessage("Logged out")
+}
+
+func (a *auth) LogoutAll(principal appctx.Principal) appctx.Response {
+	if err := a.sessionRepo.RevokeAll(principal.UserID, entities.SessionRevokedLogoutAll); err != nil {
+		log.Error(fmt.Sprintf("[%s][Logout All] %s", a.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	return *appctx.NewResponse().WithMessage("Logged out from all devices")
+}
+
+// startSession create a new session of the user on the device and issue its first tokens
+func (a *auth) startSession(userID int, loginMethod string, client params.SessionClient) (map[string]interface{}, error) {
+	session, err := a.sessionRepo.Create(entities.UserSession{
+		UserID:      userID,
+		LoginMethod: loginMethod,
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, refresh, err := a.issueTokens(session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.sessionRepo.CreateToken(refresh); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// issueTokens sign a new access token and generate a new refresh token of the session.
+// The refresh token record is returned to be stored by the caller.
+func (a *auth) issueTokens(session entities.UserSession) (map[string]interface{}, entities.RefreshToken, error) {
+	access, claims, err := jwt.GenerateAccessToken(session.UserID, session.ID)
+	if err != nil {
+		return nil, entities.RefreshToken{}, err
+	}
+
+	refresh, err := random.GenerateToken(32)
+	if err != nil {
+		return nil, entities.RefreshToken{}, err
+	}
+
+	token := entities.RefreshToken{
+		SessionID:       session.ID,
+		TokenHash:       random.HashToken(refresh),
+		ExpiresAt:       time.Now().Add(jwt.RefreshTokenDuration),
+		AccessJti:       claims.ID,
+		AccessExpiresAt: claims.ExpiresAt.Time,
 	}
 
-	t := time.Now().UTC().Add(time.Hour*time.Duration(4) - time.Minute*time.Duration(5))
 	data := map[string]interface{}{
-		"token": map[string]interface{}{
-			"access":  ss,
-			"refresh": param.Refresh,
-			"timeout": t,
-		},
+		"access":  access,
+		"refresh": refresh,
+		"timeout": claims.ExpiresAt.Time.UTC().Add(-5 * time.Minute),
 	}
 
-	return *appctx.NewResponse().WithData(data)
+	return data, token, nil
+}
+
+// revokeReusedSession revoke the whole session when a used refresh token is presented again,
+// either the legitimate client or an attacker holds a stolen token
+func (a *auth) revokeReusedSession(token entities.RefreshToken) appctx.Response {
+	log.Warn(fmt.Sprintf("[%s][Refresh] refresh token of session %d is reused, revoking the session", a.name, token.SessionID))
+
+	if err := a.sessionRepo.Revoke(token.SessionID, entities.SessionRevokedReuse); err != nil {
+		log.Error(fmt.Sprintf("[%s][Refresh] %s", a.name, err.Error()))
+	}
+
+	return *appctx.NewResponse().WithErrors("refresh token has been used").WithCode(401)
 }
 
 func (a *auth) RequestResetPassword(param params.AuthRequestResetPasswordParams) appctx.Response {
@@ -319,8 +425,8 @@ func (a *auth) ValidateEmail(param params.AuthValidateEmailParams) appctx.Respon
 	return *appctx.NewResponse().WithMessage("Verification done successfully").WithCode(200)
 }
 
-func (a *auth) AuthenticateGoogleJWT(token string) appctx.Response {
-	claims, err := oauth.ValidateGoogleJWT(token, a.googleClientID)
+func (a *auth) AuthenticateGoogleJWT(param params.AuthLoginGoogleParam) appctx.Response {
+	claims, err := oauth.ValidateGoogleJWT(param.Token, a.googleClientID)
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Authenticate Google JWT] %s", a.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(401)
@@ -355,26 +461,15 @@ func (a *auth) AuthenticateGoogleJWT(token string) appctx.Response {
 	}
 
 	// Generate JWT Token
-	access, err := jwt.GenerateToken("access", int(user.ID))
-	if err != nil {
-		log.Error(fmt.Sprintf("[%s][Authenticate Google JWT] %s", a.name, err.Error()))
-		return *appctx.NewResponse().WithErrors(err.Error())
-	}
-
-	refresh, err := jwt.GenerateToken("refresh", int(user.ID))
+	tokens, err := a.startSession(user.ID, entities.SessionLoginGoogle, param.Client)
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Authenticate Google JWT] %s", a.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error())
 	}
 
-	t := time.Now().UTC().Add(time.Hour*time.Duration(4) - time.Minute*time.Duration(5))
 	data := map[string]interface{}{
-		"token": map[string]interface{}{
-			"access":  access,
-			"refresh": refresh,
-			"timeout": t,
-		},
-		"user": user,
+		"token": tokens,
+		"user":  user,
 	}
 
 	return *appctx.NewResponse().WithData(data)
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/jwt"
+	"gorm.io/gorm"
+)
+
+// fakeSessionRepo mirrors the guarantees of the session repository in memory,
+// Rotate marks a token as used only once like the conditional update does
+type fakeSessionRepo struct {
+	repository.UserSessionRepository
+	mu       sync.Mutex
+	sessions map[int]*entities.UserSession
+	tokens   []entities.RefreshToken
+	denied   map[string]bool
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{sessions: map[int]*entities.UserSession{}, denied: map[string]bool{}}
+}
+
+func (f *fakeSessionRepo) Create(session entities.UserSession) (entities.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session.ID = len(f.sessions) + 1
+	f.sessions[session.ID] = &session
+	return session, nil
+}
+
+func (f *fakeSessionRepo) CreateToken(token entities.RefreshToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token.ID = len(f.tokens) + 1
+	f.tokens = append(f.tokens, token)
+	return nil
+}
+
+func (f *fakeSessionRepo) GetToken(tokenHash string) (entities.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, token := range f.tokens {
+		if token.TokenHash == tokenHash {
+			session := *f.sessions[token.SessionID]
+			token.Session = &session
+			return token, nil
+		}
+	}
+	return entities.RefreshToken{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSessionRepo) Rotate(used entities.RefreshToken, next entities.RefreshToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored := &f.tokens[used.ID-1]
+	if stored.UsedAt != nil {
+		return repository.ErrRefreshTokenUsed
+	}
+	now := time.Now()
+	stored.UsedAt = &now
+
+	next.ID = len(f.tokens) + 1
+	f.tokens = append(f.tokens, next)
+	return nil
+}
+
+func (f *fakeSessionRepo) Revoke(sessionID int, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	session := f.sessions[sessionID]
+	if session.RevokedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	session.RevokedReason = reason
+
+	for _, token := range f.tokens {
+		if token.SessionID == sessionID && token.AccessExpiresAt.After(now) {
+			f.denied[token.AccessJti] = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessionRepo) IsAccessTokenRevoked(jti string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.denied[jti], nil
+}
+
+func (f *fakeSessionRepo) Touch(ID int, ipAddress string) error {
+	return nil
+}
+
+func (f *fakeSessionRepo) session(ID int) entities.UserSession {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return *f.sessions[ID]
+}
+
+func newAuthWithFakes() (*auth, *fakeSessionRepo) {
+	sessions := newFakeSessionRepo()
+	return &auth{sessionRepo: sessions, name: "Auth Usecase"}, sessions
+}
+
+func startTestSession(t *testing.T, a *auth) (map[string]interface{}, *jwt.JWTClaims) {
+	t.Helper()
+
+	token, err := a.startSession(7, entities.SessionLoginPassword, params.SessionClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jwt.ParseAccessToken(token["access"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token, claims
+}
+
+func refreshTokenOf(t *testing.T, resp appctx.Response) string {
+	t.Helper()
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected refresh to succeed, got %d %v", resp.Code, resp.Errors)
+	}
+
+	return resp.Data.(map[string]interface{})["token"].(map[string]interface{})["refresh"].(string)
+}
+
+func TestRefreshReplayRevokesSession(t *testing.T) {
+	a, sessions := newAuthWithFakes()
+	token, claims := startTestSession(t, a)
+	first := token["refresh"].(string)
+
+	second := refreshTokenOf(t, a.Refresh(params.AuthRefreshTokenParam{Refresh: first}))
+
+	if resp := a.Refresh(params.AuthRefreshTokenParam{Refresh: first}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected replayed refresh token to be rejected, got %d", resp.Code)
+	}
+
+	session := sessions.session(claims.SessionID)
+	if !session.IsRevoked() || session.RevokedReason != entities.SessionRevokedReuse {
+		t.Errorf("expected session to be revoked for reuse, got %+v", session)
+	}
+
+	if resp := a.Refresh(params.AuthRefreshTokenParam{Refresh: second}); resp.Code != http.StatusUnauthorized {
+		t.Errorf("expected refresh token rotated before the replay to be rejected, got %d", resp.Code)
+	}
+
+	if revoked, _ := sessions.IsAccessTokenRevoked(claims.ID); !revoked {
+		t.Errorf("expected access token of the revoked session to be denied")
+	}
+}
+
+func TestConcurrentRefreshOnlyOneSucceeds(t *testing.T) {
+	a, _ := newAuthWithFakes()
+	token, _ := startTestSession(t, a)
+	refresh := token["refresh"].(string)
+
+	const clients = 8
+	codes := make(chan int, clients)
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- a.Refresh(params.AuthRefreshTokenParam{Refresh: refresh}).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	succeeded := 0
+	for code := range codes {
+		if code == http.StatusOK {
+			succeeded++
+		}
+	}
+
+	if succeeded != 1 {
+		t.Errorf("expected exactly one refresh to succeed, got %d", succeeded)
+	}
+}
+
+func TestLogoutDeniesAccessToken(t *testing.T) {
+	a, sessions := newAuthWithFakes()
+	_, claims := startTestSession(t, a)
+
+	resp := a.Logout(appctx.Principal{UserID: 7, SessionID: claims.SessionID, TokenID: claims.ID})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected logout to succeed, got %d %v", resp.Code, resp.Errors)
+	}
+
+	if revoked, _ := sessions.IsAccessTokenRevoked(claims.ID); !revoked {
+		t.Errorf("expected jti %s to be in the denylist after logout", claims.ID)
+	}
+}
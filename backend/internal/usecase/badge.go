@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/minio"
+	"gorm.io/gorm"
+)
+
+const badgeIconPath = "/badges"
+
+type badge struct {
+	badgeRepo repository.BadgeRepository
+	minio     minio.MinioStorageContract
+	name      string
+}
+
+type BadgeUsecase interface {
+	// Create badge, uploading its icon when given
+	Create(param params.BadgeCreateParam) appctx.Response
+	// Update badge, a new icon replaces the old one
+	Update(param params.BadgeUpdateParam) appctx.Response
+	// List and filter badges
+	List(param params.BadgeFilter) appctx.Response
+	// Get detail of badge
+	Detail(ID int) appctx.Response
+	// Delete badge, it is taken back from every user
+	Delete(ID int) appctx.Response
+}
+
+func NewBadgeUsecase(db *gorm.DB, minio minio.MinioStorageContract) BadgeUsecase {
+	return &badge{
+		badgeRepo: repository.NewBadgeRepository(db),
+		minio:     minio,
+		name:      "Badge Usecase",
+	}
+}
+
+func (b *badge) Create(param params.BadgeCreateParam) appctx.Response {
+	data := entities.Badge{}
+	b.copyParam(&data, param)
+
+	if param.Icon != nil {
+		path, err := b.uploadIcon(param)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Create] %s", b.name, err.Error()))
+			return *appctx.NewResponse().WithErrors(err.Error()).WithCode(400)
+		}
+		data.IconUrl = path
+	}
+
+	data, err := b.badgeRepo.Create(data)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(400)
+	}
+
+	return *appctx.NewResponse().WithData(withBadgeIcon(b.minio, data))
+}
+
+func (b *badge) Update(param params.BadgeUpdateParam) appctx.Response {
+	data, err := b.badgeRepo.Get(param.ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	b.copyParam(&data, param.BadgeCreateParam)
+
+	oldIcon := ""
+	if param.Icon != nil {
+		path, err := b.uploadIcon(param.BadgeCreateParam)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Update] %s", b.name, err.Error()))
+			return *appctx.NewResponse().WithErrors(err.Error()).WithCode(400)
+		}
+		oldIcon, data.IconUrl = data.IconUrl, path
+	}
+
+	data, err = b.badgeRepo.Update(data)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(400)
+	}
+
+	// The badge already points to the new icon, a leftover old icon is only logged
+	if oldIcon != "" {
+		if err := b.minio.DeleteFile(oldIcon); err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Update] %s", b.name, err.Error()))
+		}
+	}
+
+	return *appctx.NewResponse().WithData(withBadgeIcon(b.minio, data))
+}
+
+func (b *badge) List(param params.BadgeFilter) appctx.Response {
+	badges, count, err := b.badgeRepo.List(param)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	for i := range badges {
+		badges[i] = withBadgeIcon(b.minio, badges[i])
+	}
+
+	return *appctx.NewResponse().WithData(badges).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
+}
+
+func (b *badge) Detail(ID int) appctx.Response {
+	data, err := b.badgeRepo.Get(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(withBadgeIcon(b.minio, data))
+}
+
+func (b *badge) Delete(ID int) appctx.Response {
+	data, err := b.badgeRepo.Get(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if err := b.badgeRepo.Delete(ID); err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if data.IconUrl != "" {
+		if err := b.minio.DeleteFile(data.IconUrl); err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Delete] %s", b.name, err.Error()))
+		}
+	}
+
+	return *appctx.NewResponse().WithData(data)
+}
+
+func (b *badge) copyParam(data *entities.Badge, param params.BadgeCreateParam) {
+	data.Name = param.Name
+	data.Description = param.Description
+	data.RuleType = param.RuleType
+	data.Threshold = param.Threshold
+	data.MaterialID = param.MaterialID
+	data.MinScore = param.MinScore
+	data.IsActive = param.IsActive
+}
+
+func (b *badge) uploadIcon(param params.BadgeCreateParam) (string, error) {
+	// Buffered, the upload sends the path before the error when it fails
+	path := make(chan string, 1)
+	e := make(chan error, 1)
+	go b.minio.UploadMultipart(path, e, param.Icon, badgeIconPath)
+
+	if err := <-e; err != nil {
+		return "", err
+	}
+
+	return <-path, nil
+}
+
+// withBadgeIcon replaces the stored icon path with a temporary public url
+func withBadgeIcon(m minio.MinioStorageContract, data entities.Badge) entities.Badge {
+	if data.IconUrl == "" || m == nil {
+		return data
+	}
+
+	iconUrl, err := m.GetTemporaryPublicUrl(data.IconUrl)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[Badge Icon] %s", err.Error()))
+		return data
+	}
+
+	data.IconUrl = iconUrl.String()
+	return data
+}
+
+// badgeAwarder evaluates badge rules after an event and awards the badges whose threshold is reached
+type badgeAwarder struct {
+	badgeRepo repository.BadgeRepository
+	minio     minio.MinioStorageContract
+	name      string
+}
+
+func newBadgeAwarder(db *gorm.DB, minio minio.MinioStorageContract) badgeAwarder {
+	return badgeAwarder{
+		badgeRepo: repository.NewBadgeRepository(db),
+		minio:     minio,
+		name:      "Badge Awarder",
+	}
+}
+
+// award returns the badges newly awarded to the user. The event is already stored,
+// so failures are only logged and the badge is evaluated again on the next event.
+func (b badgeAwarder) award(userID int, event string) []entities.Badge {
+	badges, err := b.badgeRepo.ListPending(userID, entities.BadgeEventRules[event])
+	if err != nil {
+		return nil
+	}
+
+	awarded := []entities.Badge{}
+	now := time.Now()
+	for _, badge := range badges {
+		progress, err := b.badgeRepo.Progress(badge, userID)
+		if err != nil || progress < badge.Threshold {
+			continue
+		}
+
+		ok, err := b.badgeRepo.Award(userID, badge.ID, now)
+		if err != nil {
+			continue
+		}
+		if ok {
+			logrus.Info(fmt.Sprintf("[%s] badge %d awarded to user %d", b.name, badge.ID, userID))
+			awarded = append(awarded, withBadgeIcon(b.minio, badge))
+		}
+	}
+
+	return awarded
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrLeaderboardScopeID = errors.New("scope_id is required for material and question_pack leaderboard")
+
+type leaderboard struct {
+	leaderboardRepo repository.LeaderboardRepository
+	name            string
+}
+
+type LeaderboardUsecase interface {
+	// Get page of a leaderboard with the rank of the caller
+	Get(param params.LeaderboardParam) appctx.Response
+	// Refresh snapshots of every leaderboard
+	Refresh(now time.Time) error
+}
+
+func NewLeaderboardUsecase(db *gorm.DB) LeaderboardUsecase {
+	return &leaderboard{
+		leaderboardRepo: repository.NewLeaderboardRepository(db),
+		name:            "Leaderboard Usecase",
+	}
+}
+
+func (l *leaderboard) Get(param params.LeaderboardParam) appctx.Response {
+	if param.Scope == "" {
+		param.Scope = entities.LeaderboardScopeGlobal
+	}
+	if param.Period == "" {
+		param.Period = entities.LeaderboardPeriodAllTime
+	}
+
+	if param.Scope == entities.LeaderboardScopeGlobal {
+		param.ScopeID = 0
+	} else if param.ScopeID == 0 {
+		return *appctx.NewResponse().WithErrors(ErrLeaderboardScopeID.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	entries, count, err := l.leaderboardRepo.List(param)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	response := params.LeaderboardResponse{Entries: entries}
+
+	me, err := l.leaderboardRepo.GetUserEntry(param.Scope, param.ScopeID, param.Period, param.UserID)
+	if err == nil {
+		response.Me = &me
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.Error(fmt.Sprintf("[%s][Get] %s", l.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(response).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
+}
+
+func (l *leaderboard) Refresh(now time.Time) error {
+	for _, period := range entities.LeaderboardPeriods {
+		if err := l.leaderboardRepo.Refresh(period, entities.LeaderboardPeriodStart(period, now), now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
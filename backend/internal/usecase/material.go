@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/appctx"
@@ -40,6 +41,7 @@ func (m *material) Create(param params.MaterialCreateParam) appctx.Response {
 	var material entities.Material
 	material.Name = param.Name
 	material.Level = param.Level
+	material.CodePrefix = strings.ToLower(param.CodePrefix)
 
 	material, err := m.materialRepo.Create(material)
 	if err != nil {
@@ -60,6 +62,8 @@ func (m *material) Update(param params.MaterialEditParam) appctx.Response {
 
 	material.Name = param.Name
 	material.Level = param.Level
+	// Codes of existing questions keep their prefix until they are resequenced
+	material.CodePrefix = strings.ToLower(param.CodePrefix)
 
 	material, err = m.materialRepo.Update(material)
 	if err != nil {
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/rbac"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+// adminPolicy keeps admin able to manage policies, it can not be removed
+var adminPolicy = params.Policy{Subject: "admin", Object: "*", Action: "*"}
+
+type policy struct {
+	enforcer *rbac.Enforcer
+	roleRepo repository.RoleRepository
+	name     string
+}
+
+type PolicyUsecase interface {
+	// List role policies
+	List(param params.PolicyFilterParam) appctx.Response
+	// Add role policy, it takes effect immediately and on other instances once they reload policies
+	Add(param params.Policy) appctx.Response
+	// Remove role policy, it takes effect immediately and on other instances once they reload policies
+	Remove(param params.Policy) appctx.Response
+}
+
+func NewPolicyUsecase(db *gorm.DB, enforcer *rbac.Enforcer) PolicyUsecase {
+	return &policy{
+		enforcer: enforcer,
+		roleRepo: repository.NewRoleRepository(db),
+		name:     "Policy Usecase",
+	}
+}
+
+func (p *policy) List(param params.PolicyFilterParam) appctx.Response {
+	policies := []params.Policy{}
+	for _, values := range p.enforcer.Policies(param.Subject) {
+		if len(values) < 3 {
+			continue
+		}
+		policies = append(policies, params.Policy{Subject: values[0], Object: values[1], Action: values[2]})
+	}
+
+	return *appctx.NewResponse().WithData(policies)
+}
+
+func (p *policy) Add(param params.Policy) appctx.Response {
+	if _, err := p.roleRepo.GetByName(param.Subject); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return *appctx.NewResponse().WithErrors(fmt.Sprintf("role %s does not exist", param.Subject)).WithCode(http.StatusBadRequest)
+		}
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	added, err := p.enforcer.AddPolicy(param.Subject, param.Object, param.Action)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Add] %s", p.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+	if !added {
+		return *appctx.NewResponse().WithErrors("policy already exists").WithCode(http.StatusConflict)
+	}
+
+	return *appctx.NewResponse().WithData(param)
+}
+
+func (p *policy) Remove(param params.Policy) appctx.Response {
+	if param == adminPolicy {
+		return *appctx.NewResponse().WithErrors("admin access to every route can not be removed").WithCode(http.StatusBadRequest)
+	}
+
+	removed, err := p.enforcer.RemovePolicy(param.Subject, param.Object, param.Action)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Remove] %s", p.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+	if !removed {
+		return *appctx.NewResponse().WithErrors("policy not found").WithCode(http.StatusNotFound)
+	}
+
+	return *appctx.NewResponse().WithData(param)
+}
@@ -1,7 +1,11 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"gitlab.com/project-quiz/internal/appctx"
 	"gitlab.com/project-quiz/internal/entities"
@@ -17,6 +21,7 @@ import (
 
 type premiumPackage struct {
 	premiumPackageRepo repository.PremiumPackageRepository
+	voucherBatchRepo   repository.VoucherBatchRepository
 	name               string
 }
 
@@ -31,6 +36,10 @@ type PremiumPackageUsecase interface {
 	Detail(ID int) appctx.Response
 	// Delete role
 	Delete(ID int) appctx.Response
+	// Redeem voucher for user
+	Redeem(param params.PremiumPackageRedeemParam) appctx.Response
+	// Get active premium package of user
+	GetActive(userID int) appctx.Response
 	// // Assign Role to user
 	// Assign(userID int, roleName string) appctx.Response
 	// // Revoke Role from user
@@ -40,7 +49,8 @@ type PremiumPackageUsecase interface {
 func NewPremiumPackageUsecase(db *gorm.DB) PremiumPackageUsecase {
 	return &premiumPackage{
 		premiumPackageRepo: repository.NewPremiumPackageRepository(db),
-		name:               "Role Usecase",
+		voucherBatchRepo:   repository.NewVoucherBatchRepository(db),
+		name:               "Premium Package Usecase",
 	}
 }
 
@@ -50,13 +60,22 @@ func (r *premiumPackage) Create(param params.PremiumPackageCreateParam) appctx.R
 	var pp entities.PremiumPackage
 	copier.Copy(&pp, &param)
 
-	voucher, err := random.GenerateRandomVoucher(12)
+	voucher, err := random.GenerateSecureVoucher(12, random.DefaultVoucherAlphabet)
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Create] %s", r.name, err.Error()))
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
 	pp.Token = voucher
 
+	// Package bound to a user directly is active right away
+	if pp.UserID != nil {
+		now := time.Now()
+		activeUntil := now.AddDate(0, 0, pp.LongPeriod)
+		pp.IsActive = true
+		pp.ActiveUntil = &activeUntil
+		pp.RedeemedAt = &now
+	}
+
 	pp, err = r.premiumPackageRepo.Create(pp)
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Create] %s", r.name, err.Error()))
@@ -129,6 +148,92 @@ func (r *premiumPackage) Delete(ID int) appctx.Response {
 	return *appctx.NewResponse().WithMessage("package deleted sucessfully")
 }
 
+func (r *premiumPackage) Redeem(param params.PremiumPackageRedeemParam) appctx.Response {
+	log.Info(fmt.Sprintf("[%s][Redeem] is executed", r.name))
+
+	token := strings.TrimSpace(param.Token)
+	pp, err := r.premiumPackageRepo.GetByToken(token)
+	// Batch vouchers are upper case, allow user to type them in lower case
+	if errors.Is(err, gorm.ErrRecordNotFound) && strings.ToUpper(token) != token {
+		pp, err = r.premiumPackageRepo.GetByToken(strings.ToUpper(token))
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if r.isVoucherTypo(strings.ToUpper(token)) {
+				return *appctx.NewResponse().WithErrors("invalid voucher, please check the code for typo").WithCode(http.StatusNotFound)
+			}
+			return *appctx.NewResponse().WithErrors("invalid voucher").WithCode(http.StatusNotFound)
+		}
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if pp.RevokedAt != nil {
+		return *appctx.NewResponse().WithErrors(repository.ErrVoucherRevoked.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if pp.UserID != nil {
+		return *appctx.NewResponse().WithErrors(repository.ErrVoucherRedeemed.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	// Stack the period on top of the running package
+	now := time.Now()
+	start := now
+	active, err := r.premiumPackageRepo.GetActiveByUser(param.UserID, now)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(fmt.Sprintf("[%s][Redeem] %s", r.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+	if err == nil && active.ActiveUntil.After(start) {
+		start = *active.ActiveUntil
+	}
+
+	pp, err = r.premiumPackageRepo.Redeem(pp, param.UserID, start.AddDate(0, 0, pp.LongPeriod), now)
+	if err != nil {
+		log.Error(fmt.Sprintf("[%s][Redeem] %s", r.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(pp)
+}
+
+// isVoucherTypo tells whether the token looks like a batch voucher with a mistyped character. The token is
+// checked against the alphabet and code length of every batch, a token with a valid checksum is not a typo.
+func (r *premiumPackage) isVoucherTypo(token string) bool {
+	formats, err := r.voucherBatchRepo.Formats()
+	if err != nil {
+		return false
+	}
+
+	typo := false
+	for _, format := range formats {
+		if len(token) != format.CodeLength+1 {
+			continue
+		}
+
+		err := random.ValidateVoucher(token, format.Alphabet)
+		if err == nil {
+			return false
+		}
+		if errors.Is(err, random.ErrVoucherInvalidCheck) {
+			typo = true
+		}
+	}
+
+	return typo
+}
+
+func (r *premiumPackage) GetActive(userID int) appctx.Response {
+	pp, err := r.premiumPackageRepo.GetActiveByUser(userID, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return *appctx.NewResponse().WithErrors("no active premium package").WithCode(http.StatusNotFound)
+		}
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(pp)
+}
+
 // func (r *role) Assign(userID int, roleName string) appctx.Response {
 // 	log.Info(fmt.Sprintf("[%s][Assign] is executed", r.name))
 
@@ -3,6 +3,7 @@ package usecase
 import (
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 
@@ -23,6 +24,8 @@ type question struct {
 	solutionRepo repository.QuestionSolutionRepository
 	optionRepo   repository.QuestionOptionRepository
 	tagRepo      repository.QuestionTagRepository
+	materialRepo repository.MaterialRepository
+	revisionRepo repository.QuestionRevisionRepository
 	minio        minio.MinioStorageContract
 	name         string
 }
@@ -32,12 +35,20 @@ type QuestionUsecase interface {
 	Create(param params.QuestionCreate, isAdmin bool) appctx.Response
 	// Update Question
 	Update(param params.QuestionUpdate) appctx.Response
+	// Update Question owned by contributor while it is draft or rejected
+	UpdateByContributor(param params.QuestionUpdate) appctx.Response
 	// Add Option
 	AddOption(param params.QuestionOptionAdd) appctx.Response
 	// Add Option
 	UpdateOption(param params.QuestionOptionUpdate) appctx.Response
 	// Delete Option
-	DeleteOption(ID int) appctx.Response
+	DeleteOption(ID, actorID int) appctx.Response
+	// Add Option to question owned by contributor while it is draft or rejected
+	AddOptionByContributor(param params.QuestionOptionAdd) appctx.Response
+	// Update Option of question owned by contributor while it is draft or rejected
+	UpdateOptionByContributor(param params.QuestionOptionUpdate) appctx.Response
+	// Delete Option of question owned by contributor while it is draft or rejected
+	DeleteOptionByContributor(ID, contributorID int) appctx.Response
 	// Get list of material
 	List(param params.QuestionFilterParam) appctx.Response
 	// Get list of material
@@ -58,8 +69,18 @@ type QuestionUsecase interface {
 	AddTags(param params.QuestionAddTags) appctx.Response
 	// Remove Tag
 	RemoveTag(param params.QuestionRemoveTag) appctx.Response
+	// Add Tags to question owned by contributor while it is draft or rejected
+	AddTagsByContributor(param params.QuestionAddTags) appctx.Response
+	// Remove Tag of question owned by contributor while it is draft or rejected
+	RemoveTagByContributor(param params.QuestionRemoveTag) appctx.Response
 	// Add Image Placement
 	UploadImagePlacement(questionID int, file *multipart.FileHeader) appctx.Response
+	// Import questions from CSV or XLSX spreadsheet
+	Import(param params.QuestionImportParam, file io.Reader) appctx.Response
+	// Export questions as QTI 2.1 package, Moodle XML or GIFT
+	Export(param params.QuestionExportParam) appctx.Response
+	// Import questions from QTI 2.1 package, Moodle XML or GIFT
+	ImportBank(param params.QuestionBankImportParam, content []byte) appctx.Response
 }
 
 func NewQuestionUsecase(db *gorm.DB, minio minio.MinioStorageContract) QuestionUsecase {
@@ -69,6 +90,8 @@ func NewQuestionUsecase(db *gorm.DB, minio minio.MinioStorageContract) QuestionU
 		solutionRepo: repository.NewQuestionSolutionRepository(db),
 		optionRepo:   repository.NewQuestionOptionRepository(db),
 		tagRepo:      repository.NewQuestionTagRepository(db),
+		materialRepo: repository.NewMaterialRepository(db),
+		revisionRepo: repository.NewQuestionRevisionRepository(db),
 		minio:        minio,
 		name:         "Question Usecase",
 	}
@@ -121,8 +144,24 @@ func (q *question) AddOption(param params.QuestionOptionAdd) appctx.Response {
 		IsImage:     param.IsImage,
 		ImgPath:     param.ImgPath,
 		QuestionID:  param.QuestionID,
+		Position:    param.Position,
+		MatchBody:   param.MatchBody,
 	}
-	option, err := q.optionRepo.Create(option)
+
+	question, err := q.questionRepo.GetAnswerKey(param.QuestionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	options := append(append([]entities.QuestionOption{}, question.QuestionOptions...), option)
+	if err := validateOptions(question, options); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	_, err = q.revisionRepo.Revise(param.QuestionID, param.ActorID, entities.RevisionActionOption, func(tx *gorm.DB) error {
+		option, err = q.optionRepo.WithTx(tx).Create(option)
+		return err
+	})
 	if err != nil {
 		logrus.Error(fmt.Sprintf("[%s][Detail] %s", q.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -142,8 +181,29 @@ func (q *question) UpdateOption(param params.QuestionOptionUpdate) appctx.Respon
 	option.ImgPath = param.ImgPath
 	option.IsImage = param.IsImage
 	option.OptionValue = &param.OptionValue
+	option.Position = param.Position
+	option.MatchBody = param.MatchBody
 
-	option, err = q.optionRepo.Update(option)
+	question, err := q.questionRepo.GetAnswerKey(option.QuestionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	options := []entities.QuestionOption{}
+	for _, current := range question.QuestionOptions {
+		if current.ID == option.ID {
+			current = option
+		}
+		options = append(options, current)
+	}
+	if err := validateOptions(question, options); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	_, err = q.revisionRepo.Revise(option.QuestionID, param.ActorID, entities.RevisionActionOption, func(tx *gorm.DB) error {
+		option, err = q.optionRepo.WithTx(tx).Update(option)
+		return err
+	})
 	if err != nil {
 		logrus.Error(fmt.Sprintf("[%s][Detail] %s", q.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -152,8 +212,31 @@ func (q *question) UpdateOption(param params.QuestionOptionUpdate) appctx.Respon
 	return *appctx.NewResponse().WithData(option)
 }
 
-func (q *question) DeleteOption(ID int) appctx.Response {
-	_, err := q.optionRepo.Delete(ID)
+func (q *question) DeleteOption(ID, actorID int) appctx.Response {
+	option, err := q.optionRepo.Get(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	question, err := q.questionRepo.GetAnswerKey(option.QuestionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	options := []entities.QuestionOption{}
+	for _, current := range question.QuestionOptions {
+		if current.ID != option.ID {
+			options = append(options, current)
+		}
+	}
+	if err := validateOptions(question, options); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	_, err = q.revisionRepo.Revise(option.QuestionID, actorID, entities.RevisionActionOption, func(tx *gorm.DB) error {
+		_, err := q.optionRepo.WithTx(tx).Delete(ID)
+		return err
+	})
 	if err != nil {
 		logrus.Error(fmt.Sprintf("[%s][DeleteOPtion] %s", q.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -222,10 +305,16 @@ func (q *question) Create(param params.QuestionCreate, isAdmin bool) appctx.Resp
 	var question entities.Question
 	copier.Copy(&question, param)
 
+	if err := question.ValidateAnswerKey(question.QuestionOptions); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
 	if !isAdmin {
 		question.IsActive = boolpointer.BoolPointer(false)
+		question.ReviewStatus = entities.ReviewStatusDraft
 	}
 
+	// The first revision is recorded together with the question
 	question, err := q.questionRepo.Create(question)
 	if err != nil {
 		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
@@ -263,51 +352,217 @@ func (q *question) Update(param params.QuestionUpdate) appctx.Response {
 	// 	ContributorID:   param.ContributorID,
 	// }
 
+	current, err := q.questionRepo.GetAnswerKey(param.ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	// Options may be updated partially, so the answer key is checked with the options kept by the question
+	options := optionsAfterUpdate(current.QuestionOptions, param.QuestionOptions)
+	copier.CopyWithOption(&current, param, copier.Option{IgnoreEmpty: true})
+	if err := validateOptions(current, options); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
 	var question entities.Question
 	copier.Copy(&question, param)
 	question.QuestionOptions = []entities.QuestionOption{}
 
-	question, err := q.questionRepo.Update(question)
+	_, err = q.revisionRepo.Revise(param.ID, param.ActorID, entities.RevisionActionUpdate, func(tx *gorm.DB) error {
+		questionRepo := q.questionRepo.WithTx(tx)
+		optionRepo := q.optionRepo.WithTx(tx)
+
+		question, err = questionRepo.Update(question)
+		if err != nil {
+			return err
+		}
+
+		logrus.Debug(question)
+
+		for i := 0; i < len(param.QuestionOptions); i++ {
+			var questionOption entities.QuestionOption
+			if param.QuestionOptions[i].ID == 0 {
+				questionOption = entities.QuestionOption{
+					Body:        param.QuestionOptions[i].Body,
+					OptionValue: &param.QuestionOptions[i].OptionValue,
+					IsImage:     param.QuestionOptions[i].IsImage,
+					ImgPath:     param.QuestionOptions[i].ImgPath,
+					QuestionID:  question.ID,
+					Position:    param.QuestionOptions[i].Position,
+					MatchBody:   param.QuestionOptions[i].MatchBody,
+				}
+
+				questionOption, err = optionRepo.Create(questionOption)
+				if err != nil {
+					return err
+				}
+			} else {
+				questionOption = entities.QuestionOption{
+					ID:          param.QuestionOptions[i].ID,
+					Body:        param.QuestionOptions[i].Body,
+					OptionValue: &param.QuestionOptions[i].OptionValue,
+					IsImage:     param.QuestionOptions[i].IsImage,
+					ImgPath:     param.QuestionOptions[i].ImgPath,
+					QuestionID:  question.ID,
+					Position:    param.QuestionOptions[i].Position,
+					MatchBody:   param.QuestionOptions[i].MatchBody,
+				}
+
+				questionOption, err = optionRepo.Update(questionOption)
+				if err != nil {
+					return err
+				}
+			}
+			question.QuestionOptions = append(question.QuestionOptions, questionOption)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
 	}
 
-	logrus.Debug(question)
+	return *appctx.NewResponse().WithData(question)
+}
 
-	for i := 0; i < len(param.QuestionOptions); i++ {
-		var questionOption entities.QuestionOption
-		if param.QuestionOptions[i].ID == 0 {
-			questionOption = entities.QuestionOption{
-				Body:        param.QuestionOptions[i].Body,
-				OptionValue: &param.QuestionOptions[i].OptionValue,
-				IsImage:     param.QuestionOptions[i].IsImage,
-				ImgPath:     param.QuestionOptions[i].ImgPath,
-				QuestionID:  question.ID,
-			}
+// validateOptions checks the answer key of the question with every option it has after the change,
+// options of a question which has none yet are not checked so they can be added one by one
+func validateOptions(question entities.Question, options []entities.QuestionOption) error {
+	if len(options) == 0 {
+		return question.ValidateAnswerKey(nil)
+	}
 
-			questionOption, err = q.optionRepo.Create(questionOption)
-			if err != nil {
-				return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
-			}
-		} else {
-			questionOption = entities.QuestionOption{
-				ID:          param.QuestionOptions[i].ID,
-				Body:        param.QuestionOptions[i].Body,
-				OptionValue: &param.QuestionOptions[i].OptionValue,
-				IsImage:     param.QuestionOptions[i].IsImage,
-				ImgPath:     param.QuestionOptions[i].ImgPath,
-				QuestionID:  question.ID,
-			}
+	return question.ValidateAnswerKey(options)
+}
+
+// optionsAfterUpdate returns the options of the question once the options of the update are saved
+func optionsAfterUpdate(options []entities.QuestionOption, updates []params.QuestionOptionUpdate) []entities.QuestionOption {
+	result := append([]entities.QuestionOption{}, options...)
+
+	for _, update := range updates {
+		optionValue := update.OptionValue
+		option := entities.QuestionOption{
+			ID:          update.ID,
+			Body:        update.Body,
+			OptionValue: &optionValue,
+			IsImage:     update.IsImage,
+			ImgPath:     update.ImgPath,
+			Position:    update.Position,
+			MatchBody:   update.MatchBody,
+		}
 
-			questionOption, err = q.optionRepo.Update(questionOption)
-			if err != nil {
-				return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		found := false
+		for i := range result {
+			if update.ID != 0 && result[i].ID == update.ID {
+				option.QuestionID = result[i].QuestionID
+				result[i] = option
+				found = true
 			}
 		}
-		question.QuestionOptions = append(question.QuestionOptions, questionOption)
+		if !found {
+			result = append(result, option)
+		}
 	}
 
-	return *appctx.NewResponse().WithData(question)
+	return result
+}
+
+func (q *question) UpdateByContributor(param params.QuestionUpdate) appctx.Response {
+	current, err := q.questionRepo.GetAnswerKey(param.ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if current.ContributorID != param.ContributorID {
+		return *appctx.NewResponse().WithErrors(ErrNotQuestionOwner.Error()).WithCode(http.StatusForbidden)
+	}
+
+	// Options are updated by ID, so they must be options of this question
+	optionIDs := make(map[int]bool, len(current.QuestionOptions))
+	for _, option := range current.QuestionOptions {
+		optionIDs[option.ID] = true
+	}
+	for _, option := range param.QuestionOptions {
+		if option.ID != 0 && !optionIDs[option.ID] {
+			return *appctx.NewResponse().WithErrors(ErrNotQuestionOwner.Error()).WithCode(http.StatusForbidden)
+		}
+	}
+
+	if !current.IsEditableByContributor() {
+		return *appctx.NewResponse().WithErrors(fmt.Sprintf("question is %s and can not be edited", current.ReviewStatusOf())).WithCode(http.StatusConflict)
+	}
+
+	param.IsActive = nil
+	return q.Update(param)
+}
+
+// editableQuestion gets the question and makes sure the contributor owns it and may still change it
+func (q *question) editableQuestion(questionID, contributorID int) (entities.Question, *appctx.Response) {
+	question, err := q.questionRepo.GetAnswerKey(questionID)
+	if err != nil {
+		return question, appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if question.ContributorID != contributorID {
+		return question, appctx.NewResponse().WithErrors(ErrNotQuestionOwner.Error()).WithCode(http.StatusForbidden)
+	}
+
+	if !question.IsEditableByContributor() {
+		return question, appctx.NewResponse().WithErrors(fmt.Sprintf("question is %s and can not be edited", question.ReviewStatusOf())).WithCode(http.StatusConflict)
+	}
+
+	return question, nil
+}
+
+// editableOption gets the question of the option and makes sure the contributor may still change it
+func (q *question) editableOption(optionID, contributorID int) *appctx.Response {
+	option, err := q.optionRepo.Get(optionID)
+	if err != nil {
+		return appctx.NewResponse().WithErrorObj(err)
+	}
+
+	_, resp := q.editableQuestion(option.QuestionID, contributorID)
+	return resp
+}
+
+func (q *question) AddOptionByContributor(param params.QuestionOptionAdd) appctx.Response {
+	if _, resp := q.editableQuestion(param.QuestionID, param.ContributorID); resp != nil {
+		return *resp
+	}
+
+	return q.AddOption(param)
+}
+
+func (q *question) UpdateOptionByContributor(param params.QuestionOptionUpdate) appctx.Response {
+	if resp := q.editableOption(param.ID, param.ContributorID); resp != nil {
+		return *resp
+	}
+
+	return q.UpdateOption(param)
+}
+
+func (q *question) DeleteOptionByContributor(ID, contributorID int) appctx.Response {
+	if resp := q.editableOption(ID, contributorID); resp != nil {
+		return *resp
+	}
+
+	return q.DeleteOption(ID, contributorID)
+}
+
+func (q *question) AddTagsByContributor(param params.QuestionAddTags) appctx.Response {
+	if _, resp := q.editableQuestion(param.QuestionID, param.ContributorID); resp != nil {
+		return *resp
+	}
+
+	return q.AddTags(param)
+}
+
+func (q *question) RemoveTagByContributor(param params.QuestionRemoveTag) appctx.Response {
+	if _, resp := q.editableQuestion(param.QuestionID, param.ContributorID); resp != nil {
+		return *resp
+	}
+
+	return q.RemoveTag(param)
 }
 
 func (q *question) AddTags(param params.QuestionAddTags) appctx.Response {
@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/params/generics"
+	"gitlab.com/project-quiz/utils/boolpointer"
+	"gitlab.com/project-quiz/utils/interchange"
+)
+
+func (q *question) Export(param params.QuestionExportParam) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Export] is executed", q.name))
+
+	format, err := interchange.Get(param.Format)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	questions, err := q.questionRepo.ListForExport(param)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if len(questions) == 0 {
+		return *appctx.NewResponse().WithErrors("no question to export").WithCode(http.StatusNotFound)
+	}
+
+	materials, err := q.materialRepo.GetAll()
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+	materialNames := make(map[int]string)
+	for _, material := range materials {
+		materialNames[material.ID] = material.Name
+	}
+
+	questionIDs := make([]int, len(questions))
+	for i, question := range questions {
+		questionIDs[i] = question.ID
+	}
+	solutions, err := q.solutionRepo.GetByQuestionIDs(questionIDs)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+	solutionOf := make(map[int]entities.QuestionSolution)
+	for _, solution := range solutions {
+		solutionOf[solution.QuestionID] = solution
+	}
+
+	bank := interchange.Bank{}
+	for _, question := range questions {
+		item, err := q.exportQuestion(&bank, question, materialNames[question.MaterialID], solutionOf[question.ID])
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Export] %s", q.name, err.Error()))
+			return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		}
+		bank.Questions = append(bank.Questions, item)
+	}
+
+	file, skipped, err := format.Encode(bank)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Export] %s", q.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	response := generics.FileResponse{Name: file.Name, ContentType: file.ContentType, Content: file.Content}
+	if len(skipped) > 0 {
+		logrus.Warn(fmt.Sprintf("[%s][Export] %s can not express %s", q.name, param.Format, strings.Join(skipped, ", ")))
+		response.Headers = map[string]string{"X-Skipped-Questions": strings.Join(skipped, ",")}
+	}
+
+	return *appctx.NewResponse().WithData(response)
+}
+
+func (q *question) exportQuestion(bank *interchange.Bank, question entities.Question, material string, solution entities.QuestionSolution) (interchange.Question, error) {
+	item := interchange.Question{
+		Code:             question.Code,
+		Type:             question.QuestionType(),
+		ScoringMode:      question.ScoringMode,
+		Material:         material,
+		Body:             question.Body,
+		NumericAnswer:    question.NumericAnswer,
+		NumericTolerance: question.NumericTolerance,
+		AcceptedAnswers:  question.AcceptedAnswers,
+		CaseSensitive:    question.CaseSensitive,
+		Solution:         solution.SolutionText,
+	}
+	if item.ScoringMode == "" {
+		item.ScoringMode = entities.ScoringModeAllOrNothing
+	}
+
+	var err error
+	if question.IsImage {
+		if item.Image, err = q.exportImage(bank, question.ImgPath); err != nil {
+			return item, err
+		}
+	}
+
+	if solution.SolutionType == "image" || solution.SolutionType == "img" {
+		if item.SolutionImage, err = q.exportImage(bank, solution.SolutionImgUrl); err != nil {
+			return item, err
+		}
+	}
+
+	for _, tag := range question.QuestionTags {
+		item.Tags = append(item.Tags, tag.Name)
+	}
+
+	for _, option := range question.QuestionOptions {
+		exported := interchange.Option{
+			Body:      option.Body,
+			IsCorrect: option.OptionValue != nil && *option.OptionValue,
+			MatchBody: option.MatchBody,
+		}
+		// Every option of ordering and matching question is part of the answer
+		if item.Type == entities.QuestionTypeOrdering || item.Type == entities.QuestionTypeMatching {
+			exported.IsCorrect = true
+		}
+		if option.IsImage {
+			if exported.Image, err = q.exportImage(bank, option.ImgPath); err != nil {
+				return item, err
+			}
+		}
+		item.Options = append(item.Options, exported)
+	}
+
+	return item, nil
+}
+
+// exportImage reads the image from storage into the bank, external urls are not exported
+func (q *question) exportImage(bank *interchange.Bank, filePath string) (string, error) {
+	if filePath == "" || strings.HasPrefix(filePath, "http") {
+		return "", nil
+	}
+
+	content, err := q.minio.GetObject(filePath)
+	if err != nil {
+		return "", fmt.Errorf("cannot read image %s: %w", filePath, err)
+	}
+
+	return bank.AddMedia(filePath, content), nil
+}
+
+func (q *question) ImportBank(param params.QuestionBankImportParam, content []byte) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Import Bank] is executed", q.name))
+
+	format, err := interchange.Get(param.Format)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	bank, err := format.Decode(param.FileName, content)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if len(bank.Questions) > importMaxRows {
+		return *appctx.NewResponse().WithErrors(fmt.Sprintf("file can have at most %d questions", importMaxRows)).WithCode(http.StatusBadRequest)
+	}
+
+	allMaterials, err := q.materialRepo.GetAll()
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+	materials := make(map[string]entities.Material)
+	var fallback *entities.Material
+	for i, material := range allMaterials {
+		materials[strings.ToLower(material.Name)] = material
+		if material.ID == param.MaterialID {
+			fallback = &allMaterials[i]
+		}
+	}
+	if param.MaterialID != 0 && fallback == nil {
+		return *appctx.NewResponse().WithErrors(fmt.Sprintf("material %d does not exist", param.MaterialID)).WithCode(http.StatusBadRequest)
+	}
+
+	tags, err := q.bankTags(bank)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	report := params.QuestionImportResponse{DryRun: param.DryRun}
+	questions := []entities.Question{}
+	solutions := []*entities.QuestionSolution{}
+	items := []interchange.Question{}
+	rowOfQuestion := []int{}
+
+	for i, item := range bank.Questions {
+		question, solution, errs := importBankQuestion(item, materials, fallback)
+		report.Total++
+		if len(errs) > 0 {
+			report.Invalid++
+		} else {
+			report.Valid++
+
+			question.ContributorID = param.ContributorID
+			if !param.IsAdmin {
+				question.IsActive = boolpointer.BoolPointer(false)
+				question.ReviewStatus = entities.ReviewStatusDraft
+			}
+			questions = append(questions, question)
+			solutions = append(solutions, solution)
+			items = append(items, item)
+			rowOfQuestion = append(rowOfQuestion, len(report.Rows))
+		}
+
+		report.Rows = append(report.Rows, params.QuestionImportRowResult{
+			// Position of the question inside the file
+			Row:    i + 1,
+			Body:   item.Body,
+			Code:   item.Code,
+			Errors: errs,
+		})
+	}
+
+	if report.Invalid > 0 {
+		return *appctx.NewResponse().WithErrors(fmt.Sprintf("%d of %d questions are invalid, nothing is imported", report.Invalid, report.Total)).WithCode(http.StatusBadRequest).WithData(report)
+	}
+
+	if param.DryRun {
+		return *appctx.NewResponse().WithMessage("every question is valid").WithData(report)
+	}
+
+	for i := range questions {
+		for _, name := range items[i].Tags {
+			questions[i].QuestionTags = append(questions[i].QuestionTags, bankTag(tags, name))
+		}
+	}
+
+	uploaded, err := q.uploadBankMedia(bank, items, questions, solutions)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Import Bank] %s", q.name, err.Error()))
+		q.deleteFiles(uploaded)
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest).WithData(report)
+	}
+
+	questions, err = q.questionRepo.CreateMany(questions, solutions)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Import Bank] %s", q.name, err.Error()))
+		q.deleteFiles(uploaded)
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest).WithData(report)
+	}
+
+	for i, question := range questions {
+		report.Rows[rowOfQuestion[i]].QuestionID = question.ID
+		report.Rows[rowOfQuestion[i]].Code = question.Code
+	}
+	report.Created = len(questions)
+
+	return *appctx.NewResponse().WithMessage(fmt.Sprintf("%d questions imported", report.Created)).WithData(report)
+}
+
+// bankTags loads the existing tags used by the bank, keyed by lower cased name
+func (q *question) bankTags(bank interchange.Bank) (map[string]entities.QuestionTag, error) {
+	tags := make(map[string]entities.QuestionTag)
+	names := []string{}
+	for _, item := range bank.Questions {
+		names = append(names, item.Tags...)
+	}
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	found, err := q.tagRepo.ListByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range found {
+		tags[strings.ToLower(tag.Name)] = tag
+	}
+
+	return tags, nil
+}
+
+// bankTag returns the tag by its name, tags unknown to us are returned without ID
+// so they are created together with the questions
+func bankTag(tags map[string]entities.QuestionTag, name string) entities.QuestionTag {
+	if tag, ok := tags[strings.ToLower(name)]; ok {
+		return tag
+	}
+
+	return entities.QuestionTag{Name: name}
+}
+
+// uploadBankMedia stores the images of the bank and points the questions to them.
+// It returns the uploaded paths so they can be removed when the import fails.
+func (q *question) uploadBankMedia(bank interchange.Bank, items []interchange.Question, questions []entities.Question, solutions []*entities.QuestionSolution) ([]string, error) {
+	uploaded := []string{}
+	paths := make(map[string]string)
+	folder := fmt.Sprintf("/question/import/%d", time.Now().UnixNano())
+
+	upload := func(name string) (string, error) {
+		if filePath, ok := paths[name]; ok {
+			return filePath, nil
+		}
+
+		content := bank.Media[name]
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+
+		filePath := fmt.Sprintf("%s/file_%d%s", folder, len(paths)+1, path.Ext(name))
+		if err := q.minio.PutObject(filePath, content, contentType); err != nil {
+			return "", err
+		}
+		paths[name] = filePath
+		uploaded = append(uploaded, filePath)
+
+		return filePath, nil
+	}
+
+	for i, item := range items {
+		if item.Image != "" {
+			filePath, err := upload(item.Image)
+			if err != nil {
+				return uploaded, err
+			}
+			questions[i].IsImage = true
+			questions[i].ImgPath = filePath
+		}
+
+		for j, option := range item.Options {
+			if option.Image == "" {
+				continue
+			}
+			filePath, err := upload(option.Image)
+			if err != nil {
+				return uploaded, err
+			}
+			questions[i].QuestionOptions[j].IsImage = true
+			questions[i].QuestionOptions[j].ImgPath = filePath
+		}
+
+		if item.SolutionImage != "" && solutions[i] != nil {
+			filePath, err := upload(item.SolutionImage)
+			if err != nil {
+				return uploaded, err
+			}
+			solutions[i].SolutionImgUrl = filePath
+		}
+	}
+
+	return uploaded, nil
+}
+
+func (q *question) deleteFiles(paths []string) {
+	for _, filePath := range paths {
+		if err := q.minio.DeleteFile(filePath); err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Delete Files] %s", q.name, err.Error()))
+		}
+	}
+}
+
+// importBankQuestion maps the interchange question to question, option and solution entities
+func importBankQuestion(item interchange.Question, materials map[string]entities.Material, fallback *entities.Material) (entities.Question, *entities.QuestionSolution, []string) {
+	errs := append([]string{}, item.Errors...)
+
+	question := entities.Question{
+		Body:             item.Body,
+		Type:             item.Type,
+		ScoringMode:      item.ScoringMode,
+		NumericAnswer:    item.NumericAnswer,
+		NumericTolerance: item.NumericTolerance,
+		AcceptedAnswers:  item.AcceptedAnswers,
+		CaseSensitive:    item.CaseSensitive,
+	}
+
+	if question.Body == "" && item.Image == "" {
+		errs = append(errs, "body is required")
+	}
+
+	if material, ok := materials[strings.ToLower(item.Material)]; ok {
+		question.MaterialID = material.ID
+	} else if fallback != nil {
+		question.MaterialID = fallback.ID
+	} else {
+		errs = append(errs, fmt.Sprintf("material %q does not exist, choose a material for the import", item.Material))
+	}
+
+	for i, option := range item.Options {
+		entity := entities.QuestionOption{
+			Body:        option.Body,
+			OptionValue: boolpointer.BoolPointer(option.IsCorrect),
+			MatchBody:   option.MatchBody,
+		}
+		if question.Type == entities.QuestionTypeOrdering {
+			position := i + 1
+			entity.Position = &position
+		}
+		question.QuestionOptions = append(question.QuestionOptions, entity)
+	}
+
+	if len(item.Errors) == 0 {
+		if err := question.ValidateAnswerKey(question.QuestionOptions); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	var solution *entities.QuestionSolution
+	if item.Solution != "" || item.SolutionImage != "" {
+		solution = &entities.QuestionSolution{
+			SolutionType: "text",
+			SolutionText: item.Solution,
+		}
+		if item.SolutionImage != "" {
+			solution.SolutionType = "image"
+		}
+	}
+
+	return question, solution, errs
+}
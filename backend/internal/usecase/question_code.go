@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+const questionCodeDefaultBatchSize = 500
+
+type questionCode struct {
+	codeRepo repository.QuestionCodeRepository
+	name     string
+}
+
+type QuestionCodeUsecase interface {
+	// Renumber question codes per prefix of their material
+	Resequence(param params.QuestionCodeResequenceParam) appctx.Response
+	// Give codes to questions without one and sync the counters
+	Backfill(param params.QuestionCodeBackfillParam) appctx.Response
+}
+
+func NewQuestionCodeUsecase(db *gorm.DB) QuestionCodeUsecase {
+	return &questionCode{
+		codeRepo: repository.NewQuestionCodeRepository(db),
+		name:     "Question Code Usecase",
+	}
+}
+
+func (q *questionCode) Resequence(param params.QuestionCodeResequenceParam) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Resequence] is executed", q.name))
+	report := params.QuestionCodeReport{DryRun: param.DryRun}
+
+	var err error
+	if param.DryRun {
+		report.Prefixes, err = q.codeRepo.Plan()
+	} else {
+		report.Prefixes, err = q.codeRepo.Resequence()
+	}
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	report.Duplicates, err = q.codeRepo.Duplicates()
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	changed := 0
+	for _, prefix := range report.Prefixes {
+		changed += prefix.Changed
+	}
+
+	return *appctx.NewResponse().WithData(report).WithMessage(fmt.Sprintf("%d question codes resequenced", changed))
+}
+
+func (q *questionCode) Backfill(param params.QuestionCodeBackfillParam) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Backfill] is executed", q.name))
+	report := params.QuestionCodeReport{DryRun: param.DryRun}
+
+	if param.BatchSize <= 0 {
+		param.BatchSize = questionCodeDefaultBatchSize
+	}
+
+	var err error
+	if param.DryRun {
+		report.Backfilled, err = q.codeRepo.CountMissing()
+		if err != nil {
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+	} else {
+		// Counters must be past every code in use before new codes are given
+		if err := q.codeRepo.SyncCounters(); err != nil {
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+
+		report.Backfilled, err = q.codeRepo.Backfill(param.BatchSize)
+		if err != nil {
+			return *appctx.NewResponse().WithErrorObj(err).WithData(report)
+		}
+	}
+
+	report.Duplicates, err = q.codeRepo.Duplicates()
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if param.UniqueIndex && !param.DryRun {
+		if len(report.Duplicates) > 0 {
+			return *appctx.NewResponse().WithErrors("duplicate question codes found, resequence the codes before creating the unique index").
+				WithCode(http.StatusConflict).WithData(report)
+		}
+
+		if err := q.codeRepo.CreateUniqueIndex(); err != nil {
+			return *appctx.NewResponse().WithErrorObj(err).WithData(report)
+		}
+		report.UniqueIndex = true
+	}
+
+	return *appctx.NewResponse().WithData(report).WithMessage(fmt.Sprintf("%d question codes backfilled", report.Backfilled))
+}
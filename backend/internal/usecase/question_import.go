@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/utils/boolpointer"
+	"gitlab.com/project-quiz/utils/spreadsheet"
+)
+
+// Columns of the question import spreadsheet, matched case insensitive on the header row.
+//
+//	body         question body, required
+//	type         question type, single_choice when empty
+//	scoring_mode all_or_nothing or partial
+//	material     material ID or name, required
+//	options      options separated by "|", in the correct order for ordering question
+//	             and written as "left => right" for matching question
+//	answer       option letter for single choice and true/false ("A"), option letters
+//	             separated by "," for multiple answer ("A,C"), the number for numeric and
+//	             accepted answers separated by "|" for short text
+//	tolerance    tolerance of numeric answer
+//	tags         tag names separated by ","
+//	solution     solution text
+//	is_pack_only true when the question is only shown inside question packs
+const (
+	importColumnBody        = "body"
+	importColumnType        = "type"
+	importColumnScoringMode = "scoring_mode"
+	importColumnMaterial    = "material"
+	importColumnOptions     = "options"
+	importColumnAnswer      = "answer"
+	importColumnTolerance   = "tolerance"
+	importColumnTags        = "tags"
+	importColumnSolution    = "solution"
+	importColumnIsPackOnly  = "is_pack_only"
+
+	importMaxRows = 1000
+)
+
+var trueFalseOptions = []string{"Benar", "Salah"}
+
+func (q *question) Import(param params.QuestionImportParam, file io.Reader) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Import] is executed", q.name))
+
+	rows, err := spreadsheet.ReadRows(param.FileName, file)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	if len(rows) < 2 {
+		return *appctx.NewResponse().WithErrors("spreadsheet must have a header row and at least one question").WithCode(http.StatusBadRequest)
+	}
+
+	if len(rows)-1 > importMaxRows {
+		return *appctx.NewResponse().WithErrors(fmt.Sprintf("spreadsheet can have at most %d questions", importMaxRows)).WithCode(http.StatusBadRequest)
+	}
+
+	header := spreadsheet.Header(rows[0])
+	for _, column := range []string{importColumnBody, importColumnMaterial} {
+		if _, ok := header[column]; !ok {
+			return *appctx.NewResponse().WithErrors(fmt.Sprintf("column %s is required", column)).WithCode(http.StatusBadRequest)
+		}
+	}
+
+	materials, tags, err := q.importLookups(header, rows[1:])
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Import] %s", q.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	report := params.QuestionImportResponse{DryRun: param.DryRun}
+	questions := []entities.Question{}
+	solutions := []*entities.QuestionSolution{}
+	rowOfQuestion := []int{}
+
+	for i, row := range rows[1:] {
+		if isEmptyRow(row) {
+			continue
+		}
+
+		question, solution, errs := parseImportRow(header, row, materials, tags)
+		result := params.QuestionImportRowResult{
+			// Row number as shown by spreadsheet applications, the header is row 1
+			Row:    i + 2,
+			Body:   question.Body,
+			Errors: errs,
+		}
+
+		report.Total++
+		if len(errs) > 0 {
+			report.Invalid++
+		} else {
+			report.Valid++
+
+			question.ContributorID = param.ContributorID
+			if !param.IsAdmin {
+				question.IsActive = boolpointer.BoolPointer(false)
+				question.ReviewStatus = entities.ReviewStatusDraft
+			}
+			questions = append(questions, question)
+			solutions = append(solutions, solution)
+			rowOfQuestion = append(rowOfQuestion, len(report.Rows))
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	if report.Invalid > 0 {
+		return *appctx.NewResponse().WithErrors(fmt.Sprintf("%d of %d rows are invalid, nothing is imported", report.Invalid, report.Total)).WithCode(http.StatusBadRequest).WithData(report)
+	}
+
+	if param.DryRun {
+		return *appctx.NewResponse().WithMessage("every row is valid").WithData(report)
+	}
+
+	questions, err = q.questionRepo.CreateMany(questions, solutions)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Import] %s", q.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest).WithData(report)
+	}
+
+	for i, question := range questions {
+		report.Rows[rowOfQuestion[i]].QuestionID = question.ID
+		report.Rows[rowOfQuestion[i]].Code = question.Code
+	}
+	report.Created = len(questions)
+
+	return *appctx.NewResponse().WithMessage(fmt.Sprintf("%d questions imported", report.Created)).WithData(report)
+}
+
+// importLookups load materials and the tags used by the rows, keyed by lower cased name
+func (q *question) importLookups(header map[string]int, rows [][]string) (map[string]entities.Material, map[string]entities.QuestionTag, error) {
+	materials := make(map[string]entities.Material)
+	allMaterials, err := q.materialRepo.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, material := range allMaterials {
+		materials[strconv.Itoa(material.ID)] = material
+		materials[strings.ToLower(material.Name)] = material
+	}
+
+	tags := make(map[string]entities.QuestionTag)
+	names := []string{}
+	for _, row := range rows {
+		names = append(names, splitCell(spreadsheet.Cell(header, row, importColumnTags), ",")...)
+	}
+	if len(names) > 0 {
+		found, err := q.tagRepo.ListByNames(names)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, tag := range found {
+			tags[strings.ToLower(tag.Name)] = tag
+		}
+	}
+
+	return materials, tags, nil
+}
+
+func parseImportRow(header map[string]int, row []string, materials map[string]entities.Material, tags map[string]entities.QuestionTag) (entities.Question, *entities.QuestionSolution, []string) {
+	errs := []string{}
+	cell := func(column string) string {
+		return spreadsheet.Cell(header, row, column)
+	}
+
+	question := entities.Question{
+		Body:        cell(importColumnBody),
+		Type:        strings.ToLower(cell(importColumnType)),
+		ScoringMode: strings.ToLower(cell(importColumnScoringMode)),
+	}
+	if question.Type == "" {
+		question.Type = entities.QuestionTypeSingleChoice
+	}
+	if question.ScoringMode == "" {
+		question.ScoringMode = entities.ScoringModeAllOrNothing
+	}
+	if question.ScoringMode != entities.ScoringModeAllOrNothing && question.ScoringMode != entities.ScoringModePartial {
+		errs = append(errs, fmt.Sprintf("unknown scoring_mode %s", question.ScoringMode))
+	}
+
+	if question.Body == "" {
+		errs = append(errs, "body is required")
+	}
+
+	if material, ok := materials[strings.ToLower(cell(importColumnMaterial))]; ok {
+		question.MaterialID = material.ID
+	} else {
+		errs = append(errs, fmt.Sprintf("material %q does not exist", cell(importColumnMaterial)))
+	}
+
+	for _, name := range splitCell(cell(importColumnTags), ",") {
+		tag, ok := tags[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("tag %q does not exist", name))
+			continue
+		}
+		question.QuestionTags = append(question.QuestionTags, tag)
+	}
+
+	if isPackOnly := strings.ToLower(cell(importColumnIsPackOnly)); isPackOnly != "" {
+		value, err := strconv.ParseBool(isPackOnly)
+		if err != nil {
+			errs = append(errs, "is_pack_only must be true or false")
+		}
+		question.IsPackOnly = &value
+	}
+
+	options, err := parseImportAnswer(&question, cell(importColumnOptions), cell(importColumnAnswer), cell(importColumnTolerance))
+	if err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		question.QuestionOptions = options
+		if err := question.ValidateAnswerKey(options); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	var solution *entities.QuestionSolution
+	if text := cell(importColumnSolution); text != "" {
+		solution = &entities.QuestionSolution{
+			SolutionType: "text",
+			SolutionText: text,
+		}
+	}
+
+	return question, solution, errs
+}
+
+// parseImportAnswer build options and answer key of the question from the options and answer cells
+func parseImportAnswer(question *entities.Question, optionsCell, answer, tolerance string) ([]entities.QuestionOption, error) {
+	switch question.Type {
+	case entities.QuestionTypeNumeric:
+		value, err := strconv.ParseFloat(strings.ReplaceAll(answer, ",", "."), 64)
+		if err != nil {
+			return nil, fmt.Errorf("answer %q is not a number", answer)
+		}
+		question.NumericAnswer = &value
+
+		if tolerance != "" {
+			question.NumericTolerance, err = strconv.ParseFloat(strings.ReplaceAll(tolerance, ",", "."), 64)
+			if err != nil {
+				return nil, fmt.Errorf("tolerance %q is not a number", tolerance)
+			}
+		}
+		return nil, nil
+	case entities.QuestionTypeShortText:
+		question.AcceptedAnswers = splitCell(answer, "|")
+		return nil, nil
+	}
+
+	bodies := splitCell(optionsCell, "|")
+	if question.Type == entities.QuestionTypeTrueFalse && len(bodies) == 0 {
+		bodies = trueFalseOptions
+	}
+	if len(bodies) == 0 {
+		return nil, fmt.Errorf("options are required for %s question", question.Type)
+	}
+
+	options := make([]entities.QuestionOption, len(bodies))
+	for i, body := range bodies {
+		options[i] = entities.QuestionOption{Body: body, OptionValue: boolpointer.BoolPointer(false)}
+	}
+
+	switch question.Type {
+	case entities.QuestionTypeOrdering:
+		for i := range options {
+			position := i + 1
+			options[i].Position = &position
+		}
+	case entities.QuestionTypeMatching:
+		for i, body := range bodies {
+			pair := strings.SplitN(body, "=>", 2)
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("option %q of matching question must be written as \"left => right\"", body)
+			}
+			options[i].Body = strings.TrimSpace(pair[0])
+			options[i].MatchBody = strings.TrimSpace(pair[1])
+		}
+	case entities.QuestionTypeSingleChoice, entities.QuestionTypeTrueFalse, entities.QuestionTypeMultipleAnswer:
+		letters := splitCell(answer, ",")
+		if len(letters) == 0 {
+			return nil, fmt.Errorf("answer is required for %s question", question.Type)
+		}
+		for _, letter := range letters {
+			i, err := optionIndex(letter, len(options))
+			if err != nil {
+				return nil, err
+			}
+			options[i].OptionValue = boolpointer.BoolPointer(true)
+		}
+	default:
+		return nil, fmt.Errorf("unknown question type %s", question.Type)
+	}
+
+	return options, nil
+}
+
+// optionIndex resolve option letter (A, B, ...) or 1 based number to the index of the option
+func optionIndex(letter string, count int) (int, error) {
+	index := -1
+	if n, err := strconv.Atoi(letter); err == nil {
+		index = n - 1
+	} else if len(letter) == 1 {
+		index = int(strings.ToUpper(letter)[0] - 'A')
+	}
+
+	if index < 0 || index >= count {
+		return 0, fmt.Errorf("answer %q does not refer to any option", letter)
+	}
+
+	return index, nil
+}
+
+func splitCell(cell, sep string) []string {
+	values := []string{}
+	for _, value := range strings.Split(cell, sep) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,12 +12,22 @@ import (
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
 	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/minio"
+	"gitlab.com/project-quiz/utils/pagination/cursor"
+	"gitlab.com/project-quiz/utils/postgres"
+	"gitlab.com/project-quiz/utils/scoring"
 	"gorm.io/gorm"
 )
 
 type questionPack struct {
 	questionPackRepo       repository.QuestionPackRepository
 	questionPackAttempRepo repository.QuestionPackAttemptRepository
+	attemptRepo            repository.UserQuestionAttemptRepository
+	optionRepo             repository.QuestionOptionRepository
+	solutionRepo           repository.QuestionSolutionRepository
+	premiumRepo            repository.PremiumPackageRepository
+	minio                  minio.MinioStorageContract
+	badges                 badgeAwarder
 	name                   string
 }
 
@@ -29,6 +40,8 @@ type QuestionPackUsecase interface {
 	List(param params.QuestionPackFilterParam) appctx.Response
 	// Get detail of question pack
 	Detail(ID int) appctx.Response
+	// Get detail of question pack without the answer key of its questions
+	StudentDetail(ID int) appctx.Response
 	// Delete question pack
 	Delete(ID int) appctx.Response
 	// Add Questions
@@ -41,14 +54,24 @@ type QuestionPackUsecase interface {
 	FinishQuestionPack(QuestionPackAttemptID, UserID int) appctx.Response
 	// Get attempt list
 	GetAttemptList(param params.QuestionPackAttemptFilterParam) appctx.Response
+	// Get attempt with its score breakdown
+	GetAttempt(QuestionPackAttemptID int) appctx.Response
 	// Get Question Pack Attempt Detail
-	// GetQuestionPackAttemptDetail(QuestionPackAttemptID int) appctx.Response
+	GetQuestionPackAttemptDetail(QuestionPackAttemptID, UserID int) appctx.Response
+	// Finish and score attempts whose deadline has passed, returns number of finished attempts
+	FinishExpiredAttempts(now time.Time) (int, error)
 }
 
-func NewQuestionPackUsecase(db *gorm.DB) QuestionPackUsecase {
+func NewQuestionPackUsecase(db *gorm.DB, minio minio.MinioStorageContract) QuestionPackUsecase {
 	return &questionPack{
 		questionPackRepo:       repository.NewQuestionPackRepository(db),
 		questionPackAttempRepo: repository.NewQuestionPackAttemptRepository(db),
+		attemptRepo:            repository.NewUserQuestionAttemptRepository(db),
+		optionRepo:             repository.NewQuestionOptionRepository(db),
+		solutionRepo:           repository.NewQuestionSolutionRepository(db),
+		premiumRepo:            repository.NewPremiumPackageRepository(db),
+		minio:                  minio,
+		badges:                 newBadgeAwarder(db, minio),
 		name:                   "QUestion Pack Usecase",
 	}
 }
@@ -109,6 +132,21 @@ func (q *questionPack) Detail(ID int) appctx.Response {
 	return *ctx.WithData(pack)
 }
 
+func (q *questionPack) StudentDetail(ID int) appctx.Response {
+	ctx := appctx.NewResponse()
+	pack, err := q.questionPackRepo.Get(ID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Student Detail] %s", q.name, err.Error()))
+		return *ctx.WithErrors(err.Error())
+	}
+
+	for i, question := range pack.Questions {
+		pack.Questions[i] = question.WithoutAnswerKey()
+	}
+
+	return *ctx.WithData(pack)
+}
+
 func (q *questionPack) Delete(ID int) appctx.Response {
 	ctx := appctx.NewResponse()
 	if err := q.questionPackRepo.Delete(ID); err != nil {
@@ -155,28 +193,121 @@ func (q *questionPack) DeleteQuestions(param params.QuestionPackAddQuestionParam
 
 func (q *questionPack) TakeQuestionPack(questionPackID, userID int) appctx.Response {
 	ctx := appctx.NewResponse()
+	now := time.Now()
 
-	_, err := q.questionPackRepo.Get(questionPackID)
+	pack, err := q.questionPackRepo.Get(questionPackID)
 	if err != nil {
 		logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
-		return *ctx.WithErrorContexts(err)
+		return *ctx.WithErrorObj(err)
+	}
+
+	// Resume the open attempt when there is one
+	openAttempt, err := q.questionPackAttempRepo.GetOpen(userID, questionPackID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
+	}
+
+	if err == nil {
+		if !openAttempt.IsExpired(now) {
+			return q.takeResponse(openAttempt, now, true)
+		}
+
+		// The worker did not catch it yet, finish it before starting a new one
+		if _, _, err := q.finishAttempt(openAttempt, *openAttempt.Deadline); err != nil && !errors.Is(err, repository.ErrQuestionPackAttemptFinished) {
+			logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
+			return *ctx.WithErrorObj(err)
+		}
+	}
+
+	if resp := q.checkAttemptPolicy(pack, userID, now); resp != nil {
+		return *resp
 	}
 
 	questionPackAttempt := entities.QuestionPackAttempt{
 		UserID:         userID,
 		QuestionPackID: questionPackID,
 		IsFinish:       false,
-		StartedAt:      time.Now(),
+		StartedAt:      now,
 		Score:          0,
 	}
 
+	if pack.TimeLimit > 0 {
+		deadline := questionPackAttempt.StartedAt.Add(time.Duration(pack.TimeLimit) * time.Minute)
+		questionPackAttempt.Deadline = &deadline
+	}
+
 	questionPackAttempt, err = q.questionPackAttempRepo.Create(questionPackAttempt)
 	if err != nil {
+		// Another request opened the attempt concurrently, resume that one
+		if postgres.IsUniqueViolation(err) {
+			if openAttempt, err := q.questionPackAttempRepo.GetOpen(userID, questionPackID); err == nil {
+				return q.takeResponse(openAttempt, now, true)
+			}
+		}
+
 		logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
-		return *ctx.WithErrorContexts(err)
+		return *ctx.WithErrorObj(err)
 	}
 
-	return *ctx.WithData(questionPackAttempt)
+	return q.takeResponse(questionPackAttempt, now, false)
+}
+
+// checkAttemptPolicy enforce maximum number of attempts and cooldown of the pack
+func (q *questionPack) checkAttemptPolicy(pack entities.QuestionPack, userID int, now time.Time) *appctx.Response {
+	if pack.MaxAttempts != nil && *pack.MaxAttempts > 0 {
+		count, err := q.questionPackAttempRepo.CountByUserAndPack(userID, pack.ID)
+		if err != nil {
+			return appctx.NewResponse().WithErrorObj(err)
+		}
+
+		if count >= *pack.MaxAttempts {
+			return appctx.NewResponse().WithErrors("maximum number of attempts has been reached").WithCode(http.StatusForbidden)
+		}
+	}
+
+	if pack.Cooldown != nil && *pack.Cooldown > 0 {
+		lastAttempt, err := q.questionPackAttempRepo.GetLatestFinished(userID, pack.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return appctx.NewResponse().WithErrorObj(err)
+		}
+
+		if err == nil {
+			availableAt := lastAttempt.FinishedAt.Add(time.Duration(*pack.Cooldown) * time.Minute)
+			if now.Before(availableAt) {
+				return appctx.NewResponse().WithErrors(fmt.Sprintf("next attempt is available at %s", availableAt.Format(time.RFC3339))).WithCode(http.StatusForbidden)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *questionPack) takeResponse(attempt entities.QuestionPackAttempt, now time.Time, isResumed bool) appctx.Response {
+	response := params.QuestionPackTakeResponse{
+		QuestionPackAttempt: attempt,
+		Answers:             []entities.UserQuestionAttempt{},
+		IsResumed:           isResumed,
+	}
+
+	if isResumed {
+		answers, err := q.attemptRepo.GetPackAttemptAnswers(attempt.ID)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+		response.Answers = answers
+	}
+
+	if attempt.Deadline != nil {
+		remaining := int(attempt.Deadline.Sub(now).Seconds())
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.RemainingTime = &remaining
+	}
+
+	return *appctx.NewResponse().WithData(response)
 }
 
 func (q *questionPack) FinishQuestionPack(questionPackAttemptID, userID int) appctx.Response {
@@ -184,29 +315,156 @@ func (q *questionPack) FinishQuestionPack(questionPackAttemptID, userID int) app
 
 	questionPackAttempt, err := q.questionPackAttempRepo.Get(questionPackAttemptID)
 	if err != nil {
-		logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
-		return *ctx.WithErrorContexts(err)
+		logrus.Error(fmt.Sprintf("[%s][Finish Question Pack] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
 	}
 
 	if questionPackAttempt.UserID != userID {
 		return *ctx.WithErrors("Invalid user").WithCode(http.StatusBadRequest)
 	}
 
-	questionPackAttempt.IsFinish = true
-	questionPackAttempt.FinishedAt = time.Now()
+	if questionPackAttempt.IsFinish {
+		return *ctx.WithErrors("question pack attempt is already finished").WithCode(http.StatusBadRequest)
+	}
 
-	questionPackAttempt, err = q.questionPackAttempRepo.Update(questionPackAttempt)
+	finishedAt := time.Now()
+	if questionPackAttempt.IsExpired(finishedAt) {
+		finishedAt = *questionPackAttempt.Deadline
+	}
+
+	questionPackAttempt, badges, err := q.finishAttempt(questionPackAttempt, finishedAt)
 	if err != nil {
-		logrus.Error(fmt.Sprintf("[%s][Take Question Pack] %s", q.name, err.Error()))
-		return *ctx.WithErrorContexts(err)
+		logrus.Error(fmt.Sprintf("[%s][Finish Question Pack] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
 	}
 
-	return *ctx.WithData(questionPackAttempt)
+	return *ctx.WithData(params.QuestionPackFinishResponse{QuestionPackAttempt: questionPackAttempt, Badges: badges})
+}
+
+// finishAttempt score every question of the pack from the answers given inside the attempt,
+// then store the score and its breakdown. It returns the badges awarded for finishing.
+func (q *questionPack) finishAttempt(attempt entities.QuestionPackAttempt, finishedAt time.Time) (entities.QuestionPackAttempt, []entities.Badge, error) {
+	pack, err := q.questionPackRepo.Get(attempt.QuestionPackID)
+	if err != nil {
+		return attempt, nil, err
+	}
+
+	questionIDs := make([]int, len(pack.Questions))
+	for i, question := range pack.Questions {
+		questionIDs[i] = question.ID
+	}
+
+	answers := make(map[int]entities.UserQuestionAttempt)
+	userAnswers, err := q.attemptRepo.GetPackAttemptAnswers(attempt.ID)
+	if err != nil {
+		return attempt, nil, err
+	}
+	for _, answer := range userAnswers {
+		answers[answer.QuestionID] = answer
+	}
+
+	options := make(map[int][]entities.QuestionOption)
+	if len(questionIDs) > 0 {
+		questionOptions, err := q.optionRepo.GetByQuestionIDs(questionIDs)
+		if err != nil {
+			return attempt, nil, err
+		}
+		for _, option := range questionOptions {
+			options[option.QuestionID] = append(options[option.QuestionID], option)
+		}
+	}
+
+	rule := pack.ScoringRule()
+	scores := make([]entities.QuestionPackAttemptScore, 0, len(pack.Questions))
+	attempt.Score = 0
+	attempt.MaxScore = 0
+
+	for _, question := range pack.Questions {
+		answer := answers[question.ID]
+		credit := question.Grade(options[question.ID], answer)
+		status := scoring.StatusOf(question.IsAnswered(answer), credit)
+
+		score := entities.QuestionPackAttemptScore{
+			QuestionID:       question.ID,
+			QuestionOptionID: answer.QuestionOptionID,
+			Status:           status,
+			Point:            rule.Score(status, credit),
+		}
+
+		attempt.Score += score.Point
+		attempt.MaxScore += rule.Correct
+		scores = append(scores, score)
+	}
+
+	attempt.IsFinish = true
+	attempt.FinishedAt = finishedAt
+
+	attempt, err = q.questionPackAttempRepo.Finish(attempt, scores)
+	if err != nil {
+		return attempt, nil, err
+	}
+
+	return attempt, q.badges.award(attempt.UserID, entities.BadgeEventPackFinished), nil
+}
+
+// Number of expired attempts loaded at once
+const expiredAttemptPageSize = 100
+
+// FinishExpiredAttempts pages through the expired attempts by ID, an attempt which can not be finished
+// is left for the next run and does not keep the attempts after it from being finished
+func (q *questionPack) FinishExpiredAttempts(now time.Time) (int, error) {
+	finished := 0
+	afterID := 0
+
+	for {
+		attempts, err := q.questionPackAttempRepo.GetExpired(now, afterID, expiredAttemptPageSize)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Finish Expired Attempts] %s", q.name, err.Error()))
+			return finished, err
+		}
+
+		for _, attempt := range attempts {
+			afterID = attempt.ID
+			if _, _, err := q.finishAttempt(attempt, *attempt.Deadline); err != nil {
+				if errors.Is(err, repository.ErrQuestionPackAttemptFinished) {
+					continue
+				}
+				logrus.Error(fmt.Sprintf("[%s][Finish Expired Attempts] attempt %d: %s", q.name, attempt.ID, err.Error()))
+				continue
+			}
+			finished++
+		}
+
+		if len(attempts) < expiredAttemptPageSize {
+			return finished, nil
+		}
+	}
 }
 
 func (q *questionPack) GetAttemptList(param params.QuestionPackAttemptFilterParam) appctx.Response {
 	ctx := appctx.NewResponse()
 
+	if param.IsCursor() {
+		questionPackAttempts, next, err := q.questionPackAttempRepo.GetListByCursor(param)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Get Attempt List] %s", q.name, err.Error()))
+			return *ctx.WithErrorObj(err)
+		}
+
+		var total *int64
+		if param.WithCount {
+			count, err := q.questionPackAttempRepo.Count(param)
+			if err != nil {
+				logrus.Error(fmt.Sprintf("[%s][Get Attempt List] %s", q.name, err.Error()))
+				return *ctx.WithErrorObj(err)
+			}
+			c := int64(count)
+			total = &c
+		}
+
+		return *ctx.WithData(questionPackAttempts).WithCursorMeta(int64(cursor.Limit(param.Limit)), param.Cursor, next, total)
+	}
+
 	questionPackAttempts, count, err := q.questionPackAttempRepo.GetList(param)
 	if err != nil {
 		logrus.Error(fmt.Sprintf("[%s][Get Attempt List] %s", q.name, err.Error()))
@@ -216,24 +474,142 @@ func (q *questionPack) GetAttemptList(param params.QuestionPackAttemptFilterPara
 	return *ctx.WithData(questionPackAttempts).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
 }
 
-// func (q *questionPack) GetQuestionPackAttemptDetail(QuestionPackAttemptID int) appctx.Response {
-// 	ctx := appctx.NewResponse()
+func (q *questionPack) GetAttempt(questionPackAttemptID int) appctx.Response {
+	ctx := appctx.NewResponse()
 
-// 	questionPackAttempt, err := q.questionPackAttempRepo.Get(QuestionPackAttemptID)
-// 	if err != nil {
-// 		logrus.Error(fmt.Sprintf("[%s][Get Attempt List] %s", q.name, err.Error()))
-// 		return *ctx.WithErrorObj(err)
-// 	}
+	questionPackAttempt, err := q.questionPackAttempRepo.GetWithScores(questionPackAttemptID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get Attempt] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
+	}
 
-// 	// Get question pack
-// 	questionPack, err := q.questionPackRepo.Get(questionPackAttempt.QuestionPackID)
-// 	if err != nil {
-// 		logrus.Error(fmt.Sprintf("[%s][Get Attempt List] %s", q.name, err.Error()))
-// 		return *ctx.WithErrorObj(err)
-// 	}
+	return *ctx.WithData(questionPackAttempt)
+}
 
-// 	// Get question option
-// 	// Get question attempt
+func (q *questionPack) GetQuestionPackAttemptDetail(questionPackAttemptID, userID int) appctx.Response {
+	ctx := appctx.NewResponse()
 
-// 	// return
-// }
+	questionPackAttempt, err := q.questionPackAttempRepo.GetWithScores(questionPackAttemptID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get Attempt Detail] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
+	}
+
+	if questionPackAttempt.UserID != userID {
+		return *ctx.WithErrors("Invalid user").WithCode(http.StatusForbidden)
+	}
+
+	if !questionPackAttempt.IsFinish {
+		return *ctx.WithErrors("question pack attempt is not finished yet").WithCode(http.StatusForbidden)
+	}
+
+	// Get question pack
+	questionPack, err := q.questionPackRepo.Get(questionPackAttempt.QuestionPackID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get Attempt Detail] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
+	}
+
+	questions := questionPack.Questions
+	questionPack.Questions = nil
+
+	questionIDs := make([]int, len(questions))
+	for i, question := range questions {
+		questionIDs[i] = question.ID
+	}
+
+	options := make(map[int][]entities.QuestionOption)
+	solutions := make(map[int]entities.QuestionSolution)
+	if len(questionIDs) > 0 {
+		// Get question option
+		questionOptions, err := q.optionRepo.GetByQuestionIDs(questionIDs)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Get Attempt Detail] %s", q.name, err.Error()))
+			return *ctx.WithErrorObj(err)
+		}
+		for _, option := range questionOptions {
+			option.GenerateTempStorageUrl(q.minio)
+			options[option.QuestionID] = append(options[option.QuestionID], option)
+		}
+
+		// Get question solution
+		questionSolutions, err := q.solutionRepo.GetByQuestionIDs(questionIDs)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][Get Attempt Detail] %s", q.name, err.Error()))
+			return *ctx.WithErrorObj(err)
+		}
+		// Premium only solutions are hidden for user without active premium package
+		_, err = q.premiumRepo.GetActiveByUser(userID, time.Now())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.Error(fmt.Sprintf("[%s][Get Attempt Detail] %s", q.name, err.Error()))
+			return *ctx.WithErrorObj(err)
+		}
+		isPremium := err == nil
+
+		for _, solution := range questionSolutions {
+			if !isPremium && solution.IsPremium != nil && *solution.IsPremium {
+				continue
+			}
+			solution.GenerateTempStorageUrl(q.minio)
+			solutions[solution.QuestionID] = solution
+		}
+	}
+
+	// Get question attempt
+	answers, err := q.attemptRepo.GetPackAttemptAnswers(questionPackAttempt.ID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Get Attempt Detail] %s", q.name, err.Error()))
+		return *ctx.WithErrorObj(err)
+	}
+	answerOf := make(map[int]entities.UserQuestionAttempt)
+	for _, answer := range answers {
+		answerOf[answer.QuestionID] = answer
+	}
+
+	scores := make(map[int]entities.QuestionPackAttemptScore)
+	for _, score := range questionPackAttempt.Scores {
+		scores[score.QuestionID] = score
+	}
+	questionPackAttempt.Scores = nil
+
+	details := make([]params.QuestionPackAttemptQuestionDetail, 0, len(questions))
+	for _, question := range questions {
+		question.GenerateTempStorageUrl(q.minio)
+		question.QuestionOptions = options[question.ID]
+
+		score := scores[question.ID]
+		answer, answered := answerOf[question.ID]
+		detail := params.QuestionPackAttemptQuestionDetail{
+			Question:  question,
+			AnswerID:  score.QuestionOptionID,
+			Status:    score.Status,
+			Point:     score.Point,
+			TimeSpent: answer.TimeSpent,
+		}
+
+		trueOptionIDs := question.TrueOptionIDs(question.QuestionOptions)
+		switch question.QuestionType() {
+		case entities.QuestionTypeSingleChoice, entities.QuestionTypeTrueFalse:
+			if len(trueOptionIDs) > 0 {
+				detail.TrueAnswerID = trueOptionIDs[0]
+			}
+		default:
+			detail.TrueAnswerIDs = trueOptionIDs
+			if answered {
+				detail.Answer = &answer
+			}
+		}
+
+		if solution, ok := solutions[question.ID]; ok {
+			detail.Solution = &solution
+		}
+
+		details = append(details, detail)
+	}
+
+	return *ctx.WithData(params.QuestionPackAttemptDetailResponse{
+		Attempt:      questionPackAttempt,
+		QuestionPack: questionPack,
+		Questions:    details,
+	})
+}
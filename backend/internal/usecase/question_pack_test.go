@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/boolpointer"
+)
+
+type fakeQuestionPackRepo struct {
+	repository.QuestionPackRepository
+	pack entities.QuestionPack
+}
+
+func (f *fakeQuestionPackRepo) Get(ID int) (entities.QuestionPack, error) {
+	return f.pack, nil
+}
+
+func TestStudentDetailHidesAnswerKey(t *testing.T) {
+	numericAnswer := 42.5
+	position := 1
+	pack := entities.QuestionPack{ID: 1, Questions: []entities.Question{
+		{ID: 1, Type: entities.QuestionTypeNumeric, NumericAnswer: &numericAnswer},
+		{ID: 2, Type: entities.QuestionTypeShortText, AcceptedAnswers: []string{"photosynthesis"}},
+		{ID: 3, Type: entities.QuestionTypeOrdering, QuestionOptions: []entities.QuestionOption{
+			{ID: 30, Body: "first", OptionValue: boolpointer.BoolPointer(true), Position: &position, MatchBody: "pair"},
+		}},
+	}}
+	u := &questionPack{questionPackRepo: &fakeQuestionPackRepo{pack: pack}, name: "Question Pack Usecase"}
+
+	resp := u.StudentDetail(1)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("detail failed: %v", resp.Errors)
+	}
+
+	body, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"numeric_answer", "accepted_answers", "photosynthesis", "42.5", "option_value\":true", "position", "match_body"} {
+		if strings.Contains(string(body), key) {
+			t.Errorf("expected %s to be hidden from the student, got %s", key, body)
+		}
+	}
+}
+
+func (f *fakeAttemptRepo) GetPackAttemptAnswers(packAttemptID int) ([]entities.UserQuestionAttempt, error) {
+	return nil, nil
+}
+
+// fakeExpiredAttemptRepo can not finish the attempts in failing
+type fakeExpiredAttemptRepo struct {
+	repository.QuestionPackAttemptRepository
+	attempts []entities.QuestionPackAttempt
+	failing  map[int]bool
+}
+
+func (f *fakeExpiredAttemptRepo) GetExpired(now time.Time, afterID, limit int) ([]entities.QuestionPackAttempt, error) {
+	attempts := []entities.QuestionPackAttempt{}
+	for _, attempt := range f.attempts {
+		if !attempt.IsFinish && attempt.Deadline.Before(now) && attempt.ID > afterID && len(attempts) < limit {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+func (f *fakeExpiredAttemptRepo) Finish(attempt entities.QuestionPackAttempt, scores []entities.QuestionPackAttemptScore) (entities.QuestionPackAttempt, error) {
+	if f.failing[attempt.ID] {
+		return attempt, errors.New("attempt can not be finished")
+	}
+	f.attempts[attempt.ID-1] = attempt
+	return attempt, nil
+}
+
+func TestFinishExpiredAttemptsPastAttemptsWhichFail(t *testing.T) {
+	deadline := time.Now().Add(-time.Hour)
+	attempts := &fakeExpiredAttemptRepo{failing: map[int]bool{}}
+	for ID := 1; ID <= expiredAttemptPageSize+50; ID++ {
+		attempts.attempts = append(attempts.attempts, entities.QuestionPackAttempt{ID: ID, QuestionPackID: 1, Deadline: &deadline})
+		// The whole first page fails
+		if ID <= expiredAttemptPageSize {
+			attempts.failing[ID] = true
+		}
+	}
+	u := &questionPack{
+		questionPackRepo:       &fakeQuestionPackRepo{pack: entities.QuestionPack{ID: 1}},
+		questionPackAttempRepo: attempts,
+		attemptRepo:            &fakeAttemptRepo{},
+		badges:                 badgeAwarder{badgeRepo: &fakeBadgeRepo{}},
+		name:                   "Question Pack Usecase",
+	}
+
+	finished, err := u.FinishExpiredAttempts(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if finished != 50 {
+		t.Errorf("expected the 50 attempts after the failing ones to be finished, got %d", finished)
+	}
+	for _, attempt := range attempts.attempts[expiredAttemptPageSize:] {
+		if !attempt.IsFinish {
+			t.Errorf("expected attempt %d to be finished", attempt.ID)
+		}
+	}
+}
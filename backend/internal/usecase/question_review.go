@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotQuestionOwner     = errors.New("question does not belong to the contributor")
+	ErrNotQuestionReviewer  = errors.New("question is assigned to another reviewer")
+	ErrReviewerNotAdmin     = errors.New("reviewer must be an admin")
+	ErrRejectionReasonEmpty = errors.New("rejection reason is required")
+)
+
+type questionReview struct {
+	questionRepo repository.QuestionRepository
+	reviewRepo   repository.QuestionReviewRepository
+	userRepo     repository.UserRepository
+	name         string
+}
+
+type QuestionReviewUsecase interface {
+	// Contributor submits a draft or rejected question for review
+	Submit(questionID, userID int) appctx.Response
+	// Contributor takes a submitted question back to draft
+	Withdraw(questionID, userID int) appctx.Response
+	// Assign a reviewer to a submitted question
+	Assign(param params.QuestionReviewAssignParam) appctx.Response
+	// Reviewer approves and activates the question
+	Approve(param params.QuestionReviewDecisionParam) appctx.Response
+	// Reviewer rejects the question with a reason
+	Reject(param params.QuestionReviewDecisionParam) appctx.Response
+	// Add reviewer comment to the question
+	Comment(param params.QuestionReviewCommentParam) appctx.Response
+	// Get review status, comments and audit trail, contributorID is 0 for admin
+	Detail(questionID, contributorID int) appctx.Response
+	// List questions waiting for review
+	Queue(param params.QuestionReviewQueueParam) appctx.Response
+}
+
+func NewQuestionReviewUsecase(db *gorm.DB) QuestionReviewUsecase {
+	return &questionReview{
+		questionRepo: repository.NewQuestionRepository(db, nil),
+		reviewRepo:   repository.NewQuestionReviewRepository(db),
+		userRepo:     repository.NewUserRepository(db),
+		name:         "Question Review Usecase",
+	}
+}
+
+func (q *questionReview) Submit(questionID, userID int) appctx.Response {
+	question, resp := q.ownedQuestion(questionID, userID)
+	if resp != nil {
+		return *resp
+	}
+
+	now := time.Now()
+	return q.transition(question, userID, entities.ReviewActionSubmit, "", map[string]interface{}{
+		"submitted_at":     now,
+		"rejection_reason": "",
+		"is_active":        false,
+	})
+}
+
+func (q *questionReview) Withdraw(questionID, userID int) appctx.Response {
+	question, resp := q.ownedQuestion(questionID, userID)
+	if resp != nil {
+		return *resp
+	}
+
+	return q.transition(question, userID, entities.ReviewActionWithdraw, "", map[string]interface{}{
+		"submitted_at": nil,
+	})
+}
+
+func (q *questionReview) Assign(param params.QuestionReviewAssignParam) appctx.Response {
+	question, err := q.questionRepo.GetAnswerKey(param.QuestionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if param.ReviewerID == 0 {
+		param.ReviewerID = param.ActorID
+	}
+
+	reviewer, err := q.userRepo.Get(entities.User{}, param.ReviewerID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+	if !hasRole(reviewer, entities.RoleAdmin) {
+		return *appctx.NewResponse().WithErrors(ErrReviewerNotAdmin.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	note := strings.TrimSpace(fmt.Sprintf("assigned to user %d. %s", reviewer.ID, param.Note))
+	return q.transition(question, param.ActorID, entities.ReviewActionAssign, note, map[string]interface{}{
+		"reviewer_id": reviewer.ID,
+	})
+}
+
+func (q *questionReview) Approve(param params.QuestionReviewDecisionParam) appctx.Response {
+	question, resp := q.reviewedQuestion(param.QuestionID, param.ActorID)
+	if resp != nil {
+		return *resp
+	}
+
+	return q.transition(question, param.ActorID, entities.ReviewActionApprove, param.Note, map[string]interface{}{
+		"is_active":        true,
+		"rejection_reason": "",
+		"reviewed_at":      time.Now(),
+	})
+}
+
+func (q *questionReview) Reject(param params.QuestionReviewDecisionParam) appctx.Response {
+	reason := strings.TrimSpace(param.Reason)
+	if reason == "" {
+		return *appctx.NewResponse().WithErrors(ErrRejectionReasonEmpty.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	question, resp := q.reviewedQuestion(param.QuestionID, param.ActorID)
+	if resp != nil {
+		return *resp
+	}
+
+	note := param.Note
+	if note == "" {
+		note = reason
+	}
+
+	return q.transition(question, param.ActorID, entities.ReviewActionReject, note, map[string]interface{}{
+		"is_active":        false,
+		"rejection_reason": reason,
+		"reviewed_at":      time.Now(),
+	})
+}
+
+func (q *questionReview) Comment(param params.QuestionReviewCommentParam) appctx.Response {
+	question, err := q.questionRepo.GetAnswerKey(param.QuestionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if param.QuestionOptionID != nil {
+		found := false
+		for _, option := range question.QuestionOptions {
+			if option.ID == *param.QuestionOptionID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return *appctx.NewResponse().WithErrors("option does not belong to the question").WithCode(http.StatusBadRequest)
+		}
+	}
+
+	comment, err := q.reviewRepo.AddComment(entities.QuestionReviewComment{
+		QuestionID:       question.ID,
+		UserID:           param.UserID,
+		Field:            param.Field,
+		QuestionOptionID: param.QuestionOptionID,
+		Body:             param.Body,
+	})
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(comment)
+}
+
+func (q *questionReview) Detail(questionID, contributorID int) appctx.Response {
+	var (
+		question entities.Question
+		resp     *appctx.Response
+		err      error
+	)
+	if contributorID != 0 {
+		question, resp = q.ownedQuestion(questionID, contributorID)
+		if resp != nil {
+			return *resp
+		}
+	} else {
+		question, err = q.questionRepo.GetAnswerKey(questionID)
+		if err != nil {
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+	}
+
+	comments, err := q.reviewRepo.ListComments(question.ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	logs, err := q.reviewRepo.ListLogs(question.ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(params.QuestionReviewDetailResponse{
+		QuestionID:      question.ID,
+		ReviewStatus:    question.ReviewStatusOf(),
+		ReviewerID:      question.ReviewerID,
+		RejectionReason: question.RejectionReason,
+		Comments:        comments,
+		Logs:            logs,
+	})
+}
+
+func (q *questionReview) Queue(param params.QuestionReviewQueueParam) appctx.Response {
+	items, count, err := q.reviewRepo.Queue(param)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(items).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
+}
+
+// ownedQuestion gets the question and makes sure it was created by the contributor
+func (q *questionReview) ownedQuestion(questionID, userID int) (entities.Question, *appctx.Response) {
+	question, err := q.questionRepo.GetAnswerKey(questionID)
+	if err != nil {
+		return question, appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if question.ContributorID != userID {
+		return question, appctx.NewResponse().WithErrors(ErrNotQuestionOwner.Error()).WithCode(http.StatusForbidden)
+	}
+
+	return question, nil
+}
+
+// reviewedQuestion gets the question and makes sure the actor is its reviewer
+func (q *questionReview) reviewedQuestion(questionID, actorID int) (entities.Question, *appctx.Response) {
+	question, err := q.questionRepo.GetAnswerKey(questionID)
+	if err != nil {
+		return question, appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if question.ReviewerID == nil || *question.ReviewerID != actorID {
+		return question, appctx.NewResponse().WithErrors(ErrNotQuestionReviewer.Error()).WithCode(http.StatusForbidden)
+	}
+
+	return question, nil
+}
+
+func (q *questionReview) transition(question entities.Question, actorID int, action, note string, changes map[string]interface{}) appctx.Response {
+	from := question.ReviewStatusOf()
+	to, err := question.NextReviewStatus(action)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusConflict)
+	}
+
+	changes["review_status"] = to
+	log, err := q.reviewRepo.Transition(question.ID, from, changes, entities.QuestionReviewLog{
+		QuestionID: question.ID,
+		ActorID:    actorID,
+		Action:     action,
+		FromStatus: from,
+		ToStatus:   to,
+		Note:       note,
+	})
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Transition] %s", q.name, err.Error()))
+		if errors.Is(err, repository.ErrReviewStatusChanged) {
+			return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusConflict)
+		}
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(log).WithMessage(fmt.Sprintf("Question moved to %s", to))
+}
+
+func hasRole(user entities.User, name string) bool {
+	for _, role := range user.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
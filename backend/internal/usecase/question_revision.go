@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+type questionRevision struct {
+	revisionRepo repository.QuestionRevisionRepository
+	name         string
+}
+
+type QuestionRevisionUsecase interface {
+	// List revisions of a question
+	List(param params.QuestionRevisionFilterParam) appctx.Response
+	// Get revision with its snapshot
+	Detail(questionID, revisionID int) appctx.Response
+	// Compare two revisions of a question
+	Diff(param params.QuestionRevisionDiffParam) appctx.Response
+	// Roll question back to the revision
+	Rollback(questionID, revisionID, actorID int) appctx.Response
+}
+
+func NewQuestionRevisionUsecase(db *gorm.DB) QuestionRevisionUsecase {
+	return &questionRevision{
+		revisionRepo: repository.NewQuestionRevisionRepository(db),
+		name:         "Question Revision Usecase",
+	}
+}
+
+func (q *questionRevision) List(param params.QuestionRevisionFilterParam) appctx.Response {
+	revisions, count, err := q.revisionRepo.List(param.QuestionID, param.Page, param.Limit)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(revisions).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
+}
+
+func (q *questionRevision) Detail(questionID, revisionID int) appctx.Response {
+	revision, err := q.revisionRepo.Get(questionID, revisionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(revision)
+}
+
+func (q *questionRevision) Diff(param params.QuestionRevisionDiffParam) appctx.Response {
+	from, err := q.revisionRepo.Get(param.QuestionID, param.From)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	var to entities.QuestionRevision
+	if param.To != 0 {
+		to, err = q.revisionRepo.Get(param.QuestionID, param.To)
+		if err != nil {
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+	} else {
+		latest, _, err := q.revisionRepo.List(param.QuestionID, 1, 1)
+		if err != nil {
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+		if len(latest) == 0 {
+			return *appctx.NewResponse().WithErrorObj(gorm.ErrRecordNotFound)
+		}
+		to = latest[0]
+	}
+
+	changes := from.Snapshot.Diff(to.Snapshot)
+	if changes == nil {
+		changes = []entities.QuestionRevisionChange{}
+	}
+
+	return *appctx.NewResponse().WithData(params.QuestionRevisionDiffResponse{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	})
+}
+
+func (q *questionRevision) Rollback(questionID, revisionID, actorID int) appctx.Response {
+	revision, err := q.revisionRepo.Get(questionID, revisionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	restored, err := q.revisionRepo.Restore(revision, actorID)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Rollback] %s", q.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(restored).WithMessage(fmt.Sprintf("Question rolled back to revision %d", revision.Revision))
+}
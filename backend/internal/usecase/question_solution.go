@@ -22,6 +22,7 @@ import (
 
 type questionSolution struct {
 	questionSolutionRepo repository.QuestionSolutionRepository
+	revisionRepo         repository.QuestionRevisionRepository
 	name                 string
 	minio                minio.MinioStorageContract
 }
@@ -37,7 +38,7 @@ type QuestionSolutionUsecase interface {
 	// Get detail question tag
 	Detail(ID int) appctx.Response
 	// Delete question tag
-	Delete(ID int) appctx.Response
+	Delete(ID, actorID int) appctx.Response
 	// // Assign Role to user
 	// Assign(userID int, roleName string) appctx.Response
 	// // Revoke Role from user
@@ -47,6 +48,7 @@ type QuestionSolutionUsecase interface {
 func NewQuestionSolutionUsecase(db *gorm.DB, minio minio.MinioStorageContract) QuestionSolutionUsecase {
 	return &questionSolution{
 		questionSolutionRepo: repository.NewQuestionSolutionRepository(db),
+		revisionRepo:         repository.NewQuestionRevisionRepository(db),
 		name:                 "Question Solution Usecase",
 		minio:                minio,
 	}
@@ -71,7 +73,11 @@ func (q *questionSolution) Create(param params.QuestionSolutionCreate) appctx.Re
 	// }
 	copier.Copy(&solution, &param)
 
-	data, err := q.questionSolutionRepo.Create(solution)
+	var data entities.QuestionSolution
+	_, err = q.revisionRepo.Revise(param.QuestionID, param.ActorID, entities.RevisionActionSolution, func(tx *gorm.DB) error {
+		data, err = q.questionSolutionRepo.WithTx(tx).Create(solution)
+		return err
+	})
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Create] %s", q.name, err.Error()))
 		if _, ok := err.(*e.ValueValidationError); ok {
@@ -127,7 +133,10 @@ func (q *questionSolution) CreateWithFile(param params.QuestionSolutionWithFileU
 		solution.PdfFileUrl = <-path
 	}
 
-	solution, err = q.questionSolutionRepo.Create(solution)
+	_, err = q.revisionRepo.Revise(param.QuestionID, param.ActorID, entities.RevisionActionSolution, func(tx *gorm.DB) error {
+		solution, err = q.questionSolutionRepo.WithTx(tx).Create(solution)
+		return err
+	})
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Create] %s", q.name, err.Error()))
 		if _, ok := err.(*e.ValueValidationError); ok {
@@ -153,7 +162,10 @@ func (q *questionSolution) Update(param params.QuestionSolutionUpdate) appctx.Re
 	logrus.Debug(param)
 	copier.Copy(&solution, &param)
 
-	solution, err = q.questionSolutionRepo.Update(solution)
+	_, err = q.revisionRepo.Revise(solution.QuestionID, param.ActorID, entities.RevisionActionSolution, func(tx *gorm.DB) error {
+		solution, err = q.questionSolutionRepo.WithTx(tx).Update(solution)
+		return err
+	})
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Update] %s", q.name, err.Error()))
 		return *appctx.NewResponse().WithErrors(err.Error())
@@ -208,11 +220,19 @@ func (q *questionSolution) Detail(ID int) appctx.Response {
 	return *appctx.NewResponse().WithData(solution)
 }
 
-func (q *questionSolution) Delete(ID int) appctx.Response {
+func (q *questionSolution) Delete(ID, actorID int) appctx.Response {
 	log.Info(fmt.Sprintf("[%s][Delete] is executed", q.name))
 
+	solution, err := q.questionSolutionRepo.GetByID(ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("[%s][Delete] %s", q.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
 	// delete question tag
-	err := q.questionSolutionRepo.Delete(ID)
+	_, err = q.revisionRepo.Revise(solution.QuestionID, actorID, entities.RevisionActionSolution, func(tx *gorm.DB) error {
+		return q.questionSolutionRepo.WithTx(tx).Delete(ID)
+	})
 	if err != nil {
 		log.Error(fmt.Sprintf("[%s][Delete] %s", q.name, err.Error()))
 		return *appctx.NewResponse().WithErrorObj(err)
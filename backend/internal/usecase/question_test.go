@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gorm.io/gorm"
+)
+
+func (f *fakeQuestionRepo) Get(ID int) (entities.Question, error) {
+	return f.question, nil
+}
+
+func (f *fakeQuestionRepo) WithTx(tx *gorm.DB) repository.QuestionRepository {
+	return f
+}
+
+func (f *fakeQuestionRepo) AddTag(question entities.Question, tags []entities.QuestionTag) (entities.Question, error) {
+	f.question.QuestionTags = append(f.question.QuestionTags, tags...)
+	return f.question, nil
+}
+
+func (f *fakeQuestionRepo) RemoveTag(question entities.Question, tag entities.QuestionTag) (entities.Question, error) {
+	tags := []entities.QuestionTag{}
+	for _, current := range f.question.QuestionTags {
+		if current.ID != tag.ID {
+			tags = append(tags, current)
+		}
+	}
+	f.question.QuestionTags = tags
+	return f.question, nil
+}
+
+// fakeOptionRepo keeps the options inside the question of the question repository
+type fakeOptionRepo struct {
+	repository.QuestionOptionRepository
+	questions *fakeQuestionRepo
+}
+
+func (f *fakeOptionRepo) WithTx(tx *gorm.DB) repository.QuestionOptionRepository {
+	return f
+}
+
+func (f *fakeOptionRepo) Get(ID int) (entities.QuestionOption, error) {
+	for _, option := range f.questions.question.QuestionOptions {
+		if option.ID == ID {
+			return option, nil
+		}
+	}
+	return entities.QuestionOption{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOptionRepo) Create(option entities.QuestionOption) (entities.QuestionOption, error) {
+	option.ID = 100 + len(f.questions.question.QuestionOptions)
+	f.questions.question.QuestionOptions = append(f.questions.question.QuestionOptions, option)
+	return option, nil
+}
+
+func (f *fakeOptionRepo) Update(option entities.QuestionOption) (entities.QuestionOption, error) {
+	for i, current := range f.questions.question.QuestionOptions {
+		if current.ID == option.ID {
+			f.questions.question.QuestionOptions[i] = option
+		}
+	}
+	return option, nil
+}
+
+func (f *fakeOptionRepo) Delete(ID int) (bool, error) {
+	options := []entities.QuestionOption{}
+	for _, option := range f.questions.question.QuestionOptions {
+		if option.ID != ID {
+			options = append(options, option)
+		}
+	}
+	f.questions.question.QuestionOptions = options
+	return true, nil
+}
+
+type fakeTagRepo struct {
+	repository.QuestionTagRepository
+	created []entities.QuestionTag
+}
+
+func (f *fakeTagRepo) ListIn(IDs []int) ([]entities.QuestionTag, int, error) {
+	tags := []entities.QuestionTag{}
+	for _, ID := range IDs {
+		tags = append(tags, entities.QuestionTag{ID: ID})
+	}
+	return tags, len(tags), nil
+}
+
+func (f *fakeTagRepo) Get(ID int) (entities.QuestionTag, error) {
+	return entities.QuestionTag{ID: ID}, nil
+}
+
+func (f *fakeTagRepo) ListByNames(names []string) ([]entities.QuestionTag, error) {
+	return []entities.QuestionTag{{ID: 3, Name: "Algebra"}}, nil
+}
+
+func (f *fakeTagRepo) Create(tag entities.QuestionTag) (entities.QuestionTag, error) {
+	f.created = append(f.created, tag)
+	return tag, nil
+}
+
+// fakeRevisionRepo fails every revision with err, the change is not made as if it was rolled back
+type fakeRevisionRepo struct {
+	repository.QuestionRevisionRepository
+	err error
+}
+
+func (f *fakeRevisionRepo) Record(questionID, actorID int, action string) (entities.QuestionRevision, error) {
+	return entities.QuestionRevision{QuestionID: questionID}, f.err
+}
+
+func (f *fakeRevisionRepo) Revise(questionID, actorID int, action string, change func(tx *gorm.DB) error) (entities.QuestionRevision, error) {
+	if f.err != nil {
+		return entities.QuestionRevision{}, f.err
+	}
+	return entities.QuestionRevision{QuestionID: questionID, Action: action}, change(nil)
+}
+
+func newQuestionUsecaseWithFakes(current entities.Question) (*question, *fakeQuestionRepo) {
+	// Options are copied so the changes do not reach the question of the test
+	current.QuestionOptions = append([]entities.QuestionOption{}, current.QuestionOptions...)
+	questions := &fakeQuestionRepo{question: current}
+	return &question{
+		questionRepo: questions,
+		optionRepo:   &fakeOptionRepo{questions: questions},
+		tagRepo:      &fakeTagRepo{},
+		revisionRepo: &fakeRevisionRepo{},
+		name:         "Question Usecase",
+	}, questions
+}
+
+// contributorRoutes call every contributor path which changes options or tags of question 1
+var contributorRoutes = map[string]func(u *question, contributorID int) appctx.Response{
+	"POST /option": func(u *question, contributorID int) appctx.Response {
+		return u.AddOptionByContributor(params.QuestionOptionAdd{QuestionID: 1, Body: "another", ActorID: contributorID, ContributorID: contributorID})
+	},
+	"PUT /option/{id}": func(u *question, contributorID int) appctx.Response {
+		return u.UpdateOptionByContributor(params.QuestionOptionUpdate{ID: 11, QuestionID: 1, Body: "changed", ActorID: contributorID, ContributorID: contributorID})
+	},
+	"DELETE /option/{id}": func(u *question, contributorID int) appctx.Response {
+		return u.DeleteOptionByContributor(11, contributorID)
+	},
+	"POST /tags": func(u *question, contributorID int) appctx.Response {
+		return u.AddTagsByContributor(params.QuestionAddTags{QuestionID: 1, TagIDs: []int{3}, ContributorID: contributorID})
+	},
+	"POST /tags/remove": func(u *question, contributorID int) appctx.Response {
+		return u.RemoveTagByContributor(params.QuestionRemoveTag{QuestionID: 1, TagID: 3, ContributorID: contributorID})
+	},
+}
+
+func contributorQuestion(status string) entities.Question {
+	question := singleChoiceQuestion(1)
+	question.ContributorID = 5
+	question.ReviewStatus = status
+	question.QuestionTags = []entities.QuestionTag{{ID: 3}}
+	return question
+}
+
+func TestContributorCanNotChangeQuestionUnderModeration(t *testing.T) {
+	for route, call := range contributorRoutes {
+		for _, status := range []string{entities.ReviewStatusSubmitted, entities.ReviewStatusInReview, entities.ReviewStatusApproved} {
+			question := contributorQuestion(status)
+			u, questions := newQuestionUsecaseWithFakes(question)
+
+			resp := call(u, question.ContributorID)
+			if resp.Code != http.StatusConflict {
+				t.Errorf("%s: expected %s question to be rejected with %d, got %d", route, status, http.StatusConflict, resp.Code)
+			}
+			if len(questions.question.QuestionOptions) != len(question.QuestionOptions) || questions.question.QuestionOptions[1].Body != question.QuestionOptions[1].Body ||
+				len(questions.question.QuestionTags) != len(question.QuestionTags) {
+				t.Errorf("%s: expected %s question to stay unchanged", route, status)
+			}
+		}
+	}
+}
+
+func TestContributorChangesEditableQuestion(t *testing.T) {
+	for route, call := range contributorRoutes {
+		for _, status := range []string{entities.ReviewStatusDraft, entities.ReviewStatusRejected} {
+			question := contributorQuestion(status)
+			u, _ := newQuestionUsecaseWithFakes(question)
+
+			if resp := call(u, question.ContributorID); resp.Code != http.StatusOK {
+				t.Errorf("%s: expected %s question to be changed, got %d %v", route, status, resp.Code, resp.Errors)
+			}
+		}
+	}
+}
+
+func TestContributorCanNotChangeQuestionOfAnother(t *testing.T) {
+	for route, call := range contributorRoutes {
+		u, _ := newQuestionUsecaseWithFakes(contributorQuestion(entities.ReviewStatusDraft))
+
+		if resp := call(u, 6); resp.Code != http.StatusForbidden {
+			t.Errorf("%s: expected question of another contributor to be forbidden, got %d", route, resp.Code)
+		}
+	}
+}
+
+func TestOptionChangesKeepAnswerKeyOfQuestionType(t *testing.T) {
+	changes := map[string]func(u *question) appctx.Response{
+		"add second true option": func(u *question) appctx.Response {
+			return u.AddOption(params.QuestionOptionAdd{QuestionID: 1, Body: "also true", OptionValue: true})
+		},
+		"update true option to false": func(u *question) appctx.Response {
+			return u.UpdateOption(params.QuestionOptionUpdate{ID: 10, QuestionID: 1, Body: "no longer true"})
+		},
+		"update false option to true": func(u *question) appctx.Response {
+			return u.UpdateOption(params.QuestionOptionUpdate{ID: 11, QuestionID: 1, Body: "now true", OptionValue: true})
+		},
+		"delete true option": func(u *question) appctx.Response {
+			return u.DeleteOption(10, 0)
+		},
+		"update question with second true option": func(u *question) appctx.Response {
+			return u.Update(params.QuestionUpdate{ID: 1, QuestionOptions: []params.QuestionOptionUpdate{{ID: 11, Body: "now true", OptionValue: true}}})
+		},
+		"update question with new true option": func(u *question) appctx.Response {
+			return u.Update(params.QuestionUpdate{ID: 1, QuestionOptions: []params.QuestionOptionUpdate{{Body: "another true", OptionValue: true}}})
+		},
+	}
+
+	for name, change := range changes {
+		u, questions := newQuestionUsecaseWithFakes(singleChoiceQuestion(1))
+
+		if resp := change(u); resp.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected single choice question to reject the change, got %d", name, resp.Code)
+		}
+		if len(questions.question.QuestionOptions) != 2 || *questions.question.QuestionOptions[0].OptionValue != true || *questions.question.QuestionOptions[1].OptionValue != false {
+			t.Errorf("%s: expected options to stay unchanged, got %+v", name, questions.question.QuestionOptions)
+		}
+	}
+}
+
+func TestOptionChangesValidForQuestionType(t *testing.T) {
+	u, questions := newQuestionUsecaseWithFakes(singleChoiceQuestion(1))
+
+	if resp := u.UpdateOption(params.QuestionOptionUpdate{ID: 10, QuestionID: 1, Body: "still true", OptionValue: true}); resp.Code != http.StatusOK {
+		t.Fatalf("expected true option to be updated, got %d %v", resp.Code, resp.Errors)
+	}
+	if resp := u.AddOption(params.QuestionOptionAdd{QuestionID: 1, Body: "another false"}); resp.Code != http.StatusOK {
+		t.Fatalf("expected false option to be added, got %d %v", resp.Code, resp.Errors)
+	}
+	if resp := u.DeleteOption(11, 0); resp.Code != http.StatusOK {
+		t.Fatalf("expected false option to be deleted, got %d %v", resp.Code, resp.Errors)
+	}
+
+	if len(questions.question.QuestionOptions) != 2 {
+		t.Errorf("expected two options to be left, got %+v", questions.question.QuestionOptions)
+	}
+}
+
+func TestOptionChangesFailWhenRevisionCanNotBeRecorded(t *testing.T) {
+	changes := map[string]func(u *question) appctx.Response{
+		"add option": func(u *question) appctx.Response {
+			return u.AddOption(params.QuestionOptionAdd{QuestionID: 1, Body: "another false"})
+		},
+		"update option": func(u *question) appctx.Response {
+			return u.UpdateOption(params.QuestionOptionUpdate{ID: 11, QuestionID: 1, Body: "changed"})
+		},
+		"delete option": func(u *question) appctx.Response {
+			return u.DeleteOption(11, 0)
+		},
+	}
+
+	for name, change := range changes {
+		u, questions := newQuestionUsecaseWithFakes(singleChoiceQuestion(1))
+		u.revisionRepo = &fakeRevisionRepo{err: errors.New("revision can not be recorded")}
+
+		if resp := change(u); resp.Code == http.StatusOK {
+			t.Errorf("%s: expected the change to fail when its revision can not be recorded", name)
+		}
+		if len(questions.question.QuestionOptions) != 2 || questions.question.QuestionOptions[1].Body != singleChoiceQuestion(1).QuestionOptions[1].Body {
+			t.Errorf("%s: expected options to stay unchanged, got %+v", name, questions.question.QuestionOptions)
+		}
+	}
+}
+
+type fakeMaterialRepo struct {
+	repository.MaterialRepository
+}
+
+func (f *fakeMaterialRepo) GetAll() ([]entities.Material, error) {
+	return []entities.Material{{ID: 1, Name: "Math"}}, nil
+}
+
+// fakeImportQuestionRepo keeps the questions given to CreateMany and fails the import
+type fakeImportQuestionRepo struct {
+	repository.QuestionRepository
+	questions []entities.Question
+}
+
+func (f *fakeImportQuestionRepo) CreateMany(questions []entities.Question, solutions []*entities.QuestionSolution) ([]entities.Question, error) {
+	f.questions = questions
+	return questions, errors.New("import failed")
+}
+
+func TestImportBankCreatesNewTagsWithQuestions(t *testing.T) {
+	tags := &fakeTagRepo{}
+	questions := &fakeImportQuestionRepo{}
+	u := &question{questionRepo: questions, tagRepo: tags, materialRepo: &fakeMaterialRepo{}, name: "Question Usecase"}
+
+	content := "// [tag:algebra] [tag:Geometry]\n::Q1:: What is 2+2? {~3 =4}\n\n// [tag:geometry]\n::Q2:: What is 3+3? {=6 ~7}\n"
+	resp := u.ImportBank(params.QuestionBankImportParam{Format: "gift", FileName: "bank.gift", MaterialID: 1, IsAdmin: true}, []byte(content))
+	if resp.Code == http.StatusOK {
+		t.Fatal("expected the import to fail with the questions")
+	}
+
+	if len(tags.created) != 0 {
+		t.Errorf("expected no tag to be created outside of the import, got %+v", tags.created)
+	}
+	if len(questions.questions) != 2 {
+		t.Fatalf("expected 2 questions to be imported, got %d", len(questions.questions))
+	}
+	first, second := questions.questions[0].QuestionTags, questions.questions[1].QuestionTags
+	if len(first) != 2 || first[0].ID != 3 || first[1].ID != 0 || first[1].Name != "Geometry" {
+		t.Errorf("expected the known tag and the new tag, got %+v", first)
+	}
+	if len(second) != 1 || second[0].ID != 0 || second[0].Name != "geometry" {
+		t.Errorf("expected the new tag, got %+v", second)
+	}
+}
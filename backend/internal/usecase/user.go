@@ -8,6 +8,7 @@ import (
 	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
 	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/minio"
 	"gitlab.com/project-quiz/utils/password"
 	"gitlab.com/project-quiz/utils/postgres"
 
@@ -18,8 +19,10 @@ import (
 )
 
 type user struct {
-	repo repository.UserRepository
-	name string
+	repo      repository.UserRepository
+	badgeRepo repository.BadgeRepository
+	minio     minio.MinioStorageContract
+	name      string
 }
 
 type UserUsecase interface {
@@ -35,6 +38,9 @@ type UserUsecase interface {
 	// Update user record
 	Get(int) appctx.Response
 
+	// Get authenticated user with the badges awarded to them
+	Me(int) appctx.Response
+
 	// Delete user record
 	Delete(int) appctx.Response
 
@@ -42,10 +48,12 @@ type UserUsecase interface {
 	UpdatePassword(params.UserUpdatePassword) appctx.Response
 }
 
-func NewUserUsecase(db *gorm.DB) UserUsecase {
+func NewUserUsecase(db *gorm.DB, minio minio.MinioStorageContract) UserUsecase {
 	return &user{
-		repo: repository.NewUserRepository(db),
-		name: "USER USECASE",
+		repo:      repository.NewUserRepository(db),
+		badgeRepo: repository.NewBadgeRepository(db),
+		minio:     minio,
+		name:      "USER USECASE",
 	}
 }
 
@@ -108,6 +116,29 @@ func (u *user) Get(ID int) appctx.Response {
 	return *appctx.NewResponse().WithData(user)
 }
 
+func (u *user) Me(ID int) appctx.Response {
+	var user entities.User
+	user, err := u.repo.Get(user, ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("[%s][Me] %s", u.name, err.Error()))
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	badges, err := u.badgeRepo.ListUserBadges(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	for i := range badges {
+		if badges[i].Badge != nil {
+			badge := withBadgeIcon(u.minio, *badges[i].Badge)
+			badges[i].Badge = &badge
+		}
+	}
+
+	return *appctx.NewResponse().WithData(params.UserMeResponse{User: user, Badges: badges})
+}
+
 func (u *user) Delete(ID int) appctx.Response {
 	var user entities.User
 	user, err := u.repo.Get(user, ID)
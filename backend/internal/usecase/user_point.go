@@ -1,42 +1,74 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
 	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/pagination/cursor"
 	"gorm.io/gorm"
 )
 
 type userPoint struct {
-	userPointRepo repository.UserPointRepository
-	name          string
+	userPointRepo   repository.UserPointRepository
+	transactionRepo repository.PointTransactionRepository
+	streakRepo      repository.UserStreakRepository
+	userRepo        repository.UserRepository
+	name            string
 }
 
 type UserPointUsecase interface {
 	Get(userID int) appctx.Response
 	GetList(param params.UserPointFilterParam) appctx.Response
+	// Admin gives points to user
+	Grant(param params.PointAdjustParam) appctx.Response
+	// Admin takes points away from user
+	Revoke(param params.PointAdjustParam) appctx.Response
+	// List point transactions explaining the balance
+	Transactions(param params.PointTransactionFilterParam) appctx.Response
 }
 
 func NewUserPointUsecase(db *gorm.DB) UserPointUsecase {
 	return &userPoint{
-		userPointRepo: repository.NewUserPointRepository(db),
-		name:          "User Point Usecase",
+		userPointRepo:   repository.NewUserPointRepository(db),
+		transactionRepo: repository.NewPointTransactionRepository(db),
+		streakRepo:      repository.NewUserStreakRepository(db),
+		userRepo:        repository.NewUserRepository(db),
+		name:            "User Point Usecase",
 	}
 }
 
 func (u *userPoint) Get(userID int) appctx.Response {
 	up, err := u.userPointRepo.GetByUser(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	streak, err := u.streakRepo.Get(userID)
 	if err != nil {
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
 
-	return *appctx.NewResponse().WithData(up)
+	// User who has not earned any point yet still has a streak to show
+	up.UserID = userID
+	return *appctx.NewResponse().WithData(params.UserPointResponse{
+		UserPoint: up,
+		Streak:    streak.AsOf(time.Now()),
+	})
 }
 
 func (u *userPoint) GetList(param params.UserPointFilterParam) appctx.Response {
+	if param.IsCursor() {
+		return u.getListByCursor(param)
+	}
+
 	ups, count, err := u.userPointRepo.GetListOfUserPoint(param)
 	if err != nil {
 		logrus.Error(fmt.Sprintf("[%s][GetList] %s", u.name, err.Error()))
@@ -45,3 +77,75 @@ func (u *userPoint) GetList(param params.UserPointFilterParam) appctx.Response {
 
 	return *appctx.NewResponse().WithData(ups).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
 }
+
+func (u *userPoint) getListByCursor(param params.UserPointFilterParam) appctx.Response {
+	ups, next, err := u.userPointRepo.GetListOfUserPointByCursor(param)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][GetList] %s", u.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	var total *int64
+	if param.WithCount {
+		count, err := u.userPointRepo.CountOfUserPoint(param)
+		if err != nil {
+			logrus.Error(fmt.Sprintf("[%s][GetList] %s", u.name, err.Error()))
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
+		c := int64(count)
+		total = &c
+	}
+
+	return *appctx.NewResponse().WithData(ups).WithCursorMeta(int64(cursor.Limit(param.Limit)), param.Cursor, next, total)
+}
+
+func (u *userPoint) Grant(param params.PointAdjustParam) appctx.Response {
+	return u.adjust(param, param.Amount, entities.PointReasonAdminGrant)
+}
+
+func (u *userPoint) Revoke(param params.PointAdjustParam) appctx.Response {
+	return u.adjust(param, -param.Amount, entities.PointReasonAdminRevoke)
+}
+
+func (u *userPoint) adjust(param params.PointAdjustParam, amount int, reason string) appctx.Response {
+	if _, err := u.userRepo.Get(entities.User{}, param.UserID); err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	transaction := entities.PointTransaction{
+		UserID:  param.UserID,
+		Amount:  amount,
+		Reason:  reason,
+		Note:    strings.TrimSpace(param.Note),
+		ActorID: &param.ActorID,
+	}
+	if param.IdempotencyKey != "" {
+		key := fmt.Sprintf("%s:%d:%s", reason, param.ActorID, param.IdempotencyKey)
+		transaction.IdempotencyKey = &key
+	}
+
+	transaction, applied, err := u.transactionRepo.Apply(transaction)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Adjust] %s", u.name, err.Error()))
+		if errors.Is(err, repository.ErrInsufficientPoint) {
+			return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusConflict)
+		}
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if !applied {
+		return *appctx.NewResponse().WithData(transaction).WithMessage("Transaction was already applied")
+	}
+
+	return *appctx.NewResponse().WithData(transaction)
+}
+
+func (u *userPoint) Transactions(param params.PointTransactionFilterParam) appctx.Response {
+	transactions, count, err := u.transactionRepo.List(param)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Transactions] %s", u.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(transactions).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
+}
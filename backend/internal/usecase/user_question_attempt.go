@@ -2,19 +2,30 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
 	"gitlab.com/project-quiz/internal/params"
 	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/minio"
 	"gorm.io/gorm"
 )
 
 type userQuestionAttempt struct {
-	attemptRepo   repository.UserQuestionAttemptRepository
-	optionRepo    repository.QuestionOptionRepository
-	userPointRepo repository.UserPointRepository
-	name          string
+	attemptRepo     repository.UserQuestionAttemptRepository
+	questionRepo    repository.QuestionRepository
+	optionRepo      repository.QuestionOptionRepository
+	pointRepo       repository.PointTransactionRepository
+	streakRepo      repository.UserStreakRepository
+	milestones      entities.StreakMilestones
+	packRepo        repository.QuestionPackRepository
+	packAttemptRepo repository.QuestionPackAttemptRepository
+	revisionRepo    repository.QuestionRevisionRepository
+	badges          badgeAwarder
+	name            string
 }
 
 type UserQuestionAttemptUsecase interface {
@@ -26,17 +37,83 @@ type UserQuestionAttemptUsecase interface {
 	GetLatestAnswers(param params.AttemptGetLatestAnswersParam) appctx.Response
 }
 
-func NewUserQuestionAttemptUsecase(db *gorm.DB) UserQuestionAttemptUsecase {
+func NewUserQuestionAttemptUsecase(db *gorm.DB, minio minio.MinioStorageContract, milestones entities.StreakMilestones) UserQuestionAttemptUsecase {
 	return &userQuestionAttempt{
-		attemptRepo:   repository.NewUserQuestionAttemptRepository(db),
-		optionRepo:    repository.NewQuestionOptionRepository(db),
-		userPointRepo: repository.NewUserPointRepository(db),
-		name:          "User Question Attempt Usecase",
+		attemptRepo:     repository.NewUserQuestionAttemptRepository(db),
+		questionRepo:    repository.NewQuestionRepository(db, nil),
+		optionRepo:      repository.NewQuestionOptionRepository(db),
+		pointRepo:       repository.NewPointTransactionRepository(db),
+		streakRepo:      repository.NewUserStreakRepository(db),
+		milestones:      milestones,
+		packRepo:        repository.NewQuestionPackRepository(db),
+		packAttemptRepo: repository.NewQuestionPackAttemptRepository(db),
+		revisionRepo:    repository.NewQuestionRevisionRepository(db),
+		badges:          newBadgeAwarder(db, minio),
+		name:            "User Question Attempt Usecase",
 	}
 }
 
+// validatePackAttempt make sure the pack attempt belongs to the user and the
+// question is part of the attempted pack. Nil pack attempt means free practice.
+func (u *userQuestionAttempt) validatePackAttempt(packAttemptID *int, questionID, userID int, mustBeOpen bool) *appctx.Response {
+	if packAttemptID == nil {
+		return nil
+	}
+
+	packAttempt, err := u.packAttemptRepo.Get(*packAttemptID)
+	if err != nil {
+		return appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if packAttempt.UserID != userID {
+		return appctx.NewResponse().WithErrors("Invalid user").WithCode(http.StatusForbidden)
+	}
+
+	if mustBeOpen && packAttempt.IsFinish {
+		return appctx.NewResponse().WithErrors("question pack attempt is already finished").WithCode(http.StatusBadRequest)
+	}
+
+	if mustBeOpen && packAttempt.IsExpired(time.Now()) {
+		return appctx.NewResponse().WithErrors("time limit of the question pack attempt has passed").WithCode(http.StatusForbidden)
+	}
+
+	if questionID == 0 {
+		return nil
+	}
+
+	inPack, err := u.packRepo.HasQuestion(packAttempt.QuestionPackID, questionID)
+	if err != nil {
+		return appctx.NewResponse().WithErrorObj(err)
+	}
+
+	if !inPack {
+		return appctx.NewResponse().WithErrors("question is not part of the question pack").WithCode(http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 func (u *userQuestionAttempt) AnswerQuestion(param params.AttemptAnswerQuestionParam) appctx.Response {
-	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID)
+	if resp := u.validatePackAttempt(param.QuestionPackAttemptID, param.QuestionID, param.UserID, true); resp != nil {
+		return *resp
+	}
+
+	question, err := u.questionRepo.GetAnswerKey(param.QuestionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	answer := entities.UserQuestionAttempt{
+		QuestionOptionID:  param.OptionID,
+		QuestionOptionIDs: param.OptionIDs,
+		AnswerText:        param.AnswerText,
+		AnswerPairs:       param.AnswerPairs,
+	}
+	if err := question.ValidateAnswer(question.QuestionOptions, answer); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+	}
+
+	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID, param.QuestionPackAttemptID)
 	found := true
 	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -46,15 +123,20 @@ func (u *userQuestionAttempt) AnswerQuestion(param params.AttemptAnswerQuestionP
 	}
 
 	if found {
-		attempt.QuestionOptionID = &param.OptionID
-		attempt, err = u.attemptRepo.Update(attempt)
+		attempt.SetAnswer(answer)
+		attempt.TimeSpent += param.TimeSpent
+		attempt.QuestionRevisionID = u.questionRevisionID(question)
+		attempt, err = u.attemptRepo.UpdateField(attempt, append([]string{"time_spent", "question_revision_id"}, entities.AnswerFields...))
 		if err != nil {
 			return *appctx.NewResponse().WithErrorObj(err)
 		}
 	} else {
 		attempt.QuestionID = param.QuestionID
-		attempt.QuestionOptionID = &param.OptionID
+		attempt.SetAnswer(answer)
+		attempt.QuestionPackAttemptID = param.QuestionPackAttemptID
 		attempt.UserID = param.UserID
+		attempt.TimeSpent = param.TimeSpent
+		attempt.QuestionRevisionID = u.questionRevisionID(question)
 		attempt, err = u.attemptRepo.Create(attempt)
 		if err != nil {
 			return *appctx.NewResponse().WithErrorObj(err)
@@ -65,14 +147,19 @@ func (u *userQuestionAttempt) AnswerQuestion(param params.AttemptAnswerQuestionP
 }
 
 func (u *userQuestionAttempt) ClearAnswer(param params.AttemptClearAnswerQuestionParam) appctx.Response {
-	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID)
+	if resp := u.validatePackAttempt(param.QuestionPackAttemptID, param.QuestionID, param.UserID, true); resp != nil {
+		return *resp
+	}
+
+	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID, param.QuestionPackAttemptID)
 	if err != nil {
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
 
-	attempt.QuestionOptionID = nil
+	attempt.SetAnswer(entities.UserQuestionAttempt{})
 	attempt.AttemptValue = false
-	attempt, err = u.attemptRepo.UpdateField(attempt, []string{"question_option_id", "attempt_value"})
+	attempt.Credit = 0
+	attempt, err = u.attemptRepo.UpdateField(attempt, append([]string{"attempt_value", "credit"}, entities.AnswerFields...))
 	if err != nil {
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
@@ -80,12 +167,16 @@ func (u *userQuestionAttempt) ClearAnswer(param params.AttemptClearAnswerQuestio
 }
 
 func (u *userQuestionAttempt) GetLatestAnswer(param params.AttemptClearAnswerQuestionParam) appctx.Response {
-	attempt, err := u.attemptRepo.GetLatestSubmitted(param.QuestionID, param.UserID)
+	if resp := u.validatePackAttempt(param.QuestionPackAttemptID, param.QuestionID, param.UserID, false); resp != nil {
+		return *resp
+	}
+
+	attempt, err := u.attemptRepo.GetLatestSubmitted(param.QuestionID, param.UserID, param.QuestionPackAttemptID)
 	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return *appctx.NewResponse().WithErrorObj(err)
 		} else {
-			attempt, err = u.attemptRepo.GetLatest(param.QuestionID, param.UserID)
+			attempt, err = u.attemptRepo.GetLatest(param.QuestionID, param.UserID, param.QuestionPackAttemptID)
 			if err != nil {
 				return *appctx.NewResponse().WithErrorObj(err)
 			}
@@ -96,7 +187,11 @@ func (u *userQuestionAttempt) GetLatestAnswer(param params.AttemptClearAnswerQue
 }
 
 func (u *userQuestionAttempt) GetLatestAnswers(param params.AttemptGetLatestAnswersParam) appctx.Response {
-	attempt, err := u.attemptRepo.GetLatestSubmittedAnswers(param.QuestionIDs, param.UserID)
+	if resp := u.validatePackAttempt(param.QuestionPackAttemptID, 0, param.UserID, false); resp != nil {
+		return *resp
+	}
+
+	attempt, err := u.attemptRepo.GetLatestSubmittedAnswers(param.QuestionIDs, param.UserID, param.QuestionPackAttemptID)
 	if err != nil {
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
@@ -105,7 +200,11 @@ func (u *userQuestionAttempt) GetLatestAnswers(param params.AttemptGetLatestAnsw
 }
 
 func (u *userQuestionAttempt) MarkAttempt(param params.AttemptSubmitAnswerQuestionParam) appctx.Response {
-	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID)
+	if resp := u.validatePackAttempt(param.QuestionPackAttemptID, param.QuestionID, param.UserID, true); resp != nil {
+		return *resp
+	}
+
+	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID, param.QuestionPackAttemptID)
 	found := true
 	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -116,6 +215,7 @@ func (u *userQuestionAttempt) MarkAttempt(param params.AttemptSubmitAnswerQuesti
 
 	if !found {
 		attempt.QuestionID = param.QuestionID
+		attempt.QuestionPackAttemptID = param.QuestionPackAttemptID
 		attempt.UserID = param.UserID
 		attempt, err = u.attemptRepo.Create(attempt)
 		if err != nil {
@@ -133,43 +233,138 @@ func (u *userQuestionAttempt) MarkAttempt(param params.AttemptSubmitAnswerQuesti
 }
 
 func (u *userQuestionAttempt) SubmitAnswer(param params.AttemptSubmitAnswerQuestionParam) appctx.Response {
-	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID)
-	if err != nil {
-		return *appctx.NewResponse().WithErrorObj(err)
+	if resp := u.validatePackAttempt(param.QuestionPackAttemptID, param.QuestionID, param.UserID, true); resp != nil {
+		return *resp
 	}
 
-	if attempt.QuestionOptionID == nil {
-		return *appctx.NewResponse().WithErrors("Jawaban kosong").WithCode(http.StatusBadRequest)
+	attempt, err := u.attemptRepo.GetLatest(param.QuestionID, param.UserID, param.QuestionPackAttemptID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
 	}
 
-	option, err := u.optionRepo.Get(*attempt.QuestionOptionID)
+	question, err := u.questionRepo.GetAnswerKey(param.QuestionID)
 	if err != nil {
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
 
-	if *option.OptionValue {
-		go u.userPointRepo.UpdateOrCreate(param.UserID, 3)
+	if !question.IsAnswered(attempt) {
+		return *appctx.NewResponse().WithErrors("Jawaban kosong").WithCode(http.StatusBadRequest)
 	}
 
-	attempt.AttemptValue = *option.OptionValue
+	credit := question.Grade(question.QuestionOptions, attempt)
+	isCorrect := credit >= 1
+
+	attempt.AttemptValue = isCorrect
+	attempt.Credit = credit
 	attempt.IsSubmitted = true
 	attempt.IsMarked = false
-	attempt, err = u.attemptRepo.Update(attempt)
+	attempt.QuestionRevisionID = u.questionRevisionID(question)
+	attempt, err = u.attemptRepo.UpdateField(attempt, []string{"attempt_value", "credit", "is_submitted", "is_marked", "question_revision_id"})
 	if err != nil {
 		return *appctx.NewResponse().WithErrorObj(err)
 	}
 
-	trueOption, err := u.optionRepo.GetTrueOption(param.QuestionID)
-	if err != nil {
-		return *appctx.NewResponse().WithErrorObj(err)
+	// The question is rewarded once per user, inside or outside of question pack,
+	// submitting it again does not add points
+	if isCorrect {
+		_, _, err = u.pointRepo.Apply(entities.PointTransaction{
+			UserID:         param.UserID,
+			Amount:         entities.CorrectAnswerPoint,
+			Reason:         entities.PointReasonCorrectAnswer,
+			SourceType:     entities.PointSourceAttempt,
+			SourceID:       &attempt.ID,
+			IdempotencyKey: entities.CorrectAnswerIdempotencyKey(param.QuestionID, param.UserID),
+		})
+		if err != nil {
+			return *appctx.NewResponse().WithErrorObj(err)
+		}
 	}
 
+	// A question the user already got right does not count toward the answer streak again,
+	// when it can not be checked the answer is not counted either
+	repeated, err := u.attemptRepo.HasCorrectAnswer(param.QuestionID, param.UserID, attempt.ID)
+	streak, bonus := u.recordStreak(param.UserID, attempt.ID, isCorrect, err == nil && !repeated)
+
 	response := &params.AttemptSubmitAnswerResponse{
-		AttemptValue:     *option.OptionValue,
-		TrueAnswerID:     trueOption.ID,
-		AnswerID:         option.ID,
-		TrueAnswerStreak: 0, // Gonna develop it later LOL
+		AttemptValue:     isCorrect,
+		TrueAnswerStreak: streak.CurrentAnswerStreak,
+		Type:             question.QuestionType(),
+		Credit:           credit,
+		Streak:           streak,
+		StreakBonus:      bonus,
+		Badges:           u.badges.award(param.UserID, entities.BadgeEventAnswerSubmitted),
+	}
+
+	switch question.QuestionType() {
+	case entities.QuestionTypeSingleChoice, entities.QuestionTypeTrueFalse:
+		if trueOptionIDs := question.TrueOptionIDs(question.QuestionOptions); len(trueOptionIDs) > 0 {
+			response.TrueAnswerID = trueOptionIDs[0]
+		}
+		response.AnswerID = *attempt.QuestionOptionID
+	case entities.QuestionTypeNumeric:
+		response.NumericAnswer = question.NumericAnswer
+	case entities.QuestionTypeShortText:
+		response.AcceptedAnswers = question.AcceptedAnswers
+	default:
+		response.TrueAnswerIDs = question.TrueOptionIDs(question.QuestionOptions)
 	}
 
 	return *appctx.NewResponse().WithData(response)
 }
+
+// recordStreak counts the submitted attempt into the streaks of user and gives the bonus of
+// reached milestones. The answer is already submitted, so failures are only logged.
+func (u *userQuestionAttempt) recordStreak(userID, attemptID int, isCorrect, countsAnswer bool) (entities.UserStreak, int) {
+	before, after, err := u.streakRepo.Record(userID, attemptID, isCorrect, countsAnswer, time.Now())
+	if err != nil {
+		return after, 0
+	}
+
+	bonus := 0
+	if after.CurrentAnswerStreak > before.CurrentAnswerStreak {
+		if point, ok := u.milestones.Answer[after.CurrentAnswerStreak]; ok {
+			bonus += u.giveStreakBonus(userID, attemptID, point, entities.StreakAnswer,
+				fmt.Sprintf("%d correct answers in a row", after.CurrentAnswerStreak))
+		}
+	}
+	if after.CurrentDailyStreak > before.CurrentDailyStreak {
+		if point, ok := u.milestones.Daily[after.CurrentDailyStreak]; ok {
+			bonus += u.giveStreakBonus(userID, attemptID, point, entities.StreakDaily,
+				fmt.Sprintf("%d active days in a row", after.CurrentDailyStreak))
+		}
+	}
+
+	return after, bonus
+}
+
+func (u *userQuestionAttempt) giveStreakBonus(userID, attemptID, point int, streak, note string) int {
+	_, applied, err := u.pointRepo.Apply(entities.PointTransaction{
+		UserID:         userID,
+		Amount:         point,
+		Reason:         entities.PointReasonStreakBonus,
+		SourceType:     entities.PointSourceAttempt,
+		SourceID:       &attemptID,
+		Note:           note,
+		IdempotencyKey: entities.PointIdempotencyKey(entities.PointReasonStreakBonus+":"+streak, entities.PointSourceAttempt, attemptID),
+	})
+	if err != nil || !applied {
+		return 0
+	}
+
+	return point
+}
+
+// questionRevisionID gives the revision the question is answered against,
+// a revision is recorded first for questions which have not been revised yet
+func (u *userQuestionAttempt) questionRevisionID(question entities.Question) *int {
+	if question.RevisionID != nil {
+		return question.RevisionID
+	}
+
+	revision, err := u.revisionRepo.Record(question.ID, 0, entities.RevisionActionBaseline)
+	if err != nil {
+		return nil
+	}
+
+	return &revision.ID
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/boolpointer"
+	"gorm.io/gorm"
+)
+
+// Fakes keep the state in memory, methods which are not used by the tests are left to the embedded interface
+
+type fakeAttemptRepo struct {
+	repository.UserQuestionAttemptRepository
+	attempts []entities.UserQuestionAttempt
+}
+
+func (f *fakeAttemptRepo) Create(attempt entities.UserQuestionAttempt) (entities.UserQuestionAttempt, error) {
+	attempt.ID = len(f.attempts) + 1
+	f.attempts = append(f.attempts, attempt)
+	return attempt, nil
+}
+
+func (f *fakeAttemptRepo) UpdateField(attempt entities.UserQuestionAttempt, fields []string) (entities.UserQuestionAttempt, error) {
+	f.attempts[attempt.ID-1] = attempt
+	return attempt, nil
+}
+
+func (f *fakeAttemptRepo) GetLatest(questionID int, userID int, packAttemptID *int) (entities.UserQuestionAttempt, error) {
+	for i := len(f.attempts) - 1; i >= 0; i-- {
+		a := f.attempts[i]
+		if a.QuestionID == questionID && a.UserID == userID && !a.IsSubmitted {
+			return a, nil
+		}
+	}
+	return entities.UserQuestionAttempt{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAttemptRepo) HasCorrectAnswer(questionID, userID, exceptAttemptID int) (bool, error) {
+	for _, a := range f.attempts {
+		if a.QuestionID == questionID && a.UserID == userID && a.IsSubmitted && a.AttemptValue && a.ID != exceptAttemptID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeQuestionRepo struct {
+	repository.QuestionRepository
+	question entities.Question
+}
+
+func (f *fakeQuestionRepo) GetAnswerKey(ID int) (entities.Question, error) {
+	return f.question, nil
+}
+
+type fakePointRepo struct {
+	repository.PointTransactionRepository
+	applied map[string]entities.PointTransaction
+	balance int
+}
+
+func (f *fakePointRepo) Apply(transaction entities.PointTransaction) (entities.PointTransaction, bool, error) {
+	if transaction.IdempotencyKey != nil {
+		if applied, ok := f.applied[*transaction.IdempotencyKey]; ok {
+			return applied, false, nil
+		}
+		f.applied[*transaction.IdempotencyKey] = transaction
+	}
+	f.balance += transaction.Amount
+	return transaction, true, nil
+}
+
+type fakeStreakRepo struct {
+	repository.UserStreakRepository
+	streak entities.UserStreak
+}
+
+func (f *fakeStreakRepo) Record(userID, attemptID int, isCorrect, countsAnswer bool, day time.Time) (entities.UserStreak, entities.UserStreak, error) {
+	before := f.streak
+	f.streak.Record(attemptID, isCorrect, countsAnswer, day)
+	return before, f.streak, nil
+}
+
+type fakeBadgeRepo struct {
+	repository.BadgeRepository
+}
+
+func (f *fakeBadgeRepo) ListPending(userID int, ruleTypes []string) ([]entities.Badge, error) {
+	return nil, nil
+}
+
+func newAttemptUsecaseWithFakes(question entities.Question, milestones entities.StreakMilestones) (*userQuestionAttempt, *fakePointRepo) {
+	points := &fakePointRepo{applied: map[string]entities.PointTransaction{}}
+	return &userQuestionAttempt{
+		attemptRepo:  &fakeAttemptRepo{},
+		questionRepo: &fakeQuestionRepo{question: question},
+		pointRepo:    points,
+		streakRepo:   &fakeStreakRepo{},
+		milestones:   milestones,
+		badges:       badgeAwarder{badgeRepo: &fakeBadgeRepo{}},
+		name:         "User Question Attempt Usecase",
+	}, points
+}
+
+func singleChoiceQuestion(ID int) entities.Question {
+	revisionID := 1
+	return entities.Question{
+		ID:         ID,
+		Type:       entities.QuestionTypeSingleChoice,
+		RevisionID: &revisionID,
+		QuestionOptions: []entities.QuestionOption{
+			{ID: 10, QuestionID: ID, OptionValue: boolpointer.BoolPointer(true)},
+			{ID: 11, QuestionID: ID, OptionValue: boolpointer.BoolPointer(false)},
+		},
+	}
+}
+
+func answerAndSubmit(t *testing.T, u *userQuestionAttempt, userID, questionID, optionID int) params.AttemptSubmitAnswerResponse {
+	t.Helper()
+
+	resp := u.AnswerQuestion(params.AttemptAnswerQuestionParam{QuestionID: questionID, UserID: userID, OptionID: &optionID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("answer failed: %v", resp.Errors)
+	}
+
+	resp = u.SubmitAnswer(params.AttemptSubmitAnswerQuestionParam{QuestionID: questionID, UserID: userID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("submit failed: %v", resp.Errors)
+	}
+
+	return *resp.Data.(*params.AttemptSubmitAnswerResponse)
+}
+
+func TestSubmitAnswerTwiceRewardsQuestionOnce(t *testing.T) {
+	u, points := newAttemptUsecaseWithFakes(singleChoiceQuestion(1), entities.StreakMilestones{})
+
+	answerAndSubmit(t, u, 7, 1, 10)
+	answerAndSubmit(t, u, 7, 1, 10)
+
+	if points.balance != entities.CorrectAnswerPoint {
+		t.Errorf("expected %d points for answering the question twice, got %d", entities.CorrectAnswerPoint, points.balance)
+	}
+}
+
+func TestSubmitSameQuestionDoesNotBuildAnswerStreak(t *testing.T) {
+	milestones := entities.StreakMilestones{Answer: map[int]int{2: 5}}
+	u, points := newAttemptUsecaseWithFakes(singleChoiceQuestion(1), milestones)
+
+	var resp params.AttemptSubmitAnswerResponse
+	for i := 0; i < 3; i++ {
+		resp = answerAndSubmit(t, u, 7, 1, 10)
+	}
+
+	if resp.Streak.CurrentAnswerStreak != 1 || resp.StreakBonus != 0 {
+		t.Errorf("expected answer streak 1 without bonus, got streak %d and bonus %d", resp.Streak.CurrentAnswerStreak, resp.StreakBonus)
+	}
+	if points.balance != entities.CorrectAnswerPoint {
+		t.Errorf("expected only the correct answer point, got %d", points.balance)
+	}
+}
+
+func (f *fakeQuestionPackRepo) HasQuestion(ID int, questionID int) (bool, error) {
+	for _, question := range f.pack.Questions {
+		if question.ID == questionID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakePackAttemptRepo struct {
+	repository.QuestionPackAttemptRepository
+	attempt entities.QuestionPackAttempt
+}
+
+func (f *fakePackAttemptRepo) Get(ID int) (entities.QuestionPackAttempt, error) {
+	return f.attempt, nil
+}
+
+func TestSubmitAnswerInsideQuestionPackRewardsPoints(t *testing.T) {
+	u, points := newAttemptUsecaseWithFakes(singleChoiceQuestion(1), entities.StreakMilestones{})
+	u.packRepo = &fakeQuestionPackRepo{pack: entities.QuestionPack{ID: 2, Questions: []entities.Question{{ID: 1}}}}
+	u.packAttemptRepo = &fakePackAttemptRepo{attempt: entities.QuestionPackAttempt{ID: 3, UserID: 7, QuestionPackID: 2, StartedAt: time.Now()}}
+
+	packAttemptID := 3
+	optionID := 10
+	if resp := u.AnswerQuestion(params.AttemptAnswerQuestionParam{QuestionID: 1, UserID: 7, OptionID: &optionID, QuestionPackAttemptID: &packAttemptID}); len(resp.Errors) > 0 {
+		t.Fatalf("answer failed: %v", resp.Errors)
+	}
+	if resp := u.SubmitAnswer(params.AttemptSubmitAnswerQuestionParam{QuestionID: 1, UserID: 7, QuestionPackAttemptID: &packAttemptID}); len(resp.Errors) > 0 {
+		t.Fatalf("submit failed: %v", resp.Errors)
+	}
+
+	if points.balance != entities.CorrectAnswerPoint {
+		t.Errorf("expected %d points for the correct answer inside the pack, got %d", entities.CorrectAnswerPoint, points.balance)
+	}
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/jwt"
+	"gorm.io/gorm"
+)
+
+type userSession struct {
+	sessionRepo repository.UserSessionRepository
+	name        string
+}
+
+type UserSessionUsecase interface {
+	// List active sessions of user, the current session is marked
+	List(userID, currentSessionID int) appctx.Response
+	// Revoke active session of user. Basic auth credentials stay valid,
+	// the next basic auth request of the device starts a new session.
+	Revoke(userID, sessionID int, reason string) appctx.Response
+}
+
+func NewUserSessionUsecase(db *gorm.DB) UserSessionUsecase {
+	return &userSession{
+		sessionRepo: repository.NewUserSessionRepository(db),
+		name:        "User Session Usecase",
+	}
+}
+
+func (u *userSession) List(userID, currentSessionID int) appctx.Response {
+	// Session unseen for as long as a refresh token lives can not be used anymore
+	sessions, err := u.sessionRepo.ListActive(userID, time.Now().Add(-jwt.RefreshTokenDuration))
+	if err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return *appctx.NewResponse().WithData(sessions)
+}
+
+func (u *userSession) Revoke(userID, sessionID int, reason string) appctx.Response {
+	session, err := u.sessionRepo.Get(sessionID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	// Session of other user is reported as missing
+	if session.UserID != userID || session.IsRevoked() {
+		return *appctx.NewResponse().WithErrors("session not found").WithCode(http.StatusNotFound)
+	}
+
+	if err := u.sessionRepo.Revoke(session.ID, reason); err != nil {
+		return *appctx.NewResponse().WithErrors(err.Error())
+	}
+
+	return *appctx.NewResponse().WithMessage("Session has been revoked")
+}
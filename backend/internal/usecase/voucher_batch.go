@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/appctx"
+	"gitlab.com/project-quiz/internal/entities"
+	"gitlab.com/project-quiz/internal/params"
+	"gitlab.com/project-quiz/internal/params/generics"
+	"gitlab.com/project-quiz/internal/repository"
+	"gitlab.com/project-quiz/utils/random"
+	"gorm.io/gorm"
+)
+
+const (
+	voucherDefaultCodeLength = 12
+	// Maximum rounds of regenerating codes which collide with existing vouchers
+	voucherMaxGenerateRounds = 5
+)
+
+type voucherBatch struct {
+	voucherBatchRepo   repository.VoucherBatchRepository
+	premiumPackageRepo repository.PremiumPackageRepository
+	name               string
+}
+
+type VoucherBatchUsecase interface {
+	// Generate a batch of unique vouchers
+	Create(param params.VoucherBatchCreateParam) appctx.Response
+	// Get list of voucher batch
+	List(param params.VoucherBatchFilterParam) appctx.Response
+	// Get detail of voucher batch with its summary
+	Detail(ID int) appctx.Response
+	// Export vouchers of a batch as CSV
+	Export(ID int) appctx.Response
+	// Revoke all unredeemed vouchers of a batch
+	Revoke(ID int) appctx.Response
+}
+
+func NewVoucherBatchUsecase(db *gorm.DB) VoucherBatchUsecase {
+	return &voucherBatch{
+		voucherBatchRepo:   repository.NewVoucherBatchRepository(db),
+		premiumPackageRepo: repository.NewPremiumPackageRepository(db),
+		name:               "Voucher Batch Usecase",
+	}
+}
+
+func (v *voucherBatch) Create(param params.VoucherBatchCreateParam) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Create] is executed", v.name))
+
+	batch := entities.VoucherBatch{
+		Campaign:   param.Campaign,
+		LongPeriod: param.LongPeriod,
+		Quantity:   param.Quantity,
+		CodeLength: param.CodeLength,
+		Alphabet:   strings.ToUpper(param.Alphabet),
+	}
+	if batch.CodeLength == 0 {
+		batch.CodeLength = voucherDefaultCodeLength
+	}
+	if batch.Alphabet == "" {
+		batch.Alphabet = random.DefaultVoucherAlphabet
+	}
+
+	tokens, err := v.generateTokens(batch)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Create] %s", v.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	vouchers := make([]entities.PremiumPackage, len(tokens))
+	for i, token := range tokens {
+		vouchers[i] = entities.PremiumPackage{
+			Token:      token,
+			LongPeriod: batch.LongPeriod,
+		}
+	}
+
+	batch, err = v.voucherBatchRepo.Create(batch, vouchers)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Create] %s", v.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(params.VoucherBatchDetailResponse{
+		VoucherBatch: batch,
+		Summary: params.VoucherBatchSummary{
+			Total:     len(vouchers),
+			Available: len(vouchers),
+		},
+	})
+}
+
+// generateTokens generate unique tokens which are not used by any existing voucher
+func (v *voucherBatch) generateTokens(batch entities.VoucherBatch) ([]string, error) {
+	used := make(map[string]bool, batch.Quantity)
+	tokens := make([]string, 0, batch.Quantity)
+
+	for round := 0; round < voucherMaxGenerateRounds && len(tokens) < batch.Quantity; round++ {
+		candidates := make([]string, 0, batch.Quantity-len(tokens))
+		for len(candidates) < batch.Quantity-len(tokens) {
+			token, err := random.GenerateSecureVoucher(batch.CodeLength, batch.Alphabet)
+			if err != nil {
+				return nil, err
+			}
+
+			if used[token] {
+				continue
+			}
+			used[token] = true
+			candidates = append(candidates, token)
+		}
+
+		existing, err := v.premiumPackageRepo.GetExistingTokens(candidates)
+		if err != nil {
+			return nil, err
+		}
+
+		exists := make(map[string]bool, len(existing))
+		for _, token := range existing {
+			exists[token] = true
+		}
+
+		for _, token := range candidates {
+			if !exists[token] {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	if len(tokens) < batch.Quantity {
+		return nil, errors.New("cannot generate enough unique vouchers, use longer code or larger alphabet")
+	}
+
+	return tokens, nil
+}
+
+func (v *voucherBatch) List(param params.VoucherBatchFilterParam) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][List] is executed", v.name))
+
+	batches, count, err := v.voucherBatchRepo.List(param)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s][List] %s", v.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(batches).WithMeta(int64(param.Page), int64(param.Limit), int64(count))
+}
+
+func (v *voucherBatch) Detail(ID int) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Detail] is executed", v.name))
+
+	batch, err := v.voucherBatchRepo.Get(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	summary, err := v.voucherBatchRepo.GetSummary(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(params.VoucherBatchDetailResponse{
+		VoucherBatch: batch,
+		Summary:      summary,
+	})
+}
+
+func (v *voucherBatch) Export(ID int) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Export] is executed", v.name))
+
+	batch, err := v.voucherBatchRepo.Get(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	vouchers, err := v.voucherBatchRepo.GetVouchers(ID)
+	if err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"campaign", "code", "long_period", "status", "redeemed_at"})
+	for _, voucher := range vouchers {
+		status := "available"
+		redeemedAt := ""
+		if voucher.UserID != nil {
+			status = "redeemed"
+			if voucher.RedeemedAt != nil {
+				redeemedAt = voucher.RedeemedAt.Format(time.RFC3339)
+			}
+		} else if voucher.RevokedAt != nil {
+			status = "revoked"
+		}
+
+		w.Write([]string{batch.Campaign, voucher.Token, strconv.Itoa(voucher.LongPeriod), status, redeemedAt})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logrus.Error(fmt.Sprintf("[%s][Export] %s", v.name, err.Error()))
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithData(generics.FileResponse{
+		Name:        fmt.Sprintf("voucher-batch-%d.csv", batch.ID),
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	})
+}
+
+func (v *voucherBatch) Revoke(ID int) appctx.Response {
+	logrus.Info(fmt.Sprintf("[%s][Revoke] is executed", v.name))
+
+	if _, err := v.voucherBatchRepo.Get(ID); err != nil {
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	revoked, err := v.voucherBatchRepo.Revoke(ID, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrVoucherBatchRevoked) {
+			return *appctx.NewResponse().WithErrors(err.Error()).WithCode(http.StatusBadRequest)
+		}
+		return *appctx.NewResponse().WithErrorObj(err)
+	}
+
+	return *appctx.NewResponse().WithMessage(fmt.Sprintf("%d vouchers revoked successfully", revoked))
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gitlab.com/project-quiz/utils/minio"
+	"gorm.io/gorm"
+)
+
+type attemptExpiry struct {
+	questionPackUsecase usecase.QuestionPackUsecase
+	interval            time.Duration
+	name                string
+}
+
+type AttemptExpiryWorker interface {
+	// Run finish expired question pack attempts periodically until ctx is done
+	Run(ctx context.Context)
+}
+
+func NewAttemptExpiryWorker(db *gorm.DB, minio minio.MinioStorageContract, interval time.Duration) AttemptExpiryWorker {
+	return &attemptExpiry{
+		questionPackUsecase: usecase.NewQuestionPackUsecase(db, minio),
+		interval:            interval,
+		name:                "Attempt Expiry Worker",
+	}
+}
+
+func (a *attemptExpiry) Run(ctx context.Context) {
+	logrus.Info(fmt.Sprintf("[%s] running every %s", a.name, a.interval))
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info(fmt.Sprintf("[%s] stopped", a.name))
+			return
+		case <-ticker.C:
+			a.tick()
+		}
+	}
+}
+
+func (a *attemptExpiry) tick() {
+	finished, err := a.questionPackUsecase.FinishExpiredAttempts(time.Now())
+	if err != nil {
+		logrus.Error(fmt.Sprintf("[%s] %s", a.name, err.Error()))
+		return
+	}
+
+	if finished > 0 {
+		logrus.Info(fmt.Sprintf("[%s] %d expired attempts finished", a.name, finished))
+	}
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/usecase"
+	"gorm.io/gorm"
+)
+
+type leaderboard struct {
+	leaderboardUsecase usecase.LeaderboardUsecase
+	interval           time.Duration
+	name               string
+}
+
+type LeaderboardWorker interface {
+	// Run refresh leaderboard snapshots on start and periodically until ctx is done
+	Run(ctx context.Context)
+}
+
+func NewLeaderboardWorker(db *gorm.DB, interval time.Duration) LeaderboardWorker {
+	return &leaderboard{
+		leaderboardUsecase: usecase.NewLeaderboardUsecase(db),
+		interval:           interval,
+		name:               "Leaderboard Worker",
+	}
+}
+
+func (l *leaderboard) Run(ctx context.Context) {
+	logrus.Info(fmt.Sprintf("[%s] running every %s", l.name, l.interval))
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	l.tick()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info(fmt.Sprintf("[%s] stopped", l.name))
+			return
+		case <-ticker.C:
+			l.tick()
+		}
+	}
+}
+
+func (l *leaderboard) tick() {
+	startTime := time.Now()
+
+	if err := l.leaderboardUsecase.Refresh(startTime); err != nil {
+		logrus.Error(fmt.Sprintf("[%s] %s", l.name, err.Error()))
+		return
+	}
+
+	logrus.Debug(fmt.Sprintf("[%s] refreshed in %s", l.name, time.Since(startTime)))
+}
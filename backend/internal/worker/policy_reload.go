@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/project-quiz/internal/rbac"
+)
+
+type policyReload struct {
+	enforcer *rbac.Enforcer
+	interval time.Duration
+	name     string
+}
+
+type PolicyReloadWorker interface {
+	// Run reload role policies periodically until ctx is done
+	Run(ctx context.Context)
+}
+
+func NewPolicyReloadWorker(enforcer *rbac.Enforcer, interval time.Duration) PolicyReloadWorker {
+	return &policyReload{
+		enforcer: enforcer,
+		interval: interval,
+		name:     "Policy Reload Worker",
+	}
+}
+
+func (p *policyReload) Run(ctx context.Context) {
+	logrus.Info(fmt.Sprintf("[%s] running every %s", p.name, p.interval))
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	// Policies are loaded when the enforcer is built, so there is nothing to reload on start
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info(fmt.Sprintf("[%s] stopped", p.name))
+			return
+		case <-ticker.C:
+			p.tick()
+		}
+	}
+}
+
+func (p *policyReload) tick() {
+	startTime := time.Now()
+
+	// A failed reload keeps the policies loaded before
+	if err := p.enforcer.Reload(); err != nil {
+		logrus.Error(fmt.Sprintf("[%s] %s", p.name, err.Error()))
+		return
+	}
+
+	logrus.Debug(fmt.Sprintf("[%s] reloaded in %s", p.name, time.Since(startTime)))
+}
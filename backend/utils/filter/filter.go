@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const DateLayout = "2006-01-02"
+
+var (
+	ErrUnknownSortField = errors.New("unknown sort field")
+	ErrInvalidSortOrder = errors.New("sort order must be asc or desc")
+	ErrInvalidDate      = errors.New("date must be formatted as YYYY-MM-DD or RFC3339")
+)
+
+// Param is the keyword, date range and sort requested by client
+type Param struct {
+	Q         string
+	StartDate string
+	EndDate   string
+	Sort      string
+	Order     string
+}
+
+// Config declares which columns a list may be searched, filtered and sorted by
+type Config struct {
+	// Columns matched by the keyword, case insensitive. Keyword is ignored when empty.
+	SearchColumns []string
+	// Column filtered by start and end date, created_at when empty
+	DateColumn string
+	// Sort field accepted from client and the column it sorts
+	SortFields map[string]string
+	// Sort field used when client does not ask for one, nothing is ordered when empty
+	DefaultSort string
+	// Order of the default sort, desc when empty
+	DefaultOrder string
+}
+
+// Where filters the query by keyword and date range. It goes before count, so an invalid sort
+// is rejected here too. An invalid param is added as error of the query.
+func Where(param Param, config Config) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		keyword := strings.TrimSpace(param.Q)
+		if keyword != "" && len(config.SearchColumns) > 0 {
+			var conditions []string
+			var values []interface{}
+			pattern := "%" + escapeLike(strings.ToLower(keyword)) + "%"
+			for _, column := range config.SearchColumns {
+				conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE ?", column))
+				values = append(values, pattern)
+			}
+			db = db.Where("("+strings.Join(conditions, " OR ")+")", values...)
+		}
+
+		dateColumn := config.DateColumn
+		if dateColumn == "" {
+			dateColumn = "created_at"
+		}
+
+		if param.StartDate != "" {
+			start, _, err := parseDate(param.StartDate)
+			if err != nil {
+				db.AddError(fmt.Errorf("start_date: %w", err))
+				return db
+			}
+			db = db.Where(dateColumn+" >= ?", start)
+		}
+
+		if param.EndDate != "" {
+			end, dateOnly, err := parseDate(param.EndDate)
+			if err != nil {
+				db.AddError(fmt.Errorf("end_date: %w", err))
+				return db
+			}
+			// End date without time covers the whole day
+			if dateOnly {
+				db = db.Where(dateColumn+" < ?", end.AddDate(0, 0, 1))
+			} else {
+				db = db.Where(dateColumn+" <= ?", end)
+			}
+		}
+
+		if _, err := Order(param, config); err != nil {
+			db.AddError(err)
+		}
+
+		return db
+	}
+}
+
+// Sort orders the query by the requested sort, it goes after count as ORDER BY is not allowed there
+func Sort(param Param, config Config) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		order, err := Order(param, config)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		if order != "" {
+			db = db.Order(order)
+		}
+
+		return db
+	}
+}
+
+// Order returns the ORDER BY of the requested sort, empty when nothing is sorted
+func Order(param Param, config Config) (string, error) {
+	field := strings.TrimSpace(param.Sort)
+	direction := strings.ToLower(strings.TrimSpace(param.Order))
+
+	if field == "" {
+		field = config.DefaultSort
+		if direction == "" {
+			direction = strings.ToLower(config.DefaultOrder)
+		}
+	}
+	if field == "" {
+		return "", nil
+	}
+
+	column, ok := config.SortFields[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownSortField, field)
+	}
+
+	switch direction {
+	case "":
+		direction = "desc"
+	case "asc", "desc":
+	default:
+		return "", ErrInvalidSortOrder
+	}
+
+	return column + " " + direction, nil
+}
+
+// parseDate tells whether the value only has a date so end date can include the whole day
+func parseDate(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(DateLayout, value, time.Local); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, false, ErrInvalidDate
+	}
+
+	return t, false, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type item struct {
+	ID   int
+	Name string
+}
+
+var config = Config{
+	SearchColumns: []string{"name", "code"},
+	SortFields:    map[string]string{"name": "name", "created_at": "created_at"},
+	DefaultSort:   "created_at",
+}
+
+func dryRun(t *testing.T, param Param) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []item
+	return db.Scopes(Where(param, config), Sort(param, config)).Find(&items)
+}
+
+func TestScope(t *testing.T) {
+	stmt := dryRun(t, Param{Q: "50%", StartDate: "2024-01-01", EndDate: "2024-01-31", Sort: "name", Order: "ASC"})
+	if stmt.Error != nil {
+		t.Fatal(stmt.Error)
+	}
+
+	sql := stmt.Statement.SQL.String()
+	for _, expected := range []string{
+		"(LOWER(name) LIKE $1 OR LOWER(code) LIKE $2)",
+		"created_at >= $3",
+		"created_at < $4",
+		"ORDER BY name asc",
+	} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("expected %q in %s", expected, sql)
+		}
+	}
+
+	if keyword := stmt.Statement.Vars[0]; keyword != `%50\%%` {
+		t.Errorf("expected escaped keyword, got %v", keyword)
+	}
+}
+
+func TestScopeDefaultSort(t *testing.T) {
+	stmt := dryRun(t, Param{})
+	if stmt.Error != nil {
+		t.Fatal(stmt.Error)
+	}
+
+	sql := stmt.Statement.SQL.String()
+	if strings.Contains(sql, "WHERE") || !strings.Contains(sql, "ORDER BY created_at desc") {
+		t.Errorf("unexpected query %s", sql)
+	}
+}
+
+func TestScopeRejectsInvalidParam(t *testing.T) {
+	for name, tc := range map[string]struct {
+		param Param
+		err   error
+	}{
+		"unknown sort field": {Param{Sort: "password"}, ErrUnknownSortField},
+		"invalid order":      {Param{Sort: "name", Order: "sideways"}, ErrInvalidSortOrder},
+		"invalid date":       {Param{StartDate: "01/02/2024"}, ErrInvalidDate},
+	} {
+		if err := dryRun(t, tc.param).Error; !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", name, tc.err, err)
+		}
+	}
+}
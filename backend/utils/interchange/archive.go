@@ -0,0 +1,102 @@
+package interchange
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Uncompressed size limit of an archive, protects against zip bombs
+const maxArchiveSize = 200 << 20
+
+var ErrArchiveTooLarge = errors.New("archive is too large")
+
+type archiveFile struct {
+	Name    string
+	Content []byte
+}
+
+func isZip(content []byte) bool {
+	return bytes.HasPrefix(content, []byte("PK\x03\x04"))
+}
+
+func writeZip(files []archiveFile) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		w, err := writer.Create(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readZip returns the files of the archive keyed by their cleaned path
+func readZip(content []byte) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	files := make(map[string][]byte)
+	var total int64
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxArchiveSize-total+1))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		total += int64(len(data))
+		if total > maxArchiveSize {
+			return nil, ErrArchiveTooLarge
+		}
+
+		files[cleanPath(file.Name)] = data
+	}
+
+	return files, nil
+}
+
+// mediaFiles returns the media of the bank sorted by name, placed inside dir
+func mediaFiles(bank Bank, dir string) []archiveFile {
+	names := make([]string, 0, len(bank.Media))
+	for name := range bank.Media {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]archiveFile, len(names))
+	for i, name := range names {
+		files[i] = archiveFile{Name: path.Join(dir, name), Content: bank.Media[name]}
+	}
+
+	return files
+}
+
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+}
@@ -0,0 +1,161 @@
+// Package interchange converts question banks from and to the formats used by
+// other learning tools: QTI 2.1 packages, Moodle XML and Moodle GIFT.
+package interchange
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Question types and scoring modes, same values as stored in the questions table
+const (
+	TypeSingleChoice   = "single_choice"
+	TypeMultipleAnswer = "multiple_answer"
+	TypeTrueFalse      = "true_false"
+	TypeNumeric        = "numeric"
+	TypeShortText      = "short_text"
+	TypeOrdering       = "ordering"
+	TypeMatching       = "matching"
+
+	ScoringAllOrNothing = "all_or_nothing"
+	ScoringPartial      = "partial"
+)
+
+const (
+	FormatQTI       = "qti"
+	FormatMoodleXML = "moodle_xml"
+	FormatGIFT      = "gift"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format, use qti, moodle_xml or gift")
+	ErrEmptyBank     = errors.New("file does not contain any question")
+)
+
+// Formats lists the supported format names
+var Formats = []string{FormatQTI, FormatMoodleXML, FormatGIFT}
+
+// Bank is a question bank independent of the file format. Images are referenced
+// by name and their content is kept in Media.
+type Bank struct {
+	Questions []Question
+	Media     map[string][]byte
+}
+
+type Question struct {
+	Code        string
+	Type        string
+	ScoringMode string
+	Material    string
+	Body        string
+	Image       string
+	// Options of choice questions, in the correct order for ordering question
+	Options          []Option
+	NumericAnswer    *float64
+	NumericTolerance float64
+	AcceptedAnswers  []string
+	CaseSensitive    bool
+	Tags             []string
+	Solution         string
+	SolutionImage    string
+	// Problems found while decoding the question, the question is not usable when set
+	Errors []string
+}
+
+type Option struct {
+	Body      string
+	Image     string
+	IsCorrect bool
+	// Pair of the option on matching question
+	MatchBody string
+}
+
+// File is the encoded bank
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Format encodes and decodes question banks
+type Format interface {
+	// Encode the bank, questions the format can not express are reported as skipped by their code
+	Encode(bank Bank) (file File, skipped []string, err error)
+	// Decode the bank from the file content
+	Decode(name string, content []byte) (Bank, error)
+}
+
+// Get returns the format by its name
+func Get(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case FormatQTI:
+		return qti{}, nil
+	case FormatMoodleXML:
+		return moodleXML{}, nil
+	case FormatGIFT:
+		return gift{}, nil
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// AddMedia store image content in the bank and returns the name it is referred by.
+// Names are made unique by prefixing them with their number.
+func (b *Bank) AddMedia(filePath string, content []byte) string {
+	if b.Media == nil {
+		b.Media = make(map[string][]byte)
+	}
+
+	name := sanitizeMediaName(path.Base(filePath))
+	if _, ok := b.Media[name]; ok {
+		name = fmt.Sprintf("%d_%s", len(b.Media)+1, name)
+	}
+	b.Media[name] = content
+
+	return name
+}
+
+func sanitizeMediaName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// correctIndexes returns the index of correct options
+func (q Question) correctIndexes() []int {
+	indexes := []int{}
+	for i, option := range q.Options {
+		if option.IsCorrect {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// IsTrue tells the answer of true/false question, which is true when the first option is correct
+func (q Question) IsTrue() bool {
+	return len(q.Options) > 0 && q.Options[0].IsCorrect
+}
+
+// trueFalseOptions builds the options of true/false question
+func trueFalseOptions(isTrue bool) []Option {
+	return []Option{
+		{Body: "Benar", IsCorrect: isTrue},
+		{Body: "Salah", IsCorrect: !isTrue},
+	}
+}
+
+func fallbackCode(code string, i int) string {
+	if code != "" {
+		return code
+	}
+
+	return fmt.Sprintf("q%d", i+1)
+}
@@ -0,0 +1,99 @@
+package interchange
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// readContent reads the mixed content of the current element up to its end, returning its
+// text and the source of its first image. handle is called for every child element and
+// reports whether it consumed the element itself.
+func readContent(d *xml.Decoder, handle func(xml.StartElement) (bool, error)) (string, string, error) {
+	var text strings.Builder
+	image := ""
+	depth := 0
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return "", "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if handle != nil {
+				handled, err := handle(t)
+				if err != nil {
+					return "", "", err
+				}
+				if handled {
+					continue
+				}
+			}
+
+			if t.Name.Local == "img" && image == "" {
+				image = attrValue(t, "src")
+			}
+			if isBlockElement(t.Name.Local) {
+				text.WriteString("\n")
+			}
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return normalizeText(text.String()), image, nil
+			}
+			depth--
+			if isBlockElement(t.Name.Local) {
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			text.Write(t)
+		}
+	}
+}
+
+func attrValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+func isBlockElement(name string) bool {
+	switch name {
+	case "p", "div", "br", "li", "prompt":
+		return true
+	}
+
+	return false
+}
+
+// normalizeText trims every line and drops the empty ones
+func normalizeText(text string) string {
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// rootElement returns the local name of the document root element
+func rootElement(content []byte) string {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package interchange
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// gift encodes the bank as Moodle GIFT text. Banks with images are written as zip holding
+// questions.gift and the images, referred with @@PLUGINFILE@@ like Moodle does.
+// GIFT can not express ordering questions, they are skipped. Case sensitivity of short text
+// answers and all or nothing grading of multiple answer and matching questions are lost.
+type gift struct{}
+
+const giftFile = "questions.gift"
+
+var (
+	giftSpecial    = strings.NewReplacer(`\`, `\\`, `~`, `\~`, `=`, `\=`, `#`, `\#`, `{`, `\{`, `}`, `\}`, `:`, `\:`, "\r", "", "\n", `\n`)
+	giftTagPattern = regexp.MustCompile(`\[tag:([^\]]+)\]`)
+)
+
+func (gift) Encode(bank Bank) (File, []string, error) {
+	var text strings.Builder
+	skipped := []string{}
+	category := ""
+	encoded := 0
+	media := map[string]bool{}
+
+	for i, question := range bank.Questions {
+		code := fallbackCode(question.Code, i)
+		answers, err := giftEncodeAnswers(question, bank.Media, media)
+		if err != nil {
+			skipped = append(skipped, code)
+			continue
+		}
+
+		if question.Material != "" && question.Material != category {
+			category = question.Material
+			fmt.Fprintf(&text, "$CATEGORY: $course$/top/%s\n\n", category)
+		}
+
+		if len(question.Tags) > 0 {
+			text.WriteString("//")
+			for _, tag := range question.Tags {
+				fmt.Fprintf(&text, " [tag:%s]", tag)
+			}
+			text.WriteString("\n")
+		}
+
+		body := giftHTML(question.Body, question.Image, bank.Media, media)
+		fmt.Fprintf(&text, "::%s::[html]%s{\n%s", giftEscape(code), giftEscape(body), answers)
+		if question.Solution != "" || question.SolutionImage != "" {
+			fmt.Fprintf(&text, "####%s\n", giftEscape(giftHTML(question.Solution, question.SolutionImage, bank.Media, media)))
+		}
+		text.WriteString("}\n\n")
+		encoded++
+	}
+
+	if encoded == 0 {
+		return File{}, skipped, ErrEmptyBank
+	}
+
+	if len(media) == 0 {
+		return File{Name: "question-bank.gift", ContentType: "text/plain; charset=utf-8", Content: []byte(text.String())}, skipped, nil
+	}
+
+	used := Bank{Media: make(map[string][]byte)}
+	for name := range media {
+		used.Media[name] = bank.Media[name]
+	}
+	archive, err := writeZip(append([]archiveFile{{Name: giftFile, Content: []byte(text.String())}}, mediaFiles(used, "")...))
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	return File{Name: "question-bank-gift.zip", ContentType: "application/zip", Content: archive}, skipped, nil
+}
+
+// giftEncodeAnswers writes the answer block of the question, one answer per line
+func giftEncodeAnswers(question Question, media map[string][]byte, used map[string]bool) (string, error) {
+	var answers strings.Builder
+	option := func(prefix string, option Option) {
+		fmt.Fprintf(&answers, "\t%s%s\n", prefix, giftEscape(giftHTML(option.Body, option.Image, media, used)))
+	}
+
+	switch question.Type {
+	case TypeSingleChoice:
+		for _, o := range question.Options {
+			prefix := "~"
+			if o.IsCorrect {
+				prefix = "="
+			}
+			option(prefix, o)
+		}
+	case TypeMultipleAnswer:
+		correct := len(question.correctIndexes())
+		if correct == 0 {
+			return "", fmt.Errorf("multiple answer question has no correct option")
+		}
+		weight := formatFraction(float64(int(100/float64(correct)*100000)) / 100000)
+		for _, o := range question.Options {
+			prefix := "~%-" + weight + "%"
+			if o.IsCorrect {
+				prefix = "~%" + weight + "%"
+			}
+			option(prefix, o)
+		}
+	case TypeTrueFalse:
+		if question.IsTrue() {
+			answers.WriteString("\tTRUE\n")
+		} else {
+			answers.WriteString("\tFALSE\n")
+		}
+	case TypeNumeric:
+		if question.NumericAnswer == nil {
+			return "", fmt.Errorf("numeric question has no answer")
+		}
+		fmt.Fprintf(&answers, "\t#%s:%s\n", strconv.FormatFloat(*question.NumericAnswer, 'f', -1, 64), strconv.FormatFloat(question.NumericTolerance, 'f', -1, 64))
+	case TypeShortText:
+		for _, accepted := range question.AcceptedAnswers {
+			fmt.Fprintf(&answers, "\t=%s\n", giftEscape(accepted))
+		}
+	case TypeMatching:
+		for _, o := range question.Options {
+			fmt.Fprintf(&answers, "\t=%s -> %s\n", giftEscape(giftHTML(o.Body, o.Image, media, used)), giftEscape(o.MatchBody))
+		}
+	default:
+		return "", fmt.Errorf("question type %s is not supported by GIFT", question.Type)
+	}
+
+	return answers.String(), nil
+}
+
+// giftHTML appends the image to the text and records it as used
+func giftHTML(text, image string, media map[string][]byte, used map[string]bool) string {
+	if _, ok := media[image]; !ok || image == "" {
+		return text
+	}
+
+	used[image] = true
+	return text + fmt.Sprintf(`<img src="%s%s" alt="">`, moodlePluginFile, url.PathEscape(image))
+}
+
+func giftEscape(text string) string {
+	return giftSpecial.Replace(text)
+}
+
+// giftUnescape removes the escaping backslashes, \n becomes new line
+func giftUnescape(text string) string {
+	var unescaped strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+			if text[i] == 'n' {
+				unescaped.WriteByte('\n')
+			} else {
+				unescaped.WriteByte(text[i])
+			}
+			continue
+		}
+		unescaped.WriteByte(text[i])
+	}
+
+	return unescaped.String()
+}
+
+// giftIndex returns the index of the first unescaped occurrence of substr
+func giftIndex(text, substr string) int {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(text[i:], substr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (gift) Decode(name string, content []byte) (Bank, error) {
+	bank := Bank{Media: make(map[string][]byte)}
+	files := map[string][]byte{}
+
+	if isZip(content) {
+		var err error
+		files, err = readZip(content)
+		if err != nil {
+			return bank, err
+		}
+
+		content = nil
+		for name, data := range files {
+			if ext := strings.ToLower(path.Ext(name)); ext == ".gift" || ext == ".txt" {
+				content = data
+				break
+			}
+		}
+		if content == nil {
+			return bank, fmt.Errorf("archive does not contain a .gift file")
+		}
+	}
+
+	category := ""
+	tags := []string{}
+	block := []string{}
+	flush := func() {
+		if len(block) > 0 {
+			bank.Questions = append(bank.Questions, giftDecodeQuestion(strings.Join(block, "\n"), category, tags, files, &bank))
+		}
+		block = []string{}
+		tags = []string{}
+	}
+
+	text := strings.TrimPrefix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\ufeff")
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+			for _, match := range giftTagPattern.FindAllStringSubmatch(trimmed, -1) {
+				tags = append(tags, strings.TrimSpace(match[1]))
+			}
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			flush()
+			category = moodleCategoryName(strings.TrimPrefix(trimmed, "$CATEGORY:"))
+		default:
+			block = append(block, trimmed)
+		}
+	}
+	flush()
+
+	if len(bank.Questions) == 0 {
+		return bank, ErrEmptyBank
+	}
+
+	return bank, nil
+}
+
+func giftDecodeQuestion(text, category string, tags []string, files map[string][]byte, bank *Bank) Question {
+	question := Question{
+		Material:    category,
+		ScoringMode: ScoringAllOrNothing,
+		Tags:        tags,
+	}
+
+	if strings.HasPrefix(text, "::") {
+		if end := giftIndex(text[2:], "::"); end >= 0 {
+			question.Code = strings.TrimSpace(giftUnescape(text[2 : end+2]))
+			text = strings.TrimSpace(text[end+4:])
+		}
+	}
+
+	isHTML := false
+	if strings.HasPrefix(text, "[") {
+		if end := strings.Index(text, "]"); end > 0 {
+			isHTML = text[1:end] == "html"
+			text = text[end+1:]
+		}
+	}
+
+	open := giftIndex(text, "{")
+	if open < 0 {
+		question.Code = fallbackCode(question.Code, len(bank.Questions))
+		question.Errors = append(question.Errors, "description without answer is not supported")
+		return question
+	}
+	closing := giftIndex(text[open:], "}")
+	if closing < 0 {
+		question.Errors = append(question.Errors, "answer block is not closed")
+		return question
+	}
+	closing += open
+
+	// Text after the answer block belongs to the body, the answer is the missing word
+	body := strings.TrimSpace(text[:open])
+	if rest := strings.TrimSpace(text[closing+1:]); rest != "" {
+		body += " _____ " + rest
+	}
+
+	decodeText := func(raw string) (string, string) {
+		raw = strings.TrimSpace(giftUnescape(raw))
+		if !isHTML {
+			return raw, ""
+		}
+		text, image, err := giftDecodeImage(raw, files, bank)
+		if err != nil {
+			question.Errors = append(question.Errors, err.Error())
+		}
+		return text, image
+	}
+	question.Body, question.Image = decodeText(body)
+
+	answers := text[open+1 : closing]
+	if feedback := giftIndex(answers, "####"); feedback >= 0 {
+		question.Solution, question.SolutionImage = decodeText(answers[feedback+4:])
+		answers = answers[:feedback]
+	}
+	answers = strings.TrimSpace(answers)
+
+	switch upper := strings.ToUpper(strings.SplitN(answers, "#", 2)[0]); {
+	case answers == "":
+		question.Errors = append(question.Errors, "essay question is not supported")
+	case upper == "T" || upper == "TRUE" || upper == "F" || upper == "FALSE":
+		question.Type = TypeTrueFalse
+		question.Options = trueFalseOptions(strings.HasPrefix(upper, "T"))
+	case strings.HasPrefix(answers, "#"):
+		question.Type = TypeNumeric
+		giftDecodeNumeric(&question, answers[1:])
+	default:
+		giftDecodeChoices(&question, answers, decodeText)
+	}
+
+	return question
+}
+
+type giftAnswer struct {
+	correct bool
+	weight  *float64
+	text    string
+}
+
+// giftSplitAnswers splits the answer block on unescaped = and ~
+func giftSplitAnswers(answers string) []giftAnswer {
+	list := []giftAnswer{}
+	start := -1
+	add := func(end int) {
+		if start < 0 {
+			return
+		}
+		answer := giftAnswer{correct: answers[start] == '=', text: strings.TrimSpace(answers[start+1 : end])}
+		if strings.HasPrefix(answer.text, "%") {
+			if end := strings.Index(answer.text[1:], "%"); end >= 0 {
+				if weight, err := strconv.ParseFloat(answer.text[1:end+1], 64); err == nil {
+					answer.weight = &weight
+				}
+				answer.text = strings.TrimSpace(answer.text[end+2:])
+			}
+		}
+		// Feedback of the answer is dropped
+		if feedback := giftIndex(answer.text, "#"); feedback >= 0 {
+			answer.text = strings.TrimSpace(answer.text[:feedback])
+		}
+		list = append(list, answer)
+	}
+
+	for i := 0; i < len(answers); i++ {
+		switch answers[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			add(i)
+			start = i
+		}
+	}
+	add(len(answers))
+
+	return list
+}
+
+func giftDecodeChoices(question *Question, answers string, decodeText func(string) (string, string)) {
+	list := giftSplitAnswers(answers)
+	if len(list) == 0 {
+		question.Errors = append(question.Errors, "answer block is not valid")
+		return
+	}
+
+	hasWrong, isMatching, weighted := false, false, false
+	for _, answer := range list {
+		if !answer.correct {
+			hasWrong = true
+		}
+		if answer.correct && giftIndex(answer.text, "->") >= 0 {
+			isMatching = true
+		}
+		// Weighted answers mean several options carry the mark
+		if answer.weight != nil && *answer.weight > 0 && (!answer.correct || *answer.weight < 100) {
+			weighted = true
+		}
+	}
+
+	switch {
+	case isMatching:
+		question.Type = TypeMatching
+		question.ScoringMode = ScoringPartial
+		for _, answer := range list {
+			arrow := giftIndex(answer.text, "->")
+			if arrow < 0 {
+				question.Errors = append(question.Errors, fmt.Sprintf("matching answer %q must be written as \"left -> right\"", answer.text))
+				continue
+			}
+			body, image := decodeText(answer.text[:arrow])
+			question.Options = append(question.Options, Option{Body: body, Image: image, IsCorrect: true, MatchBody: strings.TrimSpace(giftUnescape(answer.text[arrow+2:]))})
+		}
+	case !hasWrong && !weighted:
+		question.Type = TypeShortText
+		for _, answer := range list {
+			question.AcceptedAnswers = append(question.AcceptedAnswers, strings.TrimSpace(giftUnescape(answer.text)))
+		}
+	default:
+		question.Type = TypeSingleChoice
+		if weighted {
+			question.Type = TypeMultipleAnswer
+			question.ScoringMode = ScoringPartial
+		}
+		for _, answer := range list {
+			body, image := decodeText(answer.text)
+			isCorrect := answer.correct || (answer.weight != nil && *answer.weight > 0)
+			question.Options = append(question.Options, Option{Body: body, Image: image, IsCorrect: isCorrect})
+		}
+	}
+}
+
+// giftDecodeNumeric reads "value:tolerance", "min..max" or the first full mark "=value:tolerance"
+func giftDecodeNumeric(question *Question, answers string) {
+	answers = strings.TrimSpace(answers)
+	if strings.HasPrefix(answers, "=") {
+		for _, answer := range giftSplitAnswers(answers) {
+			if answer.correct && (answer.weight == nil || *answer.weight >= 100) {
+				answers = answer.text
+				break
+			}
+		}
+	}
+
+	var value, tolerance float64
+	var err error
+	if parts := strings.SplitN(answers, "..", 2); len(parts) == 2 {
+		var min, max float64
+		if min, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err == nil {
+			max, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		}
+		value, tolerance = (min+max)/2, (max-min)/2
+	} else {
+		parts := strings.SplitN(giftUnescape(answers), ":", 2)
+		value, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err == nil && len(parts) == 2 {
+			tolerance, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		}
+	}
+
+	if err != nil {
+		question.Errors = append(question.Errors, fmt.Sprintf("numeric answer %q is not valid", answers))
+		return
+	}
+	question.NumericAnswer = &value
+	question.NumericTolerance = tolerance
+}
+
+// giftDecodeImage takes the first @@PLUGINFILE@@ image out of the text and loads it from the archive
+func giftDecodeImage(text string, files map[string][]byte, bank *Bank) (string, string, error) {
+	match := moodleImagePattern.FindStringSubmatchIndex(text)
+	if match == nil {
+		return text, "", nil
+	}
+
+	name, _ := url.PathUnescape(text[match[2]:match[3]])
+	text = strings.TrimSpace(text[:match[0]] + text[match[1]:])
+	for _, candidate := range []string{cleanPath(name), cleanPath("media/" + name)} {
+		if content, ok := files[candidate]; ok {
+			return text, bank.AddMedia(candidate, content), nil
+		}
+	}
+
+	return text, "", fmt.Errorf("image %s is missing from the archive", name)
+}
@@ -0,0 +1,179 @@
+package interchange
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func sampleBank() Bank {
+	answer := 9.81
+	bank := Bank{}
+	image := bank.AddMedia("question/1/file_1.png", []byte("\x89PNG body image"))
+	optionImage := bank.AddMedia("question/1/option/file_1.png", []byte("\x89PNG option image"))
+	solutionImage := bank.AddMedia("question/1/solution/file_2.png", []byte("\x89PNG solution image"))
+
+	bank.Questions = []Question{
+		{
+			Code: "kq1", Type: TypeSingleChoice, ScoringMode: ScoringAllOrNothing, Material: "Matematika",
+			Body: "1 + 1 = {?}", Image: image, Tags: []string{"aljabar", "dasar"},
+			Options: []Option{
+				{Body: "2", IsCorrect: true},
+				{Body: "3: salah", Image: optionImage},
+			},
+			Solution: "Jelas #1", SolutionImage: solutionImage,
+		},
+		{
+			Code: "kq2", Type: TypeMultipleAnswer, ScoringMode: ScoringPartial, Material: "Matematika",
+			Body: "Bilangan prima",
+			Options: []Option{
+				{Body: "2", IsCorrect: true},
+				{Body: "3", IsCorrect: true},
+				{Body: "4"},
+			},
+		},
+		{
+			Code: "kq3", Type: TypeTrueFalse, ScoringMode: ScoringAllOrNothing, Material: "Fisika",
+			Body:    "Bumi itu bulat",
+			Options: trueFalseOptions(true),
+		},
+		{
+			Code: "kq4", Type: TypeNumeric, ScoringMode: ScoringAllOrNothing, Material: "Fisika",
+			Body: "Percepatan gravitasi", NumericAnswer: &answer, NumericTolerance: 0.01,
+		},
+		{
+			Code: "kq5", Type: TypeShortText, ScoringMode: ScoringAllOrNothing, Material: "Fisika",
+			Body: "Ibu kota Indonesia", AcceptedAnswers: []string{"Jakarta", "DKI Jakarta"},
+		},
+		{
+			Code: "kq6", Type: TypeMatching, ScoringMode: ScoringPartial, Material: "Fisika",
+			Body: "Pasangkan satuan",
+			Options: []Option{
+				{Body: "Gaya", IsCorrect: true, MatchBody: "Newton"},
+				{Body: "Energi", IsCorrect: true, MatchBody: "Joule"},
+			},
+		},
+		{
+			Code: "kq7", Type: TypeOrdering, ScoringMode: ScoringAllOrNothing, Material: "Fisika",
+			Body: "Urutkan",
+			Options: []Option{
+				{Body: "satu", IsCorrect: true},
+				{Body: "dua", IsCorrect: true},
+			},
+		},
+	}
+
+	return bank
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range Formats {
+		t.Run(name, func(t *testing.T) {
+			format, err := Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			original := sampleBank()
+			file, skipped, err := format.Encode(original)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := format.Decode(file.Name, file.Content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := original.Questions
+			if name == FormatGIFT {
+				if !reflect.DeepEqual(skipped, []string{"kq7"}) {
+					t.Errorf("expected ordering question to be skipped, got %v", skipped)
+				}
+				expected = expected[:len(expected)-1]
+			}
+
+			if len(decoded.Questions) != len(expected) {
+				t.Fatalf("expected %d questions, got %d", len(expected), len(decoded.Questions))
+			}
+
+			for i, want := range expected {
+				got := decoded.Questions[i]
+				if len(got.Errors) > 0 {
+					t.Errorf("%s: unexpected errors %v", want.Code, got.Errors)
+				}
+				if got.Code != want.Code || got.Type != want.Type || got.ScoringMode != want.ScoringMode || got.Material != want.Material || got.Body != want.Body {
+					t.Errorf("%s: got %+v", want.Code, got)
+				}
+				if len(got.Options) != len(want.Options) {
+					t.Errorf("%s: expected %d options, got %d", want.Code, len(want.Options), len(got.Options))
+					continue
+				}
+				for j, option := range want.Options {
+					if got.Options[j].Body != option.Body || got.Options[j].IsCorrect != option.IsCorrect || got.Options[j].MatchBody != option.MatchBody {
+						t.Errorf("%s: option %d got %+v", want.Code, j, got.Options[j])
+					}
+					if !sameMedia(original, option.Image, decoded, got.Options[j].Image) {
+						t.Errorf("%s: option %d image is not preserved", want.Code, j)
+					}
+				}
+				if !sameMedia(original, want.Image, decoded, got.Image) || !sameMedia(original, want.SolutionImage, decoded, got.SolutionImage) {
+					t.Errorf("%s: images are not preserved", want.Code)
+				}
+				if got.Solution != want.Solution {
+					t.Errorf("%s: expected solution %q, got %q", want.Code, want.Solution, got.Solution)
+				}
+				if !reflect.DeepEqual(got.Tags, want.Tags) && len(got.Tags)+len(want.Tags) > 0 {
+					t.Errorf("%s: expected tags %v, got %v", want.Code, want.Tags, got.Tags)
+				}
+				if !reflect.DeepEqual(got.AcceptedAnswers, want.AcceptedAnswers) {
+					t.Errorf("%s: expected accepted answers %v, got %v", want.Code, want.AcceptedAnswers, got.AcceptedAnswers)
+				}
+				if want.NumericAnswer != nil && (got.NumericAnswer == nil || *got.NumericAnswer != *want.NumericAnswer || got.NumericTolerance != want.NumericTolerance) {
+					t.Errorf("%s: numeric answer is not preserved", want.Code)
+				}
+			}
+		})
+	}
+}
+
+func sameMedia(original Bank, originalName string, decoded Bank, decodedName string) bool {
+	if originalName == "" || decodedName == "" {
+		return originalName == decodedName
+	}
+
+	return bytes.Equal(original.Media[originalName], decoded.Media[decodedName])
+}
+
+func TestGetUnknownFormat(t *testing.T) {
+	if _, err := Get("scorm"); err != ErrUnknownFormat {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestDecodeForeignGIFT(t *testing.T) {
+	content := "// question: 1\n::Q1:: What is 2+2? {~3 =4#right ~5}\n\n::Q2:: Range {#1..3}\n\n::Q3::Essay{}\n"
+
+	bank, err := gift{}.Decode("bank.gift", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bank.Questions) != 3 {
+		t.Fatalf("expected 3 questions, got %d", len(bank.Questions))
+	}
+
+	q1 := bank.Questions[0]
+	if q1.Type != TypeSingleChoice || len(q1.Options) != 3 || !q1.Options[1].IsCorrect || q1.Options[1].Body != "4" {
+		t.Errorf("unexpected choice question %+v", q1)
+	}
+
+	q2 := bank.Questions[1]
+	if q2.Type != TypeNumeric || q2.NumericAnswer == nil || *q2.NumericAnswer != 2 || q2.NumericTolerance != 1 {
+		t.Errorf("unexpected numeric question %+v", q2)
+	}
+
+	if len(bank.Questions[2].Errors) == 0 {
+		t.Errorf("expected essay question to be reported")
+	}
+}
@@ -0,0 +1,431 @@
+package interchange
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// moodleXML encodes the bank as Moodle XML with the images embedded as base64 files.
+// Materials are written as categories. Multiple answer questions graded all or nothing use
+// the multichoiceset type, matching questions are always partially graded by Moodle and
+// come back as partial.
+type moodleXML struct{}
+
+const moodlePluginFile = "@@PLUGINFILE@@/"
+
+var moodleImagePattern = regexp.MustCompile(`<img[^>]*src="@@PLUGINFILE@@/([^"]+)"[^>]*>`)
+
+type moodleQuiz struct {
+	XMLName   xml.Name         `xml:"quiz"`
+	Questions []moodleQuestion `xml:"question"`
+}
+
+type moodleQuestion struct {
+	Type            string              `xml:"type,attr"`
+	Category        *moodleText         `xml:"category"`
+	Name            *moodleText         `xml:"name"`
+	QuestionText    *moodleRichText     `xml:"questiontext"`
+	GeneralFeedback *moodleRichText     `xml:"generalfeedback"`
+	DefaultGrade    string              `xml:"defaultgrade,omitempty"`
+	Single          string              `xml:"single,omitempty"`
+	ShuffleAnswers  string              `xml:"shuffleanswers,omitempty"`
+	UseCase         string              `xml:"usecase,omitempty"`
+	GradingType     string              `xml:"gradingtype,omitempty"`
+	Answers         []moodleAnswer      `xml:"answer"`
+	Subquestions    []moodleSubquestion `xml:"subquestion"`
+	Tags            []moodleText        `xml:"tags>tag"`
+}
+
+type moodleText struct {
+	Text string `xml:"text"`
+}
+
+type moodleCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type moodleRichText struct {
+	Format string       `xml:"format,attr,omitempty"`
+	Text   moodleCDATA  `xml:"text"`
+	Files  []moodleFile `xml:"file"`
+}
+
+type moodleFile struct {
+	Name     string `xml:"name,attr"`
+	Path     string `xml:"path,attr"`
+	Encoding string `xml:"encoding,attr"`
+	Content  string `xml:",chardata"`
+}
+
+type moodleAnswer struct {
+	Fraction  string       `xml:"fraction,attr"`
+	Format    string       `xml:"format,attr,omitempty"`
+	Text      moodleCDATA  `xml:"text"`
+	Files     []moodleFile `xml:"file"`
+	Tolerance string       `xml:"tolerance,omitempty"`
+}
+
+type moodleSubquestion struct {
+	Format string       `xml:"format,attr,omitempty"`
+	Text   moodleCDATA  `xml:"text"`
+	Files  []moodleFile `xml:"file"`
+	Answer moodleText   `xml:"answer"`
+}
+
+func (moodleXML) Encode(bank Bank) (File, []string, error) {
+	quiz := moodleQuiz{}
+	skipped := []string{}
+	category := ""
+
+	for i, question := range bank.Questions {
+		code := fallbackCode(question.Code, i)
+		item, err := moodleEncodeQuestion(code, question, bank.Media)
+		if err != nil {
+			skipped = append(skipped, code)
+			continue
+		}
+
+		if question.Material != "" && question.Material != category {
+			category = question.Material
+			quiz.Questions = append(quiz.Questions, moodleQuestion{
+				Type:     "category",
+				Category: &moodleText{Text: "$course$/top/" + category},
+			})
+		}
+		quiz.Questions = append(quiz.Questions, item)
+	}
+
+	if len(quiz.Questions) == 0 {
+		return File{}, skipped, ErrEmptyBank
+	}
+
+	content, err := xml.MarshalIndent(quiz, "", "  ")
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	return File{
+		Name:        "question-bank-moodle.xml",
+		ContentType: "application/xml",
+		Content:     append([]byte(xml.Header), content...),
+	}, skipped, nil
+}
+
+func moodleEncodeQuestion(code string, question Question, media map[string][]byte) (moodleQuestion, error) {
+	item := moodleQuestion{
+		Name:         &moodleText{Text: code},
+		QuestionText: moodleEncodeText(question.Body, question.Image, media),
+		DefaultGrade: "1",
+	}
+	for _, tag := range question.Tags {
+		item.Tags = append(item.Tags, moodleText{Text: tag})
+	}
+	if question.Solution != "" || question.SolutionImage != "" {
+		item.GeneralFeedback = moodleEncodeText(question.Solution, question.SolutionImage, media)
+	}
+
+	answer := func(fraction float64, option Option) moodleAnswer {
+		text := moodleEncodeText(option.Body, option.Image, media)
+		return moodleAnswer{Fraction: formatFraction(fraction), Format: "html", Text: text.Text, Files: text.Files}
+	}
+
+	switch question.Type {
+	case TypeSingleChoice:
+		item.Type = "multichoice"
+		item.Single = "true"
+		item.ShuffleAnswers = "true"
+		for _, option := range question.Options {
+			fraction := 0.0
+			if option.IsCorrect {
+				fraction = 100
+			}
+			item.Answers = append(item.Answers, answer(fraction, option))
+		}
+	case TypeMultipleAnswer:
+		item.Type = "multichoiceset"
+		item.ShuffleAnswers = "true"
+		share := 100.0
+		if question.ScoringMode == ScoringPartial {
+			item.Type = "multichoice"
+			item.Single = "false"
+			if correct := len(question.correctIndexes()); correct > 0 {
+				share = 100 / float64(correct)
+			}
+		}
+		for _, option := range question.Options {
+			fraction := 0.0
+			if option.IsCorrect {
+				fraction = share
+			} else if question.ScoringMode == ScoringPartial {
+				fraction = -share
+			}
+			item.Answers = append(item.Answers, answer(fraction, option))
+		}
+	case TypeTrueFalse:
+		item.Type = "truefalse"
+		fractions := []float64{0, 100}
+		if question.IsTrue() {
+			fractions = []float64{100, 0}
+		}
+		item.Answers = []moodleAnswer{
+			{Fraction: formatFraction(fractions[0]), Format: "moodle_auto_format", Text: moodleCDATA{Value: "true"}},
+			{Fraction: formatFraction(fractions[1]), Format: "moodle_auto_format", Text: moodleCDATA{Value: "false"}},
+		}
+	case TypeNumeric:
+		if question.NumericAnswer == nil {
+			return item, fmt.Errorf("numeric question %s has no answer", code)
+		}
+		item.Type = "numerical"
+		item.Answers = []moodleAnswer{{
+			Fraction:  "100",
+			Format:    "moodle_auto_format",
+			Text:      moodleCDATA{Value: strconv.FormatFloat(*question.NumericAnswer, 'f', -1, 64)},
+			Tolerance: strconv.FormatFloat(question.NumericTolerance, 'f', -1, 64),
+		}}
+	case TypeShortText:
+		item.Type = "shortanswer"
+		item.UseCase = "0"
+		if question.CaseSensitive {
+			item.UseCase = "1"
+		}
+		for _, accepted := range question.AcceptedAnswers {
+			item.Answers = append(item.Answers, moodleAnswer{Fraction: "100", Format: "moodle_auto_format", Text: moodleCDATA{Value: accepted}})
+		}
+	case TypeOrdering:
+		// The fraction of ordering answer is its correct position
+		item.Type = "ordering"
+		item.GradingType = "-1"
+		if question.ScoringMode == ScoringPartial {
+			item.GradingType = "0"
+		}
+		for i, option := range question.Options {
+			item.Answers = append(item.Answers, answer(float64(i+1), option))
+		}
+	case TypeMatching:
+		item.Type = "matching"
+		item.ShuffleAnswers = "true"
+		for _, option := range question.Options {
+			text := moodleEncodeText(option.Body, option.Image, media)
+			item.Subquestions = append(item.Subquestions, moodleSubquestion{
+				Format: "html",
+				Text:   text.Text,
+				Files:  text.Files,
+				Answer: moodleText{Text: option.MatchBody},
+			})
+		}
+	default:
+		return item, fmt.Errorf("unknown question type %s", question.Type)
+	}
+
+	return item, nil
+}
+
+// moodleEncodeText writes the text as html with the image appended and embedded
+func moodleEncodeText(text, image string, media map[string][]byte) *moodleRichText {
+	rich := &moodleRichText{Format: "html", Text: moodleCDATA{Value: text}}
+	if content, ok := media[image]; ok && image != "" {
+		rich.Text.Value += fmt.Sprintf(`<img src="%s%s" alt="">`, moodlePluginFile, url.PathEscape(image))
+		rich.Files = []moodleFile{{Name: image, Path: "/", Encoding: "base64", Content: base64.StdEncoding.EncodeToString(content)}}
+	}
+
+	return rich
+}
+
+func formatFraction(fraction float64) string {
+	return strconv.FormatFloat(fraction, 'f', -1, 64)
+}
+
+func (moodleXML) Decode(name string, content []byte) (Bank, error) {
+	bank := Bank{Media: make(map[string][]byte)}
+
+	var quiz moodleQuiz
+	if err := xml.Unmarshal(content, &quiz); err != nil {
+		return bank, fmt.Errorf("invalid Moodle XML: %w", err)
+	}
+
+	category := ""
+	for _, item := range quiz.Questions {
+		if item.Type == "category" {
+			if item.Category != nil {
+				category = moodleCategoryName(item.Category.Text)
+			}
+			continue
+		}
+
+		bank.Questions = append(bank.Questions, moodleDecodeQuestion(item, category, &bank))
+	}
+
+	if len(bank.Questions) == 0 {
+		return bank, ErrEmptyBank
+	}
+
+	return bank, nil
+}
+
+func moodleDecodeQuestion(item moodleQuestion, category string, bank *Bank) Question {
+	question := Question{
+		Material:    category,
+		ScoringMode: ScoringAllOrNothing,
+	}
+	if item.Name != nil {
+		question.Code = strings.TrimSpace(item.Name.Text)
+	}
+	for _, tag := range item.Tags {
+		if tag := strings.TrimSpace(tag.Text); tag != "" {
+			question.Tags = append(question.Tags, tag)
+		}
+	}
+
+	decodeText := func(rich *moodleRichText) (string, string) {
+		if rich == nil {
+			return "", ""
+		}
+		text, image, err := moodleDecodeText(rich.Text.Value, rich.Files, bank)
+		if err != nil {
+			question.Errors = append(question.Errors, err.Error())
+		}
+		return text, image
+	}
+	question.Body, question.Image = decodeText(item.QuestionText)
+	question.Solution, question.SolutionImage = decodeText(item.GeneralFeedback)
+
+	answerOption := func(answer moodleAnswer) Option {
+		body, image, err := moodleDecodeText(answer.Text.Value, answer.Files, bank)
+		if err != nil {
+			question.Errors = append(question.Errors, err.Error())
+		}
+		return Option{Body: body, Image: image, IsCorrect: parseFraction(answer.Fraction) > 0}
+	}
+
+	switch item.Type {
+	case "multichoice", "multichoiceset":
+		question.Type = TypeMultipleAnswer
+		if item.Type == "multichoice" {
+			question.ScoringMode = ScoringPartial
+			if strings.TrimSpace(item.Single) != "false" && item.Single != "0" {
+				question.Type = TypeSingleChoice
+				question.ScoringMode = ScoringAllOrNothing
+			}
+		}
+		for _, answer := range item.Answers {
+			question.Options = append(question.Options, answerOption(answer))
+		}
+		// Single choice only keeps the full mark answer as correct
+		if question.Type == TypeSingleChoice {
+			for i, answer := range item.Answers {
+				question.Options[i].IsCorrect = parseFraction(answer.Fraction) >= 100
+			}
+		}
+	case "truefalse":
+		question.Type = TypeTrueFalse
+		isTrue := false
+		for _, answer := range item.Answers {
+			if strings.EqualFold(strings.TrimSpace(answer.Text.Value), "true") {
+				isTrue = parseFraction(answer.Fraction) > 0
+			}
+		}
+		question.Options = trueFalseOptions(isTrue)
+	case "numerical":
+		question.Type = TypeNumeric
+		for _, answer := range item.Answers {
+			value, err := strconv.ParseFloat(strings.TrimSpace(answer.Text.Value), 64)
+			if err != nil || parseFraction(answer.Fraction) < 100 {
+				continue
+			}
+			question.NumericAnswer = &value
+			question.NumericTolerance, _ = strconv.ParseFloat(strings.TrimSpace(answer.Tolerance), 64)
+			break
+		}
+		if question.NumericAnswer == nil {
+			question.Errors = append(question.Errors, "numerical question has no full mark answer")
+		}
+	case "shortanswer":
+		question.Type = TypeShortText
+		question.CaseSensitive = strings.TrimSpace(item.UseCase) == "1"
+		for _, answer := range item.Answers {
+			if parseFraction(answer.Fraction) > 0 {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, strings.TrimSpace(answer.Text.Value))
+			}
+		}
+	case "ordering":
+		question.Type = TypeOrdering
+		if strings.TrimSpace(item.GradingType) != "-1" {
+			question.ScoringMode = ScoringPartial
+		}
+		answers := append([]moodleAnswer{}, item.Answers...)
+		sort.SliceStable(answers, func(i, j int) bool {
+			return parseFraction(answers[i].Fraction) < parseFraction(answers[j].Fraction)
+		})
+		for _, answer := range answers {
+			option := answerOption(answer)
+			option.IsCorrect = true
+			question.Options = append(question.Options, option)
+		}
+	case "matching":
+		question.Type = TypeMatching
+		question.ScoringMode = ScoringPartial
+		for _, subquestion := range item.Subquestions {
+			body, image, err := moodleDecodeText(subquestion.Text.Value, subquestion.Files, bank)
+			if err != nil {
+				question.Errors = append(question.Errors, err.Error())
+			}
+			// Subquestion without text is an extra wrong answer, which is not supported
+			if body == "" && image == "" {
+				continue
+			}
+			question.Options = append(question.Options, Option{Body: body, Image: image, IsCorrect: true, MatchBody: strings.TrimSpace(subquestion.Answer.Text)})
+		}
+	default:
+		question.Errors = append(question.Errors, fmt.Sprintf("question type %s is not supported", item.Type))
+	}
+
+	return question
+}
+
+// moodleDecodeText stores the embedded files into the bank and takes the first embedded
+// image out of the text
+func moodleDecodeText(text string, files []moodleFile, bank *Bank) (string, string, error) {
+	names := map[string]string{}
+	for _, file := range files {
+		content, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(file.Content), ""))
+		if err != nil {
+			return text, "", fmt.Errorf("file %s is not valid base64", file.Name)
+		}
+		names[file.Name] = bank.AddMedia(file.Name, content)
+	}
+
+	image := ""
+	if match := moodleImagePattern.FindStringSubmatchIndex(text); match != nil {
+		name, _ := url.PathUnescape(text[match[2]:match[3]])
+		stored, ok := names[name]
+		if !ok {
+			return strings.TrimSpace(text), "", fmt.Errorf("image %s is not embedded", name)
+		}
+		image = stored
+		text = text[:match[0]] + text[match[1]:]
+	}
+
+	return strings.TrimSpace(text), image, nil
+}
+
+// moodleCategoryName returns the last part of the category path
+func moodleCategoryName(category string) string {
+	parts := strings.Split(strings.TrimSpace(category), "/")
+	name := strings.TrimSpace(parts[len(parts)-1])
+	if strings.HasPrefix(name, "$") {
+		return ""
+	}
+
+	return name
+}
+
+func parseFraction(fraction string) float64 {
+	value, _ := strconv.ParseFloat(strings.TrimSpace(fraction), 64)
+	return value
+}
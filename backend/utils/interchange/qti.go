@@ -0,0 +1,753 @@
+package interchange
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// qti encodes the bank as IMS QTI 2.1 content package: a zip with imsmanifest.xml,
+// one assessmentItem per question under items/ and the images under media/.
+// Tags are written as LOM keywords of the item resource, the material as item label.
+type qti struct{}
+
+const (
+	qtiNamespace         = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiManifestNamespace = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	qtiLOMNamespace      = "http://ltsc.ieee.org/xsd/LOM"
+	qtiItemType          = "imsqti_item_xmlv2p1"
+	qtiManifestFile      = "imsmanifest.xml"
+
+	qtiTemplateMatchCorrect = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"
+	qtiTemplateMapResponse  = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"
+
+	qtiResponse = "RESPONSE"
+)
+
+type qtiManifest struct {
+	XMLName       xml.Name      `xml:"manifest"`
+	Xmlns         string        `xml:"xmlns,attr,omitempty"`
+	Identifier    string        `xml:"identifier,attr"`
+	Schema        string        `xml:"metadata>schema"`
+	SchemaVersion string        `xml:"metadata>schemaversion"`
+	Organizations string        `xml:"organizations"`
+	Resources     []qtiResource `xml:"resources>resource"`
+}
+
+type qtiResource struct {
+	Identifier string        `xml:"identifier,attr"`
+	Type       string        `xml:"type,attr"`
+	Href       string        `xml:"href,attr"`
+	LOM        *qtiLOM       `xml:"metadata>lom"`
+	Files      []qtiFileHref `xml:"file"`
+}
+
+type qtiLOM struct {
+	Xmlns    string       `xml:"xmlns,attr,omitempty"`
+	Keywords []qtiKeyword `xml:"general>keyword"`
+}
+
+type qtiKeyword struct {
+	String string `xml:"string"`
+}
+
+type qtiFileHref struct {
+	Href string `xml:"href,attr"`
+}
+
+type qtiItem struct {
+	XMLName             xml.Name                `xml:"assessmentItem"`
+	Xmlns               string                  `xml:"xmlns,attr,omitempty"`
+	Identifier          string                  `xml:"identifier,attr"`
+	Title               string                  `xml:"title,attr"`
+	Label               string                  `xml:"label,attr,omitempty"`
+	Adaptive            bool                    `xml:"adaptive,attr"`
+	TimeDependent       bool                    `xml:"timeDependent,attr"`
+	ResponseDeclaration qtiResponseDeclaration  `xml:"responseDeclaration"`
+	OutcomeDeclarations []qtiOutcomeDeclaration `xml:"outcomeDeclaration"`
+	ItemBody            qtiItemBody             `xml:"itemBody"`
+	ResponseProcessing  *qtiResponseProcessing  `xml:"responseProcessing"`
+	ModalFeedbacks      []qtiContent            `xml:"modalFeedback"`
+}
+
+type qtiResponseDeclaration struct {
+	Identifier      string      `xml:"identifier,attr"`
+	Cardinality     string      `xml:"cardinality,attr"`
+	BaseType        string      `xml:"baseType,attr"`
+	CorrectResponse *qtiValues  `xml:"correctResponse"`
+	Mapping         *qtiMapping `xml:"mapping"`
+}
+
+type qtiValues struct {
+	Values []string `xml:"value"`
+}
+
+type qtiMapping struct {
+	LowerBound   *float64      `xml:"lowerBound,attr,omitempty"`
+	UpperBound   *float64      `xml:"upperBound,attr,omitempty"`
+	DefaultValue float64       `xml:"defaultValue,attr"`
+	Entries      []qtiMapEntry `xml:"mapEntry"`
+}
+
+type qtiMapEntry struct {
+	MapKey        string  `xml:"mapKey,attr"`
+	MappedValue   float64 `xml:"mappedValue,attr"`
+	CaseSensitive bool    `xml:"caseSensitive,attr"`
+}
+
+type qtiOutcomeDeclaration struct {
+	Identifier  string `xml:"identifier,attr"`
+	Cardinality string `xml:"cardinality,attr"`
+	BaseType    string `xml:"baseType,attr"`
+}
+
+type qtiItemBody struct {
+	Blocks            []qtiContent          `xml:"div"`
+	ChoiceInteraction *qtiChoiceInteraction `xml:"choiceInteraction"`
+	OrderInteraction  *qtiChoiceInteraction `xml:"orderInteraction"`
+	MatchInteraction  *qtiMatchInteraction  `xml:"matchInteraction"`
+	Entry             *qtiEntryParagraph    `xml:"p"`
+	// Text and first image of the body, filled when decoding
+	text  string
+	image string
+}
+
+type qtiChoiceInteraction struct {
+	ResponseIdentifier string      `xml:"responseIdentifier,attr"`
+	Class              string      `xml:"class,attr,omitempty"`
+	Shuffle            bool        `xml:"shuffle,attr"`
+	MaxChoices         *int        `xml:"maxChoices,attr,omitempty"`
+	Prompt             string      `xml:"prompt,omitempty"`
+	Choices            []qtiChoice `xml:"simpleChoice"`
+}
+
+type qtiMatchInteraction struct {
+	ResponseIdentifier string        `xml:"responseIdentifier,attr"`
+	Class              string        `xml:"class,attr,omitempty"`
+	Shuffle            bool          `xml:"shuffle,attr"`
+	MaxAssociations    int           `xml:"maxAssociations,attr"`
+	Prompt             string        `xml:"prompt,omitempty"`
+	Sets               []qtiMatchSet `xml:"simpleMatchSet"`
+}
+
+type qtiMatchSet struct {
+	Choices []qtiChoice `xml:"simpleAssociableChoice"`
+}
+
+type qtiEntryParagraph struct {
+	TextEntry qtiTextEntry `xml:"textEntryInteraction"`
+}
+
+type qtiTextEntry struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+	Class              string `xml:"class,attr,omitempty"`
+	ExpectedLength     int    `xml:"expectedLength,attr,omitempty"`
+}
+
+type qtiChoice struct {
+	Identifier string  `xml:"identifier,attr"`
+	MatchMax   int     `xml:"matchMax,attr,omitempty"`
+	Text       string  `xml:",chardata"`
+	Img        *qtiImg `xml:"img"`
+}
+
+// qtiContent is a block of text with optional image, used for the body and the solution
+type qtiContent struct {
+	OutcomeIdentifier string  `xml:"outcomeIdentifier,attr,omitempty"`
+	Identifier        string  `xml:"identifier,attr,omitempty"`
+	ShowHide          string  `xml:"showHide,attr,omitempty"`
+	Text              string  `xml:",chardata"`
+	Img               *qtiImg `xml:"img"`
+}
+
+type qtiImg struct {
+	Src string `xml:"src,attr"`
+	Alt string `xml:"alt,attr"`
+}
+
+type qtiResponseProcessing struct {
+	Template  string             `xml:"template,attr,omitempty"`
+	Condition *qtiToleranceCheck `xml:"responseCondition"`
+}
+
+// qtiToleranceCheck scores numeric answer within the tolerance of the correct response
+type qtiToleranceCheck struct {
+	Equal     qtiEqual      `xml:"responseIf>equal"`
+	IfScore   qtiSetOutcome `xml:"responseIf>setOutcomeValue"`
+	ElseScore qtiSetOutcome `xml:"responseElse>setOutcomeValue"`
+}
+
+type qtiEqual struct {
+	ToleranceMode string `xml:"toleranceMode,attr"`
+	Tolerance     string `xml:"tolerance,attr,omitempty"`
+	Variable      qtiRef `xml:"variable"`
+	Correct       qtiRef `xml:"correct"`
+}
+
+type qtiRef struct {
+	Identifier string `xml:"identifier,attr"`
+}
+
+type qtiSetOutcome struct {
+	Identifier string       `xml:"identifier,attr"`
+	BaseValue  qtiBaseValue `xml:"baseValue"`
+}
+
+type qtiBaseValue struct {
+	BaseType string `xml:"baseType,attr"`
+	Value    string `xml:",chardata"`
+}
+
+func (b *qtiItemBody) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	text, image, err := readContent(d, func(t xml.StartElement) (bool, error) {
+		switch t.Name.Local {
+		case "choiceInteraction":
+			b.ChoiceInteraction = &qtiChoiceInteraction{}
+			return true, d.DecodeElement(b.ChoiceInteraction, &t)
+		case "orderInteraction":
+			b.OrderInteraction = &qtiChoiceInteraction{}
+			return true, d.DecodeElement(b.OrderInteraction, &t)
+		case "matchInteraction":
+			b.MatchInteraction = &qtiMatchInteraction{}
+			return true, d.DecodeElement(b.MatchInteraction, &t)
+		case "textEntryInteraction", "extendedTextInteraction":
+			b.Entry = &qtiEntryParagraph{}
+			return true, d.DecodeElement(&b.Entry.TextEntry, &t)
+		}
+		return false, nil
+	})
+	b.text, b.image = text, image
+
+	return err
+}
+
+func (c *qtiChoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	c.Identifier = attrValue(start, "identifier")
+	c.MatchMax, _ = strconv.Atoi(attrValue(start, "matchMax"))
+
+	text, image, err := readContent(d, nil)
+	c.Text = text
+	if image != "" {
+		c.Img = &qtiImg{Src: image}
+	}
+
+	return err
+}
+
+func (c *qtiContent) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	c.OutcomeIdentifier = attrValue(start, "outcomeIdentifier")
+	c.Identifier = attrValue(start, "identifier")
+	c.ShowHide = attrValue(start, "showHide")
+
+	text, image, err := readContent(d, nil)
+	c.Text = text
+	if image != "" {
+		c.Img = &qtiImg{Src: image}
+	}
+
+	return err
+}
+
+func (qti) Encode(bank Bank) (File, []string, error) {
+	manifest := qtiManifest{
+		Xmlns:         qtiManifestNamespace,
+		Identifier:    "MANIFEST-QUESTION-BANK",
+		Schema:        "QTIv2.1 Package",
+		SchemaVersion: "1.0.0",
+	}
+	items := []archiveFile{}
+	skipped := []string{}
+
+	for i, question := range bank.Questions {
+		code := fallbackCode(question.Code, i)
+		identifier := qtiIdentifier(code)
+
+		item, err := qtiEncodeItem(identifier, code, question)
+		if err != nil {
+			skipped = append(skipped, code)
+			continue
+		}
+
+		content, err := xml.MarshalIndent(item, "", "  ")
+		if err != nil {
+			return File{}, nil, err
+		}
+
+		href := "items/" + identifier + ".xml"
+		items = append(items, archiveFile{Name: href, Content: append([]byte(xml.Header), content...)})
+
+		resource := qtiResource{
+			Identifier: identifier,
+			Type:       qtiItemType,
+			Href:       href,
+			Files:      []qtiFileHref{{Href: href}},
+		}
+		for _, image := range []string{question.Image, question.SolutionImage} {
+			if image != "" {
+				resource.Files = append(resource.Files, qtiFileHref{Href: "media/" + image})
+			}
+		}
+		for _, option := range question.Options {
+			if option.Image != "" {
+				resource.Files = append(resource.Files, qtiFileHref{Href: "media/" + option.Image})
+			}
+		}
+		if len(question.Tags) > 0 {
+			resource.LOM = &qtiLOM{Xmlns: qtiLOMNamespace}
+			for _, tag := range question.Tags {
+				resource.LOM.Keywords = append(resource.LOM.Keywords, qtiKeyword{String: tag})
+			}
+		}
+		manifest.Resources = append(manifest.Resources, resource)
+	}
+
+	if len(items) == 0 {
+		return File{}, skipped, ErrEmptyBank
+	}
+
+	content, err := xml.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	files := append([]archiveFile{{Name: qtiManifestFile, Content: append([]byte(xml.Header), content...)}}, items...)
+	archive, err := writeZip(append(files, mediaFiles(bank, "media")...))
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	return File{Name: "question-bank-qti21.zip", ContentType: "application/zip", Content: archive}, skipped, nil
+}
+
+func qtiEncodeItem(identifier, code string, question Question) (qtiItem, error) {
+	item := qtiItem{
+		Xmlns:      qtiNamespace,
+		Identifier: identifier,
+		Title:      code,
+		Label:      question.Material,
+		OutcomeDeclarations: []qtiOutcomeDeclaration{
+			{Identifier: "SCORE", Cardinality: "single", BaseType: "float"},
+		},
+		ResponseProcessing: &qtiResponseProcessing{Template: qtiTemplateMatchCorrect},
+	}
+
+	item.ItemBody.Blocks = []qtiContent{{Text: question.Body}}
+	if question.Image != "" {
+		item.ItemBody.Blocks = append(item.ItemBody.Blocks, qtiContent{Img: &qtiImg{Src: "../media/" + question.Image}})
+	}
+
+	if question.Solution != "" || question.SolutionImage != "" {
+		solution := qtiContent{OutcomeIdentifier: "FEEDBACK", Identifier: "SOLUTION", ShowHide: "show", Text: question.Solution}
+		if question.SolutionImage != "" {
+			solution.Img = &qtiImg{Src: "../media/" + question.SolutionImage}
+		}
+		item.ModalFeedbacks = []qtiContent{solution}
+		item.OutcomeDeclarations = append(item.OutcomeDeclarations, qtiOutcomeDeclaration{Identifier: "FEEDBACK", Cardinality: "single", BaseType: "identifier"})
+	}
+
+	// The class of the interaction keeps the exact type and scoring mode for the round-trip
+	class := question.Type + " " + question.ScoringMode
+	partial := question.ScoringMode == ScoringPartial
+	response := qtiResponseDeclaration{Identifier: qtiResponse, BaseType: "identifier"}
+	correct := []string{}
+
+	choices := make([]qtiChoice, len(question.Options))
+	for i, option := range question.Options {
+		choices[i] = qtiChoice{Identifier: fmt.Sprintf("choice_%d", i+1), Text: option.Body}
+		if option.Image != "" {
+			choices[i].Img = &qtiImg{Src: "../media/" + option.Image}
+		}
+		if option.IsCorrect {
+			correct = append(correct, choices[i].Identifier)
+		}
+	}
+
+	switch question.Type {
+	case TypeSingleChoice, TypeTrueFalse:
+		maxChoices := 1
+		response.Cardinality = "single"
+		item.ItemBody.ChoiceInteraction = &qtiChoiceInteraction{ResponseIdentifier: qtiResponse, Class: class, MaxChoices: &maxChoices, Choices: choices}
+	case TypeMultipleAnswer:
+		maxChoices := 0
+		response.Cardinality = "multiple"
+		item.ItemBody.ChoiceInteraction = &qtiChoiceInteraction{ResponseIdentifier: qtiResponse, Class: class, MaxChoices: &maxChoices, Choices: choices}
+		if partial && len(correct) > 0 {
+			share := 1 / float64(len(correct))
+			response.Mapping = qtiBoundedMapping()
+			for i, option := range question.Options {
+				value := -share
+				if option.IsCorrect {
+					value = share
+				}
+				response.Mapping.Entries = append(response.Mapping.Entries, qtiMapEntry{MapKey: choices[i].Identifier, MappedValue: value})
+			}
+			item.ResponseProcessing.Template = qtiTemplateMapResponse
+		}
+	case TypeOrdering:
+		// Ordered responses can not be mapped in QTI, partial ordering is kept in the class only
+		response.Cardinality = "ordered"
+		correct = []string{}
+		for _, choice := range choices {
+			correct = append(correct, choice.Identifier)
+		}
+		item.ItemBody.OrderInteraction = &qtiChoiceInteraction{ResponseIdentifier: qtiResponse, Class: class, Shuffle: true, Choices: choices}
+	case TypeMatching:
+		response.Cardinality = "multiple"
+		response.BaseType = "directedPair"
+		correct = []string{}
+		left := qtiMatchSet{}
+		right := qtiMatchSet{}
+		for i, option := range question.Options {
+			source := qtiChoice{Identifier: fmt.Sprintf("left_%d", i+1), MatchMax: 1, Text: option.Body, Img: choices[i].Img}
+			target := qtiChoice{Identifier: fmt.Sprintf("right_%d", i+1), MatchMax: 1, Text: option.MatchBody}
+			left.Choices = append(left.Choices, source)
+			right.Choices = append(right.Choices, target)
+			correct = append(correct, source.Identifier+" "+target.Identifier)
+		}
+		item.ItemBody.MatchInteraction = &qtiMatchInteraction{ResponseIdentifier: qtiResponse, Class: class, Shuffle: true, MaxAssociations: len(question.Options), Sets: []qtiMatchSet{left, right}}
+		if partial && len(correct) > 0 {
+			response.Mapping = qtiBoundedMapping()
+			for _, pair := range correct {
+				response.Mapping.Entries = append(response.Mapping.Entries, qtiMapEntry{MapKey: pair, MappedValue: 1 / float64(len(correct))})
+			}
+			item.ResponseProcessing.Template = qtiTemplateMapResponse
+		}
+	case TypeNumeric:
+		if question.NumericAnswer == nil {
+			return item, fmt.Errorf("numeric question %s has no answer", code)
+		}
+		response.Cardinality = "single"
+		response.BaseType = "float"
+		correct = []string{strconv.FormatFloat(*question.NumericAnswer, 'f', -1, 64)}
+		item.ItemBody.Entry = &qtiEntryParagraph{TextEntry: qtiTextEntry{ResponseIdentifier: qtiResponse, Class: class, ExpectedLength: 10}}
+		if question.NumericTolerance > 0 {
+			tolerance := strconv.FormatFloat(question.NumericTolerance, 'f', -1, 64)
+			item.ResponseProcessing = &qtiResponseProcessing{Condition: &qtiToleranceCheck{
+				Equal: qtiEqual{
+					ToleranceMode: "absolute",
+					Tolerance:     tolerance + " " + tolerance,
+					Variable:      qtiRef{Identifier: qtiResponse},
+					Correct:       qtiRef{Identifier: qtiResponse},
+				},
+				IfScore:   qtiSetOutcome{Identifier: "SCORE", BaseValue: qtiBaseValue{BaseType: "float", Value: "1"}},
+				ElseScore: qtiSetOutcome{Identifier: "SCORE", BaseValue: qtiBaseValue{BaseType: "float", Value: "0"}},
+			}}
+		}
+	case TypeShortText:
+		if len(question.AcceptedAnswers) == 0 {
+			return item, fmt.Errorf("short text question %s has no accepted answer", code)
+		}
+		response.Cardinality = "single"
+		response.BaseType = "string"
+		correct = []string{question.AcceptedAnswers[0]}
+		response.Mapping = &qtiMapping{}
+		for _, answer := range question.AcceptedAnswers {
+			response.Mapping.Entries = append(response.Mapping.Entries, qtiMapEntry{MapKey: answer, MappedValue: 1, CaseSensitive: question.CaseSensitive})
+		}
+		item.ItemBody.Entry = &qtiEntryParagraph{TextEntry: qtiTextEntry{ResponseIdentifier: qtiResponse, Class: class, ExpectedLength: 20}}
+		item.ResponseProcessing.Template = qtiTemplateMapResponse
+	default:
+		return item, fmt.Errorf("unknown question type %s", question.Type)
+	}
+
+	response.CorrectResponse = &qtiValues{Values: correct}
+	item.ResponseDeclaration = response
+
+	return item, nil
+}
+
+func qtiBoundedMapping() *qtiMapping {
+	lower, upper := 0.0, 1.0
+	return &qtiMapping{LowerBound: &lower, UpperBound: &upper}
+}
+
+// qtiIdentifier turns the code into a valid QTI identifier
+func qtiIdentifier(code string) string {
+	identifier := sanitizeMediaName(code)
+	if identifier == "" || !(identifier[0] >= 'a' && identifier[0] <= 'z' || identifier[0] >= 'A' && identifier[0] <= 'Z' || identifier[0] == '_') {
+		identifier = "item_" + identifier
+	}
+
+	return identifier
+}
+
+func (qti) Decode(name string, content []byte) (Bank, error) {
+	bank := Bank{Media: make(map[string][]byte)}
+
+	// A single item file without package
+	if !isZip(content) {
+		var item qtiItem
+		if err := xml.Unmarshal(content, &item); err != nil {
+			return bank, fmt.Errorf("invalid QTI item: %w", err)
+		}
+		bank.Questions = append(bank.Questions, qtiDecodeItem(item, "", nil, func(string) (string, bool) {
+			return "", false
+		}))
+		return bank, nil
+	}
+
+	files, err := readZip(content)
+	if err != nil {
+		return bank, err
+	}
+
+	type itemRef struct {
+		href string
+		tags []string
+	}
+	refs := []itemRef{}
+
+	if data, ok := files[qtiManifestFile]; ok {
+		var manifest qtiManifest
+		if err := xml.Unmarshal(data, &manifest); err != nil {
+			return bank, fmt.Errorf("invalid %s: %w", qtiManifestFile, err)
+		}
+		for _, resource := range manifest.Resources {
+			if !strings.HasPrefix(resource.Type, "imsqti_item") {
+				continue
+			}
+			ref := itemRef{href: cleanPath(resource.Href)}
+			if resource.LOM != nil {
+				for _, keyword := range resource.LOM.Keywords {
+					if keyword := strings.TrimSpace(keyword.String); keyword != "" {
+						ref.tags = append(ref.tags, keyword)
+					}
+				}
+			}
+			refs = append(refs, ref)
+		}
+	} else {
+		// Package without manifest, every item file is taken
+		for name, data := range files {
+			if strings.HasSuffix(strings.ToLower(name), ".xml") && rootElement(data) == "assessmentItem" {
+				refs = append(refs, itemRef{href: name})
+			}
+		}
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].href < refs[j].href
+		})
+	}
+
+	for _, ref := range refs {
+		data, ok := files[ref.href]
+		if !ok {
+			bank.Questions = append(bank.Questions, Question{Code: ref.href, Errors: []string{fmt.Sprintf("item %s is missing from the package", ref.href)}})
+			continue
+		}
+
+		var item qtiItem
+		if err := xml.Unmarshal(data, &item); err != nil {
+			bank.Questions = append(bank.Questions, Question{Code: ref.href, Errors: []string{fmt.Sprintf("invalid item %s: %s", ref.href, err.Error())}})
+			continue
+		}
+
+		bank.Questions = append(bank.Questions, qtiDecodeItem(item, path.Dir(ref.href), ref.tags, func(src string) (string, bool) {
+			data, ok := files[src]
+			if ok {
+				bank.Media[src] = data
+			}
+			return src, ok
+		}))
+	}
+
+	if len(bank.Questions) == 0 {
+		return bank, ErrEmptyBank
+	}
+
+	return bank, nil
+}
+
+// qtiDecodeItem maps the item to question. media resolves the package path of an image
+// into its media name and reports whether the image exists.
+func qtiDecodeItem(item qtiItem, dir string, tags []string, media func(string) (string, bool)) Question {
+	question := Question{
+		Code:        item.Title,
+		Material:    strings.TrimSpace(item.Label),
+		Body:        item.ItemBody.text,
+		ScoringMode: ScoringAllOrNothing,
+		Tags:        tags,
+	}
+	if question.Code == "" {
+		question.Code = item.Identifier
+	}
+
+	image := func(src string) string {
+		if src == "" {
+			return ""
+		}
+		resolved, ok := media(cleanPath(path.Join(dir, src)))
+		if !ok {
+			question.Errors = append(question.Errors, fmt.Sprintf("image %s is missing from the package", src))
+			return ""
+		}
+		return resolved
+	}
+	question.Image = image(item.ItemBody.image)
+
+	for _, feedback := range item.ModalFeedbacks {
+		question.Solution = strings.TrimSpace(question.Solution + "\n" + feedback.Text)
+		if feedback.Img != nil && question.SolutionImage == "" {
+			question.SolutionImage = image(feedback.Img.Src)
+		}
+	}
+
+	response := item.ResponseDeclaration
+	correct := map[string]bool{}
+	correctValues := []string{}
+	if response.CorrectResponse != nil {
+		for _, value := range response.CorrectResponse.Values {
+			value = strings.TrimSpace(value)
+			correct[value] = true
+			correctValues = append(correctValues, value)
+		}
+	}
+	mapped := response.Mapping != nil && len(response.Mapping.Entries) > 0
+
+	body := item.ItemBody
+	var class, prompt string
+	switch {
+	case body.ChoiceInteraction != nil:
+		class, prompt = body.ChoiceInteraction.Class, body.ChoiceInteraction.Prompt
+		question.Type = TypeSingleChoice
+		if response.Cardinality == "multiple" {
+			question.Type = TypeMultipleAnswer
+		}
+		if mapped {
+			question.ScoringMode = ScoringPartial
+		}
+		for _, choice := range body.ChoiceInteraction.Choices {
+			option := Option{Body: choice.Text, IsCorrect: correct[choice.Identifier]}
+			if choice.Img != nil {
+				option.Image = image(choice.Img.Src)
+			}
+			if !option.IsCorrect && mapped {
+				option.IsCorrect = qtiMappedValue(response.Mapping, choice.Identifier) > 0
+			}
+			question.Options = append(question.Options, option)
+		}
+	case body.OrderInteraction != nil:
+		class, prompt = body.OrderInteraction.Class, body.OrderInteraction.Prompt
+		question.Type = TypeOrdering
+		choices := map[string]qtiChoice{}
+		for _, choice := range body.OrderInteraction.Choices {
+			choices[choice.Identifier] = choice
+		}
+		order := correctValues
+		if len(order) == 0 {
+			for _, choice := range body.OrderInteraction.Choices {
+				order = append(order, choice.Identifier)
+			}
+		}
+		for _, identifier := range order {
+			choice, ok := choices[identifier]
+			if !ok {
+				question.Errors = append(question.Errors, fmt.Sprintf("correct response %s does not refer to any choice", identifier))
+				continue
+			}
+			option := Option{Body: choice.Text, IsCorrect: true}
+			if choice.Img != nil {
+				option.Image = image(choice.Img.Src)
+			}
+			question.Options = append(question.Options, option)
+		}
+	case body.MatchInteraction != nil:
+		class, prompt = body.MatchInteraction.Class, body.MatchInteraction.Prompt
+		question.Type = TypeMatching
+		if mapped {
+			question.ScoringMode = ScoringPartial
+		}
+		if len(body.MatchInteraction.Sets) != 2 {
+			question.Errors = append(question.Errors, "match interaction must have two match sets")
+			break
+		}
+		targets := map[string]string{}
+		for _, choice := range body.MatchInteraction.Sets[1].Choices {
+			targets[choice.Identifier] = choice.Text
+		}
+		pairs := map[string]string{}
+		for _, value := range correctValues {
+			if pair := strings.Fields(value); len(pair) == 2 {
+				pairs[pair[0]] = targets[pair[1]]
+			}
+		}
+		for _, choice := range body.MatchInteraction.Sets[0].Choices {
+			option := Option{Body: choice.Text, IsCorrect: true, MatchBody: pairs[choice.Identifier]}
+			if choice.Img != nil {
+				option.Image = image(choice.Img.Src)
+			}
+			question.Options = append(question.Options, option)
+		}
+	case body.Entry != nil:
+		class = body.Entry.TextEntry.Class
+		switch response.BaseType {
+		case "float", "integer":
+			question.Type = TypeNumeric
+			if len(correctValues) == 0 {
+				question.Errors = append(question.Errors, "numeric item has no correct response")
+				break
+			}
+			value, err := strconv.ParseFloat(correctValues[0], 64)
+			if err != nil {
+				question.Errors = append(question.Errors, fmt.Sprintf("correct response %q is not a number", correctValues[0]))
+				break
+			}
+			question.NumericAnswer = &value
+			if rp := item.ResponseProcessing; rp != nil && rp.Condition != nil {
+				if tolerance := strings.Fields(rp.Condition.Equal.Tolerance); len(tolerance) > 0 && rp.Condition.Equal.ToleranceMode == "absolute" {
+					question.NumericTolerance, _ = strconv.ParseFloat(tolerance[0], 64)
+				}
+			}
+		default:
+			question.Type = TypeShortText
+			seen := map[string]bool{}
+			if mapped {
+				question.CaseSensitive = response.Mapping.Entries[0].CaseSensitive
+				for _, entry := range response.Mapping.Entries {
+					if entry.MappedValue > 0 && !seen[entry.MapKey] {
+						seen[entry.MapKey] = true
+						question.AcceptedAnswers = append(question.AcceptedAnswers, entry.MapKey)
+					}
+				}
+			}
+			for _, value := range correctValues {
+				if !seen[value] {
+					seen[value] = true
+					question.AcceptedAnswers = append(question.AcceptedAnswers, value)
+				}
+			}
+		}
+	default:
+		question.Errors = append(question.Errors, "item has no supported interaction")
+	}
+
+	// Items exported by us keep the exact type and scoring mode in the interaction class
+	for _, token := range strings.Fields(class) {
+		switch token {
+		case TypeTrueFalse:
+			if question.Type == TypeSingleChoice {
+				question.Type = TypeTrueFalse
+			}
+		case ScoringPartial, ScoringAllOrNothing:
+			question.ScoringMode = token
+		}
+	}
+
+	if prompt = strings.TrimSpace(prompt); prompt != "" && !strings.Contains(question.Body, prompt) {
+		question.Body = strings.TrimSpace(question.Body + "\n" + prompt)
+	}
+
+	return question
+}
+
+func qtiMappedValue(mapping *qtiMapping, key string) float64 {
+	for _, entry := range mapping.Entries {
+		if entry.MapKey == key {
+			return entry.MappedValue
+		}
+	}
+
+	return 0
+}
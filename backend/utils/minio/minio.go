@@ -1,6 +1,7 @@
 package minio
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -32,6 +33,10 @@ type MinioStorageContract interface {
 	GetTemporaryPublicUrl(filePath string) (*url.URL, error)
 	// Delete file from bucket
 	DeleteFile(filepath string) error
+	// Read content of file from bucket
+	GetObject(filePath string) ([]byte, error)
+	// Store content as file in bucket
+	PutObject(filePath string, content []byte, contentType string) error
 }
 
 func NewMinioStorage(endpoint, accessKeyID, secretAccessKey, bucket string, useSSL bool) MinioStorageContract {
@@ -135,3 +140,32 @@ func (m *minioStorage) DeleteFile(filepath string) error {
 	err = client.RemoveObject(context.Background(), m.BucketName, filepath, minio.RemoveObjectOptions{})
 	return err
 }
+
+func (m *minioStorage) GetObject(filePath string) ([]byte, error) {
+	client, err := m.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := client.GetObject(context.Background(), m.BucketName, filePath, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return io.ReadAll(object)
+}
+
+func (m *minioStorage) PutObject(filePath string, content []byte, contentType string) error {
+	client, err := m.Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutObject(context.Background(), m.BucketName, filePath, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		logrus.Error(err)
+	}
+
+	return err
+}
@@ -0,0 +1,80 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// Keyset pagination walks a fixed order, so it can not be sorted by client
+	ErrSortNotSupported = errors.New("sort is not supported with cursor pagination")
+)
+
+// position is the sort key and ID of the last row of a page
+type position struct {
+	Key json.RawMessage `json:"k"`
+	ID  int             `json:"id"`
+}
+
+// Encode makes an opaque cursor pointing after the row with the sort key and ID
+func Encode(key interface{}, id int) (string, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(position{Key: raw, ID: id})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode reads the cursor made by Encode into key, which must point to the type of the encoded key
+func Decode(cursor string, key interface{}) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	var p position
+	if err := json.Unmarshal(data, &p); err != nil || p.ID <= 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(p.Key, key); err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	return p.ID, nil
+}
+
+// Limit is the page size, bounded like offset pagination
+func Limit(limit int) int {
+	switch {
+	case limit > 100:
+		return 100
+	case limit <= 0:
+		return 10
+	}
+
+	return limit
+}
+
+// Scope pages rows ordered descending by key column then ID column, starting after the row of
+// key and id. Zero id is the first page. One more row than limit is fetched to tell whether there
+// is a next page.
+func Scope(keyColumn, idColumn string, key interface{}, id, limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id > 0 {
+			db = db.Where("("+keyColumn+", "+idColumn+") < (?, ?)", key, id)
+		}
+
+		return db.Order(keyColumn + " desc, " + idColumn + " desc").Limit(Limit(limit) + 1)
+	}
+}
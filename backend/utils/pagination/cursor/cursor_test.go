@@ -0,0 +1,49 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	at := time.Date(2024, 3, 1, 8, 30, 0, 123, time.UTC)
+
+	c, err := Encode(at, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var key time.Time
+	id, err := Decode(c, &key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 || !key.Equal(at) {
+		t.Errorf("expected %v 42, got %v %d", at, key, id)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	point, _ := Encode(10, 3)
+
+	for name, c := range map[string]string{
+		"not base64":   "%%%",
+		"not json":     "bm90IGpzb24",
+		"without id":   "eyJrIjoxMH0",
+		"another type": point,
+	} {
+		var key time.Time
+		if _, err := Decode(c, &key); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: expected invalid cursor, got %v", name, err)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	for limit, expected := range map[int]int{0: 10, -1: 10, 25: 25, 500: 100} {
+		if Limit(limit) != expected {
+			t.Errorf("limit %d: expected %d, got %d", limit, expected, Limit(limit))
+		}
+	}
+}
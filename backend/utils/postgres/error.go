@@ -18,3 +18,9 @@ func TranslatePostgresError(err error) int {
 
 	return httpStatus
 }
+
+// Check whether error is caused by unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pgError *pgconn.PgError
+	return errors.As(err, &pgError) && pgError.Code == "23505"
+}
@@ -0,0 +1,74 @@
+// Package questioncode allocates question codes such as kq12 from a counter per prefix.
+package questioncode
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DefaultPrefix is used by materials without their own prefix
+const DefaultPrefix = "kq"
+
+// Prefixes are letters only so the number of a code can always be told apart
+var prefixPattern = regexp.MustCompile(`^[a-z]{1,16}$`)
+
+var codePattern = regexp.MustCompile(`^([a-z]+)([0-9]+)$`)
+
+// Normalize returns the prefix in lower case, or the default prefix when empty
+func Normalize(prefix string) string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return DefaultPrefix
+	}
+
+	return prefix
+}
+
+// ValidPrefix tells whether the prefix can be used for question codes
+func ValidPrefix(prefix string) bool {
+	return prefixPattern.MatchString(Normalize(prefix))
+}
+
+// Format builds the code of the n-th question of the prefix
+func Format(prefix string, n int64) string {
+	return fmt.Sprintf("%s%d", Normalize(prefix), n)
+}
+
+// Parse splits a code into its prefix and number
+func Parse(code string) (string, int64, bool) {
+	match := codePattern.FindStringSubmatch(strings.ToLower(code))
+	if match == nil {
+		return "", 0, false
+	}
+
+	n, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return match[1], n, true
+}
+
+// Next allocates the next code of the prefix. The counter row stays locked until the
+// transaction of db ends, so concurrent creates never get the same code and numbers of
+// deleted questions are never given again.
+func Next(db *gorm.DB, prefix string) (string, error) {
+	prefix = Normalize(prefix)
+	if !ValidPrefix(prefix) {
+		return "", fmt.Errorf("invalid question code prefix %q", prefix)
+	}
+
+	var n int64
+	err := db.Raw(`INSERT INTO question_code_counters (prefix, last_value) VALUES (?, 1)
+		ON CONFLICT (prefix) DO UPDATE SET last_value = question_code_counters.last_value + 1
+		RETURNING last_value`, prefix).Scan(&n).Error
+	if err != nil {
+		return "", err
+	}
+
+	return Format(prefix, n), nil
+}
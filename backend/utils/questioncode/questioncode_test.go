@@ -0,0 +1,33 @@
+package questioncode
+
+import "testing"
+
+func TestFormatAndParse(t *testing.T) {
+	code := Format("", 12)
+	if code != "kq12" {
+		t.Fatalf("expected kq12, got %s", code)
+	}
+
+	prefix, n, ok := Parse(Format(" Math ", 7))
+	if !ok || prefix != "math" || n != 7 {
+		t.Errorf("expected math 7, got %s %d %v", prefix, n, ok)
+	}
+
+	if _, _, ok := Parse("kq"); ok {
+		t.Error("code without number must not be parsed")
+	}
+}
+
+func TestValidPrefix(t *testing.T) {
+	for prefix, valid := range map[string]bool{
+		"":                  true,
+		"fis":               true,
+		"m1":                false,
+		"kq-":               false,
+		"abcdefghijklmnopq": false,
+	} {
+		if ValidPrefix(prefix) != valid {
+			t.Errorf("prefix %q: expected valid %v", prefix, valid)
+		}
+	}
+}
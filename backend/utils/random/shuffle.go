@@ -0,0 +1,17 @@
+package random
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Shuffle randomize the order of n elements using swap, like math/rand.Shuffle but seeded by crypto/rand
+func Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			continue
+		}
+		swap(i, int(j.Int64()))
+	}
+}
@@ -0,0 +1,20 @@
+package random
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestShuffleKeepsElements(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(len(values), func(i, j int) {
+		values[i], values[j] = values[j], values[i]
+	})
+
+	sort.Ints(values)
+	for i, v := range values {
+		if v != i+1 {
+			t.Fatalf("expected shuffled values to keep every element, got %v", values)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package random
+
+import "testing"
+
+func TestGenerateToken(t *testing.T) {
+	a, err := GenerateToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateToken(32)
+
+	if len(a) != 43 || a == b {
+		t.Errorf("expected distinct 43 character tokens, got %q and %q", a, b)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	if got := HashToken("abc"); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("unexpected hash %s", got)
+	}
+}
@@ -1,22 +1,127 @@
 package random
 
 import (
+	"crypto/rand"
 	"errors"
-	"math/rand"
+	"math/big"
+	"strings"
+)
+
+// DefaultVoucherAlphabet leaves out characters which are easily mistyped (0/O, 1/I/L)
+const DefaultVoucherAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+var (
+	ErrVoucherTooShort     = errors.New("voucher length should be greater than 4")
+	ErrVoucherAlphabet     = errors.New("voucher alphabet should have at least 2 unique characters")
+	ErrVoucherInvalidChar  = errors.New("voucher contains character outside of the alphabet")
+	ErrVoucherInvalidCheck = errors.New("voucher checksum does not match")
 )
 
 func GenerateRandomVoucher(length int) (string, error) {
 	if length < 4 {
-		return "", errors.New("voucher length should be greater than 4")
+		return "", ErrVoucherTooShort
+	}
+
+	return randomString(length, "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+}
+
+// GenerateSecureVoucher generate voucher from crypto source with the given alphabet.
+// The last character is a Luhn mod N checksum, so the total length is length + 1.
+func GenerateSecureVoucher(length int, alphabet string) (string, error) {
+	if length < 4 {
+		return "", ErrVoucherTooShort
+	}
+
+	if err := validateAlphabet(alphabet); err != nil {
+		return "", err
 	}
 
-	charset := "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	voucher := ""
+	body, err := randomString(length, alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	check, err := checksum(body, alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	return body + string(alphabet[check]), nil
+}
 
+// ValidateVoucher check voucher characters and its checksum against the alphabet
+func ValidateVoucher(voucher string, alphabet string) error {
+	if len(voucher) < 5 {
+		return ErrVoucherTooShort
+	}
+
+	if err := validateAlphabet(alphabet); err != nil {
+		return err
+	}
+
+	body, last := voucher[:len(voucher)-1], voucher[len(voucher)-1:]
+	check, err := checksum(body, alphabet)
+	if err != nil {
+		return err
+	}
+
+	if string(alphabet[check]) != last {
+		return ErrVoucherInvalidCheck
+	}
+
+	return nil
+}
+
+func randomString(length int, charset string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(length)
+
+	max := big.NewInt(int64(len(charset)))
 	for i := 0; i < length; i++ {
-		randNum := rand.Intn(len(charset))
-		voucher += string(charset[randNum])
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(charset[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// checksum compute Luhn mod N check character index of input
+func checksum(input string, alphabet string) (int, error) {
+	n := len(alphabet)
+	factor := 2
+	sum := 0
+
+	for i := len(input) - 1; i >= 0; i-- {
+		codePoint := strings.IndexByte(alphabet, input[i])
+		if codePoint < 0 {
+			return 0, ErrVoucherInvalidChar
+		}
+
+		addend := factor * codePoint
+		addend = addend/n + addend%n
+		sum += addend
+
+		factor = 3 - factor
+	}
+
+	return (n - sum%n) % n, nil
+}
+
+func validateAlphabet(alphabet string) error {
+	if len(alphabet) < 2 {
+		return ErrVoucherAlphabet
+	}
+
+	seen := make(map[rune]bool, len(alphabet))
+	for _, c := range alphabet {
+		if c > 127 || seen[c] {
+			return ErrVoucherAlphabet
+		}
+		seen[c] = true
 	}
 
-	return voucher, nil
+	return nil
 }
@@ -17,3 +17,43 @@ func TestGenerenateVoucherTooShort(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGenerateSecureVoucherChecksum(t *testing.T) {
+	voucher, err := GenerateSecureVoucher(12, DefaultVoucherAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(voucher) != 13 {
+		t.Errorf("expected length 13, got %d", len(voucher))
+	}
+
+	if err := ValidateVoucher(voucher, DefaultVoucherAlphabet); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateVoucherTypo(t *testing.T) {
+	voucher, err := GenerateSecureVoucher(12, DefaultVoucherAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace one character with another character of the alphabet
+	typo := []byte(voucher)
+	if typo[3] == DefaultVoucherAlphabet[0] {
+		typo[3] = DefaultVoucherAlphabet[1]
+	} else {
+		typo[3] = DefaultVoucherAlphabet[0]
+	}
+
+	if err := ValidateVoucher(string(typo), DefaultVoucherAlphabet); err == nil {
+		t.Errorf("expected typo in %s to be detected", typo)
+	}
+}
+
+func TestGenerateSecureVoucherInvalidAlphabet(t *testing.T) {
+	if _, err := GenerateSecureVoucher(12, "AA"); err == nil {
+		t.Error("expected duplicated alphabet to be rejected")
+	}
+}
@@ -0,0 +1,132 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+)
+
+// Choice grade chosen options against the correct ones. Without partial credit
+// the chosen options must be exactly the correct ones. With partial credit every
+// wrong choice cancels one correct choice.
+func Choice(correct, chosen []int, partial bool) float32 {
+	if len(correct) == 0 {
+		return 0
+	}
+
+	isCorrect := make(map[int]bool, len(correct))
+	for _, id := range correct {
+		isCorrect[id] = true
+	}
+
+	hit, miss := 0, 0
+	seen := make(map[int]bool, len(chosen))
+	for _, id := range chosen {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if isCorrect[id] {
+			hit++
+		} else {
+			miss++
+		}
+	}
+
+	if !partial {
+		if hit == len(correct) && miss == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	credit := float32(hit-miss) / float32(len(correct))
+	if credit < 0 {
+		return 0
+	}
+
+	return credit
+}
+
+// Order grade the given order of items against the correct order. With partial
+// credit every item on its correct position counts.
+func Order(correct, given []int, partial bool) float32 {
+	if len(correct) == 0 || len(given) != len(correct) {
+		return 0
+	}
+
+	hit := 0
+	for i := range correct {
+		if correct[i] == given[i] {
+			hit++
+		}
+	}
+
+	if !partial {
+		if hit == len(correct) {
+			return 1
+		}
+		return 0
+	}
+
+	return float32(hit) / float32(len(correct))
+}
+
+// Match grade pairs of matched items, pairs maps every item to its chosen pair.
+// An item is matched correctly when it is paired with itself.
+func Match(items []int, pairs map[int]int, partial bool) float32 {
+	if len(items) == 0 {
+		return 0
+	}
+
+	hit := 0
+	for _, id := range items {
+		if match, ok := pairs[id]; ok && match == id {
+			hit++
+		}
+	}
+
+	if !partial {
+		if hit == len(items) {
+			return 1
+		}
+		return 0
+	}
+
+	return float32(hit) / float32(len(items))
+}
+
+// Numeric grade a numeric answer which is correct within the tolerance
+func Numeric(expected, tolerance, given float64) float32 {
+	if math.Abs(expected-given) <= math.Abs(tolerance)+1e-9 {
+		return 1
+	}
+
+	return 0
+}
+
+// Text grade a short text answer against accepted answers, ignoring surrounding and repeated spaces
+func Text(accepted []string, given string, caseSensitive bool) float32 {
+	given = NormalizeText(given, caseSensitive)
+	if given == "" {
+		return 0
+	}
+
+	for _, answer := range accepted {
+		if NormalizeText(answer, caseSensitive) == given {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// NormalizeText trim and collapse spaces of a text answer
+func NormalizeText(text string, caseSensitive bool) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+
+	return text
+}
@@ -0,0 +1,59 @@
+package scoring
+
+import "testing"
+
+func TestChoice(t *testing.T) {
+	correct := []int{1, 2, 3}
+
+	if credit := Choice(correct, []int{3, 2, 1}, false); credit != 1 {
+		t.Errorf("expected full credit, got %v", credit)
+	}
+
+	if credit := Choice(correct, []int{1, 2}, false); credit != 0 {
+		t.Errorf("expected no credit for incomplete answer, got %v", credit)
+	}
+
+	if credit := Choice(correct, []int{1, 2, 4}, true); credit != float32(1)/3 {
+		t.Errorf("expected one third credit, got %v", credit)
+	}
+
+	if credit := Choice(correct, []int{1, 4, 5}, true); credit != 0 {
+		t.Errorf("expected credit not to be negative, got %v", credit)
+	}
+}
+
+func TestOrderAndMatch(t *testing.T) {
+	if credit := Order([]int{1, 2, 3, 4}, []int{1, 2, 4, 3}, true); credit != 0.5 {
+		t.Errorf("expected half credit, got %v", credit)
+	}
+
+	if credit := Order([]int{1, 2, 3}, []int{1, 2}, true); credit != 0 {
+		t.Errorf("expected no credit for incomplete order, got %v", credit)
+	}
+
+	if credit := Match([]int{1, 2}, map[int]int{1: 1, 2: 1}, false); credit != 0 {
+		t.Errorf("expected no credit, got %v", credit)
+	}
+
+	if credit := Match([]int{1, 2}, map[int]int{1: 1, 2: 2}, false); credit != 1 {
+		t.Errorf("expected full credit, got %v", credit)
+	}
+}
+
+func TestNumericAndText(t *testing.T) {
+	if credit := Numeric(9.81, 0.01, 9.8); credit != 1 {
+		t.Errorf("expected answer within tolerance to be correct, got %v", credit)
+	}
+
+	if credit := Numeric(9.81, 0, 9.8); credit != 0 {
+		t.Errorf("expected answer outside tolerance to be wrong, got %v", credit)
+	}
+
+	if credit := Text([]string{"Ibu Kota", "Jakarta"}, "  ibu   kota ", false); credit != 1 {
+		t.Errorf("expected normalized text to be accepted, got %v", credit)
+	}
+
+	if credit := Text([]string{"Jakarta"}, "jakarta", true); credit != 0 {
+		t.Errorf("expected case sensitive text to be rejected, got %v", credit)
+	}
+}
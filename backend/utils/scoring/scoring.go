@@ -0,0 +1,63 @@
+package scoring
+
+const (
+	StatusCorrect = "correct"
+	StatusWrong   = "wrong"
+	StatusBlank   = "blank"
+	StatusPartial = "partial"
+)
+
+// Rule holds the point given for every answer status of a question
+type Rule struct {
+	Correct float32
+	Wrong   float32
+	Blank   float32
+}
+
+// Status resolve the answer status of a question
+func Status(answered, correct bool) string {
+	if !answered {
+		return StatusBlank
+	}
+
+	if correct {
+		return StatusCorrect
+	}
+
+	return StatusWrong
+}
+
+// Point returns the point of an answer status based on the rule
+func (r Rule) Point(status string) float32 {
+	switch status {
+	case StatusCorrect:
+		return r.Correct
+	case StatusWrong:
+		return r.Wrong
+	default:
+		return r.Blank
+	}
+}
+
+// StatusOf resolve the answer status from the credit (0..1) of an answer
+func StatusOf(answered bool, credit float32) string {
+	switch {
+	case !answered:
+		return StatusBlank
+	case credit >= 1:
+		return StatusCorrect
+	case credit > 0:
+		return StatusPartial
+	default:
+		return StatusWrong
+	}
+}
+
+// Score returns the point of an answer, partial answers get their share of the correct point
+func (r Rule) Score(status string, credit float32) float32 {
+	if status == StatusPartial {
+		return r.Correct * credit
+	}
+
+	return r.Point(status)
+}
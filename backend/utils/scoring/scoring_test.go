@@ -0,0 +1,33 @@
+package scoring
+
+import "testing"
+
+func TestRulePoint(t *testing.T) {
+	rule := Rule{Correct: 4, Wrong: -1, Blank: 0}
+
+	statuses := []string{
+		Status(true, true),
+		Status(true, true),
+		Status(true, false),
+		Status(false, false),
+	}
+
+	var total float32
+	for _, s := range statuses {
+		total += rule.Point(s)
+	}
+
+	if total != 7 {
+		t.Errorf("expected total 7, got %v", total)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	if Status(false, true) != StatusBlank {
+		t.Error("unanswered question must be blank")
+	}
+
+	if Status(true, false) != StatusWrong {
+		t.Error("answered question with false value must be wrong")
+	}
+}
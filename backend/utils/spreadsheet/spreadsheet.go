@@ -0,0 +1,60 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported spreadsheet format, use .csv or .xlsx")
+
+// ReadRows read every row of a CSV file or the first sheet of a XLSX file,
+// the format is chosen from the file name extension
+func ReadRows(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("spreadsheet has no sheet")
+		}
+
+		return f.GetRows(sheets[0])
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Header map lower cased column names of the header row to their index
+func Header(row []string) map[string]int {
+	header := make(map[string]int, len(row))
+	for i, column := range row {
+		header[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	return header
+}
+
+// Cell returns the trimmed value of a column in a row, empty when the column or the cell does not exist
+func Cell(header map[string]int, row []string, column string) string {
+	i, ok := header[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
@@ -0,0 +1,55 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadRowsCSV(t *testing.T) {
+	rows, err := ReadRows("questions.CSV", strings.NewReader("Body,Answer\n\"1 + 1 = ?\",A\nshort row\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+
+	header := Header(rows[0])
+	if got := Cell(header, rows[1], "body"); got != "1 + 1 = ?" {
+		t.Errorf("expected body cell, got %q", got)
+	}
+
+	if got := Cell(header, rows[2], "answer"); got != "" {
+		t.Errorf("expected missing cell to be empty, got %q", got)
+	}
+}
+
+func TestReadRowsXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"body", "answer"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{"2 + 2 = ?", "B"})
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := ReadRows("questions.xlsx", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 2 || rows[1][1] != "B" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestReadRowsUnsupported(t *testing.T) {
+	if _, err := ReadRows("questions.ods", strings.NewReader("")); err != ErrUnsupportedFormat {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+}